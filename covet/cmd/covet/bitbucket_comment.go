@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type bitbucketCommentOptions struct {
+	BitbucketEndpoint string
+	BitbucketToken    string
+	Server            bool   // true for Bitbucket Server or Data Center, false for Bitbucket Cloud
+	Owner             string // Bitbucket Cloud workspace or Bitbucket Server project key
+	Repo              string
+	PullRequestID     int
+	Body              string
+}
+
+func ensureAppBitbucketComment(ctx context.Context, options bitbucketCommentOptions) error {
+	client := newTokenClient(ctx, options.BitbucketToken)
+	if options.Server {
+		return ensureAppBitbucketServerComment(ctx, client, options)
+	}
+	return ensureAppBitbucketCloudComment(ctx, client, options)
+}
+
+type bitbucketCloudComment struct {
+	ID      int64                        `json:"id,omitempty"`
+	Content bitbucketCloudCommentContent `json:"content"`
+	Deleted bool                         `json:"deleted,omitempty"`
+}
+
+type bitbucketCloudCommentContent struct {
+	Raw string `json:"raw"`
+}
+
+type bitbucketCloudCommentPage struct {
+	Values []bitbucketCloudComment `json:"values"`
+	Next   string                  `json:"next"`
+}
+
+func ensureAppBitbucketCloudComment(ctx context.Context, client *http.Client, options bitbucketCommentOptions) error {
+	commentsURL := fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/comments",
+		options.BitbucketEndpoint, url.PathEscape(options.Owner), url.PathEscape(options.Repo), options.PullRequestID)
+
+	var comment *bitbucketCloudComment
+	for pageURL := commentsURL + "?pagelen=100"; pageURL != ""; {
+		var page bitbucketCloudCommentPage
+		_, err := doJSON(ctx, client, http.MethodGet, pageURL, nil, &page)
+		if err != nil {
+			return err
+		}
+		for i := range page.Values {
+			if !page.Values[i].Deleted && strings.HasPrefix(page.Values[i].Content.Raw, appCommentMarker) {
+				comment = &page.Values[i]
+			}
+		}
+		pageURL = page.Next
+	}
+
+	newComment := bitbucketCloudComment{
+		Content: bitbucketCloudCommentContent{Raw: appCommentMarker + options.Body},
+	}
+	var err error
+	if comment == nil {
+		_, err = doJSON(ctx, client, http.MethodPost, commentsURL, newComment, nil)
+	} else {
+		_, err = doJSON(ctx, client, http.MethodPut, fmt.Sprintf("%s/%d", commentsURL, comment.ID), newComment, nil)
+	}
+	return err
+}
+
+type bitbucketServerComment struct {
+	ID      int64  `json:"id,omitempty"`
+	Text    string `json:"text"`
+	Version int    `json:"version"`
+}
+
+type bitbucketServerActivity struct {
+	Action  string                  `json:"action"`
+	Comment *bitbucketServerComment `json:"comment"`
+}
+
+type bitbucketServerActivityPage struct {
+	Values        []bitbucketServerActivity `json:"values"`
+	IsLastPage    bool                      `json:"isLastPage"`
+	NextPageStart int                       `json:"nextPageStart"`
+}
+
+func ensureAppBitbucketServerComment(ctx context.Context, client *http.Client, options bitbucketCommentOptions) error {
+	pullRequestURL := fmt.Sprintf("%s/projects/%s/repos/%s/pull-requests/%d",
+		options.BitbucketEndpoint, url.PathEscape(options.Owner), url.PathEscape(options.Repo), options.PullRequestID)
+
+	var comment *bitbucketServerComment
+	for start, isLastPage := 0, false; !isLastPage; {
+		var page bitbucketServerActivityPage
+		_, err := doJSON(ctx, client, http.MethodGet, pullRequestURL+"/activities?start="+strconv.Itoa(start), nil, &page)
+		if err != nil {
+			return err
+		}
+		// activities are sorted newest first, so keep the first match
+		for _, activity := range page.Values {
+			if comment == nil && activity.Action == "COMMENTED" && activity.Comment != nil && strings.HasPrefix(activity.Comment.Text, appCommentMarker) {
+				comment = activity.Comment
+			}
+		}
+		start, isLastPage = page.NextPageStart, page.IsLastPage || comment != nil
+	}
+
+	commentsURL := pullRequestURL + "/comments"
+	newComment := bitbucketServerComment{Text: appCommentMarker + options.Body}
+	var err error
+	if comment == nil {
+		_, err = doJSON(ctx, client, http.MethodPost, commentsURL, newComment, nil)
+	} else {
+		newComment.Version = comment.Version
+		_, err = doJSON(ctx, client, http.MethodPut, fmt.Sprintf("%s/%d", commentsURL, comment.ID), newComment, nil)
+	}
+	return err
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsureAppBitbucketCloudComment(t *testing.T) {
+	t.Parallel()
+	created := false
+	updated := false
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "some-user", username)
+		assert.Equal(t, "some-app-password", password)
+		switch fmt.Sprintf("%s:%s", r.Method, r.URL.Path) {
+		// create new comment
+		case "GET:/repositories/workspace/repo/pullrequests/456/comments":
+			require.NoError(t, json.NewEncoder(w).Encode(bitbucketCloudCommentPage{
+				Values: []bitbucketCloudComment{
+					{ID: 1, Content: bitbucketCloudCommentContent{Raw: "<!-- covet -->\n\ndeleted"}, Deleted: true},
+				},
+			}))
+		case "POST:/repositories/workspace/repo/pullrequests/456/comments":
+			created = true
+			var comment bitbucketCloudComment
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&comment))
+			assert.Equal(t, bitbucketCloudComment{
+				Content: bitbucketCloudCommentContent{Raw: "<!-- covet -->\n\nsome body"},
+			}, comment)
+			w.WriteHeader(http.StatusCreated)
+
+		// update existing comment on the second page
+		case "GET:/repositories/workspace/repo/pullrequests/123/comments":
+			if r.URL.Query().Get("page") == "" {
+				require.NoError(t, json.NewEncoder(w).Encode(bitbucketCloudCommentPage{
+					Values: []bitbucketCloudComment{{ID: 1, Content: bitbucketCloudCommentContent{Raw: "some other comment"}}},
+					Next:   server.URL + "/repositories/workspace/repo/pullrequests/123/comments?pagelen=100&page=2",
+				}))
+			} else {
+				require.NoError(t, json.NewEncoder(w).Encode(bitbucketCloudCommentPage{
+					Values: []bitbucketCloudComment{{ID: 2, Content: bitbucketCloudCommentContent{Raw: "<!-- covet -->\n\nsome body"}}},
+				}))
+			}
+		case "PUT:/repositories/workspace/repo/pullrequests/123/comments/2":
+			updated = true
+			var comment bitbucketCloudComment
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&comment))
+			assert.Equal(t, bitbucketCloudComment{
+				Content: bitbucketCloudCommentContent{Raw: "<!-- covet -->\n\nsome other body"},
+			}, comment)
+		default:
+			t.Fatal("Unknown request method and path:", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+	// create new comment
+	err := ensureAppBitbucketComment(ctx, bitbucketCommentOptions{
+		BitbucketEndpoint: server.URL,
+		BitbucketToken:    "some-user:some-app-password",
+		Owner:             "workspace",
+		Repo:              "repo",
+		PullRequestID:     456,
+		Body:              "some body",
+	})
+	assert.NoError(t, err)
+	assert.True(t, created)
+	// update existing comment
+	err = ensureAppBitbucketComment(ctx, bitbucketCommentOptions{
+		BitbucketEndpoint: server.URL,
+		BitbucketToken:    "some-user:some-app-password",
+		Owner:             "workspace",
+		Repo:              "repo",
+		PullRequestID:     123,
+		Body:              "some other body",
+	})
+	assert.NoError(t, err)
+	assert.True(t, updated)
+}
+
+func TestEnsureAppBitbucketServerComment(t *testing.T) {
+	t.Parallel()
+	created := false
+	updated := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer some-token", r.Header.Get("Authorization"))
+		switch fmt.Sprintf("%s:%s", r.Method, r.URL.Path) {
+		// create new comment
+		case "GET:/rest/api/1.0/projects/KEY/repos/repo/pull-requests/456/activities":
+			require.NoError(t, json.NewEncoder(w).Encode(bitbucketServerActivityPage{
+				Values: []bitbucketServerActivity{
+					{Action: "OPENED"},
+				},
+				IsLastPage: true,
+			}))
+		case "POST:/rest/api/1.0/projects/KEY/repos/repo/pull-requests/456/comments":
+			created = true
+			var comment bitbucketServerComment
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&comment))
+			assert.Equal(t, bitbucketServerComment{
+				Text: "<!-- covet -->\n\nsome body",
+			}, comment)
+			w.WriteHeader(http.StatusCreated)
+
+		// update existing comment on the second page
+		case "GET:/rest/api/1.0/projects/KEY/repos/repo/pull-requests/123/activities":
+			switch r.URL.Query().Get("start") {
+			case "0":
+				require.NoError(t, json.NewEncoder(w).Encode(bitbucketServerActivityPage{
+					Values: []bitbucketServerActivity{
+						{Action: "COMMENTED", Comment: &bitbucketServerComment{ID: 1, Text: "some other comment"}},
+					},
+					NextPageStart: 1,
+				}))
+			case "1":
+				require.NoError(t, json.NewEncoder(w).Encode(bitbucketServerActivityPage{
+					Values: []bitbucketServerActivity{
+						{Action: "COMMENTED", Comment: &bitbucketServerComment{ID: 2, Text: "<!-- covet -->\n\nsome body", Version: 3}},
+					},
+					NextPageStart: 2,
+				}))
+			default:
+				t.Fatal("Unexpected page start:", r.URL.Query().Get("start"))
+			}
+		case "PUT:/rest/api/1.0/projects/KEY/repos/repo/pull-requests/123/comments/2":
+			updated = true
+			var comment bitbucketServerComment
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&comment))
+			assert.Equal(t, bitbucketServerComment{
+				Text:    "<!-- covet -->\n\nsome other body",
+				Version: 3,
+			}, comment)
+		default:
+			t.Fatal("Unknown request method and path:", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+	// create new comment
+	err := ensureAppBitbucketComment(ctx, bitbucketCommentOptions{
+		BitbucketEndpoint: server.URL + "/rest/api/1.0",
+		BitbucketToken:    "some-token",
+		Server:            true,
+		Owner:             "KEY",
+		Repo:              "repo",
+		PullRequestID:     456,
+		Body:              "some body",
+	})
+	assert.NoError(t, err)
+	assert.True(t, created)
+	// update existing comment
+	err = ensureAppBitbucketComment(ctx, bitbucketCommentOptions{
+		BitbucketEndpoint: server.URL + "/rest/api/1.0",
+		BitbucketToken:    "some-token",
+		Server:            true,
+		Owner:             "KEY",
+		Repo:              "repo",
+		PullRequestID:     123,
+		Body:              "some other body",
+	})
+	assert.NoError(t, err)
+	assert.True(t, updated)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/johnstarich/go/covet"
+	"golang.org/x/oauth2"
+)
+
+// appCommentMarker prefixes all summary comments, so later runs find and update them instead of posting new ones
+const appCommentMarker = "<!-- covet -->\n\n"
+
+// ensureAppComments creates or updates summary comments on all configured providers, plus inline GitHub review comments if enabled.
+// Invalid issue URLs return an error, but failed API calls are only logged to 'errOutput'.
+func ensureAppComments(ctx context.Context, cov *covet.Covet, args Args, errOutput io.Writer, body string) error {
+	if args.GitHubToken != "" {
+		issue, err := parseIssueURLFlag("gh-issue", args.GitHubIssue, "GitHub issue or pull request", "github.com/org/repo/pull/123", providerGitHub)
+		if err != nil {
+			return err
+		}
+		err = ensureAppGitHubComment(ctx, gitHubCommentOptions{
+			GitHubEndpoint: args.GitHubEndpoint,
+			GitHubToken:    args.GitHubToken,
+			RepoOwner:      issue.Owner,
+			Repo:           issue.Repo,
+			IssueNumber:    issue.Number,
+			Body:           body,
+		})
+		if err != nil {
+			fmt.Fprintln(errOutput, "\nFailed to update GitHub comment, skipping. Error:", err)
+		}
+	}
+	if err := ensureAppReviewComments(ctx, cov, args, errOutput); err != nil {
+		return err
+	}
+	if args.GitLabToken != "" {
+		mergeRequest, err := parseIssueURLFlag("gitlab-mr", args.GitLabMergeRequest, "GitLab merge request", "gitlab.com/group/project/-/merge_requests/123", providerGitLab)
+		if err != nil {
+			return err
+		}
+		err = ensureAppGitLabComment(ctx, gitLabCommentOptions{
+			GitLabEndpoint:  endpointOrDefault(args.GitLabEndpoint, mergeRequest),
+			GitLabToken:     args.GitLabToken,
+			Project:         mergeRequest.Owner + "/" + mergeRequest.Repo,
+			MergeRequestIID: mergeRequest.Number,
+			Body:            body,
+		})
+		if err != nil {
+			fmt.Fprintln(errOutput, "\nFailed to update GitLab comment, skipping. Error:", err)
+		}
+	}
+	if args.BitbucketToken != "" {
+		pullRequest, err := parseIssueURLFlag("bitbucket-pr", args.BitbucketPullRequest, "Bitbucket pull request", "bitbucket.org/workspace/repo/pull-requests/123", providerBitbucketCloud, providerBitbucketServer)
+		if err != nil {
+			return err
+		}
+		err = ensureAppBitbucketComment(ctx, bitbucketCommentOptions{
+			BitbucketEndpoint: endpointOrDefault(args.BitbucketEndpoint, pullRequest),
+			BitbucketToken:    args.BitbucketToken,
+			Server:            pullRequest.Provider == providerBitbucketServer,
+			Owner:             pullRequest.Owner,
+			Repo:              pullRequest.Repo,
+			PullRequestID:     pullRequest.Number,
+			Body:              body,
+		})
+		if err != nil {
+			fmt.Fprintln(errOutput, "\nFailed to update Bitbucket comment, skipping. Error:", err)
+		}
+	}
+	return nil
+}
+
+// ensureAppReviewComments posts inline GitHub review comments on uncovered lines, if enabled.
+// Runs even without diff coverage, so comments from previous runs are still updated.
+func ensureAppReviewComments(ctx context.Context, cov *covet.Covet, args Args, errOutput io.Writer) error {
+	if args.GitHubToken == "" || !args.GitHubReview {
+		return nil
+	}
+	issue, err := parseIssueURLFlag("gh-issue", args.GitHubIssue, "GitHub issue or pull request", "github.com/org/repo/pull/123", providerGitHub)
+	if err != nil {
+		return err
+	}
+	err = ensureAppGitHubReview(ctx, gitHubReviewOptions{
+		GitHubEndpoint: args.GitHubEndpoint,
+		GitHubToken:    args.GitHubToken,
+		RepoOwner:      issue.Owner,
+		Repo:           issue.Repo,
+		PullNumber:     issue.Number,
+		Comments:       uncoveredReviewComments(cov),
+	})
+	if err != nil {
+		fmt.Fprintln(errOutput, "\nFailed to update GitHub review comments, skipping. Error:", err)
+	}
+	return nil
+}
+
+func endpointOrDefault(endpoint string, issue issueURL) string {
+	if endpoint == "" {
+		endpoint = issue.APIEndpoint()
+	}
+	return strings.TrimSuffix(endpoint, "/")
+}
+
+// newTokenClient returns an HTTP client which authenticates with 'token'.
+// Tokens in the form 'username:password' use basic auth, otherwise they are sent as bearer tokens.
+func newTokenClient(ctx context.Context, token string) *http.Client {
+	if username, password, isBasic := strings.Cut(token, ":"); isBasic {
+		return &http.Client{
+			Transport: basicAuthTransport{username: username, password: password},
+		}
+	}
+	return oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+}
+
+type basicAuthTransport struct {
+	username, password string
+}
+
+func (b basicAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.SetBasicAuth(b.username, b.password)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// doJSON sends a request with 'body' encoded as JSON, then decodes the response into 'result'.
+// Either may be nil to skip encoding or decoding. Returns the response headers, like pagination links.
+func doJSON(ctx context.Context, client *http.Client, method, url string, body, result interface{}) (http.Header, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		bodyReader = bytes.NewReader(bodyBytes)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		const maxErrorBody = 1 << 10
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return nil, fmt.Errorf("%s %s: %d %s", method, url, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if result != nil {
+		err = json.NewDecoder(resp.Body).Decode(result)
+	}
+	return resp.Header, err
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/johnstarich/go/covet/internal/minmax"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// configFileNames are the config file names discovered in -diff-base-dir, in order of precedence
+//
+//nolint:gochecknoglobals // Read-only list
+var configFileNames = []string{".covet.yaml", ".covet.yml", ".covet.toml"}
+
+// configOnlyFlags can only be set on the command line, since they decide which config file to read
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var configOnlyFlags = map[string]bool{
+	"config":        true,
+	"diff-base-dir": true,
+}
+
+// configPathFlags take file paths, which are relative to the config file's directory instead of the current directory.
+// Coverage flags may add a label and base directory to each path and separate paths with commas.
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var configPathFlags = map[string]struct{ labelled bool }{
+	"codeowners":  {},
+	"cover":       {labelled: true},
+	"cover-go":    {labelled: true},
+	"diff-file":   {},
+	"history-dir": {},
+}
+
+// findConfigFile returns the path to the config file in baseDir, or an empty string if none exists
+func findConfigFile(baseDir string) (string, error) {
+	for _, name := range configFileNames {
+		configPath := filepath.Join(baseDir, name)
+		_, err := os.Stat(configPath)
+		if err == nil {
+			return configPath, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// applyConfigFile reads the YAML or TOML config file at configPath and sets each key's flag in 'set'.
+// Keys are flag names without the leading dash. Flags set on the command line take precedence over the config file.
+// Relative paths in path-valued keys start from the config file's directory.
+func applyConfigFile(set *flag.FlagSet, configPath string) error {
+	contents, err := os.ReadFile(configPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to read config file")
+	}
+	config := make(map[string]interface{})
+	if filepath.Ext(configPath) == ".toml" {
+		_, err = toml.NewDecoder(bytes.NewReader(contents)).Decode(&config)
+	} else {
+		err = yaml.Unmarshal(contents, &config)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse config file %s", configPath)
+	}
+
+	setOnCommandLine := make(map[string]bool)
+	set.Visit(func(f *flag.Flag) {
+		setOnCommandLine[f.Name] = true
+	})
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if configOnlyFlags[key] {
+			return errors.Errorf("key %q in config file %s can only be set with the -%s flag", key, configPath, key)
+		}
+		if set.Lookup(key) == nil {
+			return unknownConfigKeyError(set, configPath, key)
+		}
+		if setOnCommandLine[key] {
+			continue
+		}
+		values, err := configValues(config[key])
+		if err != nil {
+			return errors.Wrapf(err, "invalid value for key %q in config file %s", key, configPath)
+		}
+		for _, value := range values {
+			if pathFlag, isPath := configPathFlags[key]; isPath {
+				value = resolveConfigPaths(filepath.Dir(configPath), value, pathFlag.labelled)
+			}
+			if err := set.Set(key, value); err != nil {
+				return errors.Wrapf(err, "invalid value %q for key %q in config file %s", value, key, configPath)
+			}
+		}
+	}
+	return nil
+}
+
+// resolveConfigPaths joins relative paths in a config value to configDir.
+// If 'labelled' is set, the value may hold comma-separated paths with label prefixes and base directory suffixes, like 'unit=a/cover.out=a'.
+func resolveConfigPaths(configDir, value string, labelled bool) string {
+	if !labelled {
+		return resolveConfigPath(configDir, value)
+	}
+	paths := strings.Split(value, ",")
+	for i, p := range paths {
+		if p == "" {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(configDir, p)); err == nil {
+			paths[i] = resolveConfigPath(configDir, p)
+			continue
+		}
+		arg := parseCoverageArg(p)
+		arg.Path = resolveConfigPath(configDir, arg.Path)
+		if arg.BaseDir != "" {
+			arg.BaseDir = resolveConfigPath(configDir, arg.BaseDir)
+		}
+		paths[i] = arg.String()
+	}
+	return strings.Join(paths, ",")
+}
+
+// resolveConfigPath joins a relative path to configDir. Absolute paths and '-' for stdin are unchanged.
+func resolveConfigPath(configDir, p string) string {
+	if p == "-" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(configDir, p)
+}
+
+// configValues returns the flag values for a config value. Lists set the flag once per item.
+func configValues(value interface{}) ([]string, error) {
+	list, isList := value.([]interface{})
+	if !isList {
+		list = []interface{}{value}
+	}
+	values := make([]string, 0, len(list))
+	for _, item := range list {
+		switch item := item.(type) {
+		case string, bool, int, int64, uint64, float64:
+			values = append(values, fmt.Sprint(item))
+		default:
+			return nil, errors.Errorf("must be a string, number, boolean, or list of those, found %T", item)
+		}
+	}
+	return values, nil
+}
+
+func unknownConfigKeyError(set *flag.FlagSet, configPath, key string) error {
+	const maxSuggestionDistance = 3
+	suggestion := ""
+	bestDistance := maxSuggestionDistance + 1
+	set.VisitAll(func(f *flag.Flag) {
+		if configOnlyFlags[f.Name] {
+			return
+		}
+		if distance := editDistance(strings.ToLower(key), f.Name); distance < bestDistance {
+			suggestion, bestDistance = f.Name, distance
+		}
+	})
+	if suggestion != "" {
+		return errors.Errorf("unknown key %q in config file %s, did you mean %q?", key, configPath, suggestion)
+	}
+	return errors.Errorf("unknown key %q in config file %s, keys must be flag names like \"target-diff-coverage\"", key, configPath)
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			substitution := previous[j-1]
+			if a[i-1] != b[j-1] {
+				substitution++
+			}
+			current[j] = minmax.Min(minmax.Min(previous[j]+1, current[j-1]+1), substitution)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
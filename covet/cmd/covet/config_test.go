@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/johnstarich/go/covet/internal/summary"
+	"github.com/johnstarich/go/covet/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseArgsConfigFile(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		description string
+		fileName    string
+		config      string
+		args        []string
+		expectArgs  func(t *testing.T, args Args)
+		expectErr   string
+	}{
+		{
+			description: "yaml",
+			fileName:    ".covet.yaml",
+			config: `
+diff-file: my.patch
+target-diff-coverage: 80
+format: markdown
+ignore:
+  - vendor/**
+  - "*.pb.go"
+min-file-diff-coverage: [50, "internal/*.go=70"]
+gh-review: true
+`,
+			expectArgs: func(t *testing.T, args Args) {
+				t.Helper()
+				assert.Equal(t, uint(80), args.TargetDiffCoverage)
+				assert.Equal(t, summary.FormatMarkdown, args.Format)
+				assert.Equal(t, []string{"vendor/**", "*.pb.go"}, args.IgnorePatterns)
+				assert.Equal(t, fileMinimums{
+					{Minimum: 50},
+					{Pattern: "internal/*.go", Minimum: 70},
+				}, args.MinFileDiffCoverage)
+				assert.True(t, args.GitHubReview)
+			},
+		},
+		{
+			description: "toml",
+			fileName:    ".covet.toml",
+			config: `
+diff-file = "my.patch"
+target-diff-coverage = 75
+group-by = ["package", "owner"]
+min-diff-coverage = 60
+`,
+			expectArgs: func(t *testing.T, args Args) {
+				t.Helper()
+				assert.Equal(t, uint(75), args.TargetDiffCoverage)
+				assert.Equal(t, uint(60), args.MinDiffCoverage)
+				assert.True(t, args.GroupByPackage)
+				assert.True(t, args.GroupByOwner)
+			},
+		},
+		{
+			description: "flags take precedence",
+			fileName:    ".covet.yml",
+			config: `
+diff-file: my.patch
+target-diff-coverage: 80
+format: markdown
+`,
+			args: []string{"-target-diff-coverage", "95"},
+			expectArgs: func(t *testing.T, args Args) {
+				t.Helper()
+				assert.Equal(t, uint(95), args.TargetDiffCoverage)
+				assert.Equal(t, summary.FormatMarkdown, args.Format)
+			},
+		},
+		{
+			description: "unknown key",
+			fileName:    ".covet.yaml",
+			config: `
+tagret-diff-coverage: 80
+`,
+			expectErr: `unknown key "tagret-diff-coverage" in config file <dir>/.covet.yaml, did you mean "target-diff-coverage"?`,
+		},
+		{
+			description: "unknown key without suggestion",
+			fileName:    ".covet.toml",
+			config: `
+[github]
+token = "abc"
+`,
+			expectErr: `unknown key "github" in config file <dir>/.covet.toml, keys must be flag names like "target-diff-coverage"`,
+		},
+		{
+			description: "command line only key",
+			fileName:    ".covet.yaml",
+			config: `
+diff-base-dir: ./other
+`,
+			expectErr: `key "diff-base-dir" in config file <dir>/.covet.yaml can only be set with the -diff-base-dir flag`,
+		},
+		{
+			description: "invalid value",
+			fileName:    ".covet.yaml",
+			config: `
+format: xml
+`,
+			expectErr: `invalid value "xml" for key "format" in config file <dir>/.covet.yaml: unsupported format "xml", must be one of: terminal, markdown, json, sarif, html`,
+		},
+		{
+			description: "invalid value type",
+			fileName:    ".covet.yaml",
+			config: `
+ignore:
+  pattern: vendor/**
+`,
+			expectErr: `invalid value for key "ignore" in config file <dir>/.covet.yaml: must be a string, number, boolean, or list of those, found map[string]interface {}`,
+		},
+	} {
+		tc := tc // enable parallel sub-tests
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, tc.fileName), []byte(tc.config), 0o600))
+
+			var buf bytes.Buffer
+			strArgs := append([]string{"-cover-go", "cover.out", "-diff-base-dir", dir}, tc.args...)
+			args, err := parseArgs(strArgs, &buf)
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, strings.ReplaceAll(tc.expectErr, "<dir>", dir))
+				return
+			}
+			require.NoError(t, err)
+			tc.expectArgs(t, args)
+		})
+	}
+}
+
+func TestParseArgsExplicitConfigFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "covet-ci.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+diff-file: my.patch
+show-functions: true
+`), 0o600))
+
+	var buf bytes.Buffer
+	args, err := parseArgs([]string{"-config", configPath, "-cover-go", "cover.out"}, &buf)
+	require.NoError(t, err)
+	assert.True(t, args.ShowFunctions)
+
+	_, err = parseArgs([]string{"-config", filepath.Join(dir, "missing.yaml"), "-cover-go", "cover.out"}, &buf)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestParseArgsConfigFileRelativePaths(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	configDir := filepath.Join(dir, "ci")
+	require.NoError(t, os.Mkdir(configDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, ".covet.yaml"), []byte(`
+diff-file: my.patch
+cover-go: [unit=unit.out, integ.out, out/a.out=a]
+codeowners: ../CODEOWNERS
+history-dir: history
+`), 0o600))
+
+	// The working directory is this package's directory, so paths must resolve from the config file instead
+	var buf bytes.Buffer
+	args, err := parseArgs([]string{"-diff-base-dir", configDir}, &buf)
+	require.NoError(t, err)
+
+	_, fsDir := testhelpers.FromOSToFS(t, dir)
+	assert.Equal(t, path.Join(fsDir, "ci/my.patch"), args.DiffFile)
+	assert.Equal(t, []string{
+		"unit=" + path.Join(fsDir, "ci/unit.out"),
+		path.Join(fsDir, "ci/integ.out"),
+		path.Join(fsDir, "ci/out/a.out") + "=" + path.Join(fsDir, "ci/a"),
+	}, args.GoCoverageFiles)
+	assert.Equal(t, path.Join(fsDir, "CODEOWNERS"), args.CodeOwnersFile)
+	assert.Equal(t, path.Join(fsDir, "ci/history"), args.HistoryDir)
+}
+
+func TestEditDistance(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		a, b   string
+		expect int
+	}{
+		{a: "", b: "", expect: 0},
+		{a: "abc", b: "", expect: 3},
+		{a: "format", b: "format", expect: 0},
+		{a: "fromat", b: "format", expect: 2},
+		{a: "gh-tokn", b: "gh-token", expect: 1},
+	} {
+		assert.Equal(t, tc.expect, editDistance(tc.a, tc.b), "%q -> %q", tc.a, tc.b)
+	}
+}
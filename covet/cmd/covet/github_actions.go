@@ -2,12 +2,15 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
 
+	"github.com/johnstarich/go/covet"
 	"github.com/johnstarich/go/covet/internal/coverstatus"
 	"github.com/johnstarich/go/covet/internal/span"
+	"github.com/johnstarich/go/covet/internal/summary"
 )
 
 func inGitHubActions() bool { return os.Getenv("GITHUB_ACTIONS") == "true" }
@@ -60,14 +63,32 @@ func githubActionsEncode(s string) string {
 	return sb.String()
 }
 
-func runWorkflow(s string) {
-	if inGitHubActions() {
-		fmt.Println(s)
+// writeWorkflowCommands writes GitHub Actions annotations for the total diff coverage, each uncovered file, and each failure to meet a minimum
+func writeWorkflowCommands(w io.Writer, cov *covet.Covet, args Args, failures []coverageFailure) {
+	if len(cov.DiffCoverageFiles()) == 0 {
+		return
+	}
+	fmt.Fprintln(w, coverageCommand(cov.DiffCovered(), args.MinDiffCoverage, "", nil))
+	reportedFiles := make(map[string]bool)
+	for _, f := range cov.PriorityUncoveredFiles(args.TargetDiffCoverage) {
+		reportedFiles[f.Name] = true
+		fmt.Fprintln(w, coverageCommand(summary.FileCoverage(f), args.MinFileDiffCoverage.Minimum(f.Name), f.Name, findUncoveredLines(f)))
+	}
+	for _, failure := range failures {
+		switch f := failure.File; {
+		case f.Name != "" && !reportedFiles[f.Name]:
+			fmt.Fprintln(w, coverageCommand(failure.Coverage, failure.Minimum, f.Name, findUncoveredLines(f)))
+		case failure.Owner != nil:
+			fmt.Fprintln(w, ownerCoverageCommand(failure))
+		}
 	}
 }
 
-func coverageCommand(percent float64, file string, uncovered []span.Span) string {
-	status := coverstatus.New(percent)
+// coverageCommand returns a workflow command annotating the diff coverage of 'file', or the total if 'file' is empty.
+// If 'percent' is below 'minimum', the annotation is an error and includes the failure reason.
+func coverageCommand(percent float64, minimum uint, file string, uncovered []span.Span) string {
+	minimumPercent := float64(minimum) / maxPercentInt
+	status := coverstatus.NewWithMinimum(percent, minimumPercent)
 	message := fmt.Sprintf("Diff coverage is %.1f%%", maxPercentInt*percent)
 	args := map[string]string{
 		"title": "covet",
@@ -85,5 +106,15 @@ func coverageCommand(percent float64, file string, uncovered []span.Span) string
 			message += fmt.Sprintf("* %s#L%d-%d\n", file, lines.Start, lines.End-1)
 		}
 	}
+	if percent < minimumPercent {
+		message = fmt.Sprintf("Diff coverage %.1f%% is below the required minimum %d%%\n", maxPercentInt*percent, minimum) + message
+	}
 	return workflowCommand(status.WorkflowCommand(), message, args)
 }
+
+// ownerCoverageCommand returns a workflow command annotating an owner's diff coverage failure, which has no file to attach to
+func ownerCoverageCommand(failure coverageFailure) string {
+	return workflowCommand("error", failure.Error(), map[string]string{
+		"title": "covet",
+	})
+}
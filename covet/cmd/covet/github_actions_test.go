@@ -1,9 +1,15 @@
 package main
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
+	"github.com/johnstarich/go/covet"
+	"github.com/johnstarich/go/covet/internal/span"
+	"github.com/johnstarich/go/covet/internal/testhelpers"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWorkflowCommand(t *testing.T) {
@@ -14,3 +20,101 @@ func TestWorkflowCommand(t *testing.T) {
 	})
 	assert.Equal(t, `::error file=someFile.txt,name=hello::My message.`, command)
 }
+
+func TestCoverageCommand(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		description string
+		percent     float64
+		minimum     uint
+		file        string
+		uncovered   []span.Span
+		expect      string
+	}{
+		{
+			description: "total coverage",
+			percent:     0.95,
+			expect:      `::notice title=covet::Diff coverage is 95.0%25`,
+		},
+		{
+			description: "total coverage below minimum",
+			percent:     0.85,
+			minimum:     90,
+			expect:      `::error title=covet::Diff coverage 85.0%25 is below the required minimum 90%25%0ADiff coverage is 85.0%25`,
+		},
+		{
+			description: "file coverage below minimum",
+			percent:     0.5,
+			minimum:     60,
+			file:        "foo.go",
+			uncovered:   []span.Span{{Start: 2, End: 4}},
+			expect:      `::error endLine=3,file=foo.go,line=2,title=Not enough tests on foo.go. (-50.0%25)::Diff coverage 50.0%25 is below the required minimum 60%25%0A* foo.go#L2-3%0A`,
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expect, coverageCommand(tc.percent, tc.minimum, tc.file, tc.uncovered))
+		})
+	}
+}
+
+func TestWriteWorkflowCommands(t *testing.T) {
+	t.Parallel()
+	fs := testhelpers.FSWithFiles(t, map[string]string{
+		"cover.out": `
+mode: atomic
+github.com/org/repo/main.go:4.1,4.9 1 1
+github.com/org/repo/main.go:5.1,5.9 1 0
+`,
+		"go.mod": `
+module github.com/org/repo
+`,
+		"CODEOWNERS": `
+* @org/cli
+`,
+		"main.go": `
+package main
+
+func main() {
+	println(1)
+	println(2)
+}
+`,
+	})
+	cov, err := covet.Parse(covet.Options{
+		FS: fs,
+		Diff: strings.NewReader(`
+diff --git a/main.go b/main.go
+index 0000000..1111111 100644
+--- a/main.go
++++ b/main.go
+@@ -1,4 +1,6 @@
+ package main
+
+ func main() {
++	println(1)
++	println(2)
+ }
+`),
+		DiffBaseDir:    ".",
+		GoCoveragePath: "cover.out",
+		CodeOwnersPath: "CODEOWNERS",
+	})
+	require.NoError(t, err)
+	args := Args{
+		TargetDiffCoverage: 90,
+		MinOwnerDiffCoverage: fileMinimums{
+			{Pattern: "@org/cli", Minimum: 70},
+		},
+	}
+	failures, err := findCoverageFailures(cov, args)
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	writeWorkflowCommands(&output, cov, args, failures)
+	assert.Equal(t, strings.Join([]string{
+		`::warning title=covet::Diff coverage is 50.0%25`,
+		`::warning endLine=5,file=main.go,line=5,title=Not enough tests on main.go. (-50.0%25)::* main.go#L5-5%0A`,
+		`::error title=covet::owner @org/cli diff coverage 50.0%25 is below the required minimum 70%25`,
+	}, "\n")+"\n", output.String())
+}
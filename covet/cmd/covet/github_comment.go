@@ -18,10 +18,7 @@ type gitHubCommentOptions struct {
 }
 
 func ensureAppGitHubComment(ctx context.Context, options gitHubCommentOptions) error {
-	const appMarker = "<!-- covet -->\n\n"
-	authClient := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: options.GitHubToken}))
-
-	client, err := github.NewEnterpriseClient(options.GitHubEndpoint, "", authClient)
+	client, err := newGitHubClient(ctx, options.GitHubEndpoint, options.GitHubToken)
 	if err != nil {
 		return err
 	}
@@ -33,12 +30,12 @@ func ensureAppGitHubComment(ctx context.Context, options gitHubCommentOptions) e
 	}
 	var comment *github.IssueComment
 	for _, c := range comments {
-		if strings.HasPrefix(stringOrEmpty(c.Body), appMarker) {
+		if strings.HasPrefix(stringOrEmpty(c.Body), appCommentMarker) {
 			comment = c
 		}
 	}
 	newComment := &github.IssueComment{
-		Body: stringPtr(appMarker + options.Body),
+		Body: stringPtr(appCommentMarker + options.Body),
 	}
 	if comment == nil {
 		_, _, err = client.Issues.CreateComment(ctx, options.RepoOwner, options.Repo, options.IssueNumber, newComment)
@@ -48,6 +45,11 @@ func ensureAppGitHubComment(ctx context.Context, options gitHubCommentOptions) e
 	return err
 }
 
+func newGitHubClient(ctx context.Context, endpoint, token string) (*github.Client, error) {
+	authClient := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+	return github.NewEnterpriseClient(endpoint, "", authClient)
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
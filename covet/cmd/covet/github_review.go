@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v44/github"
+	"github.com/johnstarich/go/covet"
+	"github.com/johnstarich/go/covet/internal/span"
+	"github.com/johnstarich/go/covet/internal/summary"
+)
+
+// reviewCommentMarker prefixes inline review comments, so later runs can find and update them
+const reviewCommentMarker = "<!-- covet:uncovered -->\n\n"
+
+const resolvedReviewComment = reviewCommentMarker + "These lines are now covered by tests or no longer part of the diff."
+
+type gitHubReviewOptions struct {
+	GitHubEndpoint string
+	GitHubToken    string
+	RepoOwner      string
+	Repo           string
+	PullNumber     int
+	Comments       []reviewComment
+}
+
+// reviewComment is an inline pull request comment on a range of lines in Path
+type reviewComment struct {
+	Path  string
+	Lines span.Span
+	Body  string
+}
+
+// reviewCommentKey identifies a review comment's location. StartLine is 0 for single-line comments.
+// Outdated comments are keyed by their original location, which never matches a new comment.
+type reviewCommentKey struct {
+	Path      string
+	StartLine int
+	Line      int
+	Outdated  bool
+}
+
+func (c reviewComment) key() reviewCommentKey {
+	key := reviewCommentKey{
+		Path: c.Path,
+		Line: int(c.Lines.End - 1),
+	}
+	if c.Lines.Len() > 1 {
+		key.StartLine = int(c.Lines.Start)
+	}
+	return key
+}
+
+// uncoveredReviewComments returns a review comment for each range of uncovered lines in the diff
+func uncoveredReviewComments(cov *covet.Covet) []reviewComment {
+	var comments []reviewComment
+	for _, f := range sortedFiles(cov.DiffCoverageFiles()) {
+		percent := strings.TrimSpace(summary.FormatPercent(summary.FileCoverage(f)))
+		for _, lines := range findUncoveredLines(f) {
+			description := fmt.Sprintf("Line %d is", lines.Start)
+			if lines.Len() > 1 {
+				description = fmt.Sprintf("Lines %d-%d are", lines.Start, lines.End-1)
+			}
+			comments = append(comments, reviewComment{
+				Path:  cov.DiffFilePath(f),
+				Lines: lines,
+				Body:  fmt.Sprintf("%s%s not covered by tests. File diff coverage is %s.", reviewCommentMarker, description, percent),
+			})
+		}
+	}
+	return comments
+}
+
+// ensureAppGitHubReview creates a pull request review with inline comments for any new 'options.Comments'.
+// Comments from previous runs are updated in place, and stale comments are edited to show they're resolved.
+func ensureAppGitHubReview(ctx context.Context, options gitHubReviewOptions) error {
+	client, err := newGitHubClient(ctx, options.GitHubEndpoint, options.GitHubToken)
+	if err != nil {
+		return err
+	}
+	existingComments, err := listAppReviewComments(ctx, client, options)
+	if err != nil {
+		return err
+	}
+
+	var newComments []*github.DraftReviewComment
+	for _, comment := range options.Comments {
+		key := comment.key()
+		existing, exists := existingComments[key]
+		if !exists {
+			newComments = append(newComments, newDraftReviewComment(comment, key))
+			continue
+		}
+		delete(existingComments, key)
+		if stringOrEmpty(existing.Body) != comment.Body {
+			_, _, err := client.PullRequests.EditComment(ctx, options.RepoOwner, options.Repo, *existing.ID, &github.PullRequestComment{
+				Body: stringPtr(comment.Body),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, stale := range existingComments {
+		if stringOrEmpty(stale.Body) != resolvedReviewComment {
+			_, _, err := client.PullRequests.EditComment(ctx, options.RepoOwner, options.Repo, *stale.ID, &github.PullRequestComment{
+				Body: stringPtr(resolvedReviewComment),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(newComments) == 0 {
+		return nil
+	}
+	_, _, err = client.PullRequests.CreateReview(ctx, options.RepoOwner, options.Repo, options.PullNumber, &github.PullRequestReviewRequest{
+		Event:    stringPtr("COMMENT"),
+		Body:     stringPtr(fmt.Sprintf("Found %d new uncovered line ranges in the diff.", len(newComments))),
+		Comments: newComments,
+	})
+	return err
+}
+
+// listAppReviewComments returns review comments from previous runs, including outdated ones no longer attached to lines in the diff
+func listAppReviewComments(ctx context.Context, client *github.Client, options gitHubReviewOptions) (map[reviewCommentKey]*github.PullRequestComment, error) {
+	const maxPageSize = 100
+	listOptions := &github.PullRequestListCommentsOptions{
+		ListOptions: github.ListOptions{PerPage: maxPageSize},
+	}
+	comments := make(map[reviewCommentKey]*github.PullRequestComment)
+	for {
+		page, resp, err := client.PullRequests.ListComments(ctx, options.RepoOwner, options.Repo, options.PullNumber, listOptions)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range page {
+			if c.InReplyTo != nil || c.ID == nil || !strings.HasPrefix(stringOrEmpty(c.Body), reviewCommentMarker) {
+				continue
+			}
+			comments[existingReviewCommentKey(c)] = c
+		}
+		if resp.NextPage == 0 {
+			return comments, nil
+		}
+		listOptions.Page = resp.NextPage
+	}
+}
+
+// existingReviewCommentKey returns the key for a listed comment. Outdated comments have no line in the diff, so they use their original lines instead.
+func existingReviewCommentKey(c *github.PullRequestComment) reviewCommentKey {
+	key := reviewCommentKey{Path: stringOrEmpty(c.Path)}
+	line, startLine := c.Line, c.StartLine
+	if line == nil {
+		key.Outdated = true
+		line, startLine = c.OriginalLine, c.OriginalStartLine
+	}
+	if line != nil {
+		key.Line = *line
+	}
+	if startLine != nil {
+		key.StartLine = *startLine
+	}
+	return key
+}
+
+func newDraftReviewComment(comment reviewComment, key reviewCommentKey) *github.DraftReviewComment {
+	const rightSide = "RIGHT" // the new version of the file
+	draft := &github.DraftReviewComment{
+		Path: stringPtr(comment.Path),
+		Body: stringPtr(comment.Body),
+		Line: intPtr(key.Line),
+		Side: stringPtr(rightSide),
+	}
+	if key.StartLine != 0 {
+		draft.StartLine = intPtr(key.StartLine)
+		draft.StartSide = stringPtr(rightSide)
+	}
+	return draft
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v44/github"
+	"github.com/johnstarich/go/covet"
+	"github.com/johnstarich/go/covet/internal/span"
+	"github.com/johnstarich/go/covet/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUncoveredReviewComments(t *testing.T) {
+	t.Parallel()
+	fs := testhelpers.FSWithFiles(t, map[string]string{
+		"go.mod": `module github.com/org/repo`,
+		"cmd/main.go": `
+package main
+
+func main() {
+	println(1)
+	println(2)
+	println(3)
+	println(4)
+}
+`,
+		"cover.out": `
+mode: set
+github.com/org/repo/cmd/main.go:4.1,4.11 1 0
+github.com/org/repo/cmd/main.go:5.1,5.11 1 0
+github.com/org/repo/cmd/main.go:6.1,6.11 1 1
+github.com/org/repo/cmd/main.go:7.1,7.11 1 0
+`,
+	})
+	diff := `
+diff --git a/cmd/main.go b/cmd/main.go
+index 0000000..1111111 100644
+--- a/cmd/main.go
++++ b/cmd/main.go
+@@ -1,4 +1,8 @@
+ package main
+
+ func main() {
++	println(1)
++	println(2)
++	println(3)
++	println(4)
+ }
+`
+	cov, err := covet.Parse(covet.Options{
+		FS:             fs,
+		Diff:           strings.NewReader(strings.TrimSpace(diff)),
+		DiffBaseDir:    ".",
+		GoCoveragePath: "cover.out",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []reviewComment{
+		{
+			Path:  "cmd/main.go",
+			Lines: span.Span{Start: 4, End: 6},
+			Body:  "<!-- covet:uncovered -->\n\nLines 4-5 are not covered by tests. File diff coverage is 25.0%.",
+		},
+		{
+			Path:  "cmd/main.go",
+			Lines: span.Span{Start: 7, End: 8},
+			Body:  "<!-- covet:uncovered -->\n\nLine 7 is not covered by tests. File diff coverage is 25.0%.",
+		},
+	}, uncoveredReviewComments(cov))
+}
+
+func TestEnsureAppGitHubReview(t *testing.T) {
+	t.Parallel()
+	const (
+		oldBody = "<!-- covet:uncovered -->\n\nold body"
+		newBody = "<!-- covet:uncovered -->\n\nnew body"
+	)
+	edited := make(map[string]string)
+	var review github.PullRequestReviewRequest
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch fmt.Sprintf("%s:%s", r.Method, r.URL.Path) {
+		case "GET:/api/v3/repos/org/repo/pulls/1/comments":
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/api/v3/repos/org/repo/pulls/1/comments?page=2>; rel="next"`, server.URL))
+				require.NoError(t, json.NewEncoder(w).Encode([]*github.PullRequestComment{
+					// same location, needs an update
+					{ID: int64Ptr(1), Path: stringPtr("main.go"), StartLine: intPtr(4), Line: intPtr(5), Body: stringPtr(oldBody)},
+					// reply to a comment
+					{ID: int64Ptr(2), Path: stringPtr("main.go"), Line: intPtr(9), Body: stringPtr(oldBody), InReplyTo: int64Ptr(1)},
+					// outdated comment, originally at the same location as a new one
+					{ID: int64Ptr(3), Path: stringPtr("main.go"), OriginalStartLine: intPtr(4), OriginalLine: intPtr(5), Body: stringPtr(oldBody)},
+					// someone else's comment
+					{ID: int64Ptr(4), Path: stringPtr("main.go"), Line: intPtr(10), Body: stringPtr("looks good")},
+				}))
+				return
+			}
+			require.NoError(t, json.NewEncoder(w).Encode([]*github.PullRequestComment{
+				// stale comment
+				{ID: int64Ptr(5), Path: stringPtr("main.go"), Line: intPtr(20), Body: stringPtr(oldBody)},
+				// already resolved
+				{ID: int64Ptr(6), Path: stringPtr("main.go"), Line: intPtr(21), Body: stringPtr(resolvedReviewComment)},
+			}))
+		case "PATCH:/api/v3/repos/org/repo/pulls/comments/1", "PATCH:/api/v3/repos/org/repo/pulls/comments/3", "PATCH:/api/v3/repos/org/repo/pulls/comments/5":
+			var comment github.PullRequestComment
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&comment))
+			edited[r.URL.Path] = stringOrEmpty(comment.Body)
+			require.NoError(t, json.NewEncoder(w).Encode(comment))
+		case "POST:/api/v3/repos/org/repo/pulls/1/reviews":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&review))
+			require.NoError(t, json.NewEncoder(w).Encode(github.PullRequestReview{}))
+		default:
+			t.Fatal("Unknown request method and path:", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	err := ensureAppGitHubReview(context.Background(), gitHubReviewOptions{
+		GitHubEndpoint: server.URL,
+		GitHubToken:    "some-token",
+		RepoOwner:      "org",
+		Repo:           "repo",
+		PullNumber:     1,
+		Comments: []reviewComment{
+			{Path: "main.go", Lines: span.Span{Start: 4, End: 6}, Body: newBody},
+			{Path: "main.go", Lines: span.Span{Start: 9, End: 10}, Body: newBody},
+			{Path: "other.go", Lines: span.Span{Start: 1, End: 3}, Body: newBody},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"/api/v3/repos/org/repo/pulls/comments/1": newBody,
+		"/api/v3/repos/org/repo/pulls/comments/3": resolvedReviewComment,
+		"/api/v3/repos/org/repo/pulls/comments/5": resolvedReviewComment,
+	}, edited)
+	assert.Equal(t, github.PullRequestReviewRequest{
+		Event: stringPtr("COMMENT"),
+		Body:  stringPtr("Found 2 new uncovered line ranges in the diff."),
+		Comments: []*github.DraftReviewComment{
+			{Path: stringPtr("main.go"), Body: stringPtr(newBody), Line: intPtr(9), Side: stringPtr("RIGHT")},
+			{Path: stringPtr("other.go"), Body: stringPtr(newBody), StartLine: intPtr(1), StartSide: stringPtr("RIGHT"), Line: intPtr(2), Side: stringPtr("RIGHT")},
+		},
+	}, review)
+}
+
+func TestEnsureAppGitHubReviewNoNewComments(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch fmt.Sprintf("%s:%s", r.Method, r.URL.Path) {
+		case "GET:/api/v3/repos/org/repo/pulls/1/comments":
+			require.NoError(t, json.NewEncoder(w).Encode([]*github.PullRequestComment{}))
+		default:
+			t.Fatal("Unknown request method and path:", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	err := ensureAppGitHubReview(context.Background(), gitHubReviewOptions{
+		GitHubEndpoint: server.URL,
+		GitHubToken:    "some-token",
+		RepoOwner:      "org",
+		Repo:           "repo",
+		PullNumber:     1,
+	})
+	assert.NoError(t, err)
+}
+
+func TestEnsureAppGitHubReviewOutdatedComment(t *testing.T) {
+	t.Parallel()
+	var edited []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch fmt.Sprintf("%s:%s", r.Method, r.URL.Path) {
+		case "GET:/api/v3/repos/org/repo/pulls/1/comments":
+			_, err := w.Write([]byte(`[{"id": 1, "path": "main.go", "line": null, "original_line": 5, "body": "<!-- covet:uncovered -->\n\nold body"}]`))
+			require.NoError(t, err)
+		case "PATCH:/api/v3/repos/org/repo/pulls/comments/1":
+			var comment github.PullRequestComment
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&comment))
+			edited = append(edited, stringOrEmpty(comment.Body))
+			require.NoError(t, json.NewEncoder(w).Encode(comment))
+		default:
+			t.Fatal("Unknown request method and path:", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	err := ensureAppGitHubReview(context.Background(), gitHubReviewOptions{
+		GitHubEndpoint: server.URL,
+		GitHubToken:    "some-token",
+		RepoOwner:      "org",
+		Repo:           "repo",
+		PullNumber:     1,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{resolvedReviewComment}, edited)
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type gitLabCommentOptions struct {
+	GitLabEndpoint  string
+	GitLabToken     string
+	Project         string // full project path, e.g. group/subgroup/project
+	MergeRequestIID int
+	Body            string
+}
+
+type gitLabNote struct {
+	ID     int64  `json:"id,omitempty"`
+	Body   string `json:"body"`
+	System bool   `json:"system,omitempty"`
+}
+
+func ensureAppGitLabComment(ctx context.Context, options gitLabCommentOptions) error {
+	client := newTokenClient(ctx, options.GitLabToken)
+	notesURL := fmt.Sprintf("%s/projects/%s/merge_requests/%d/notes", options.GitLabEndpoint, url.PathEscape(options.Project), options.MergeRequestIID)
+
+	var comment *gitLabNote
+	for page := "1"; page != ""; {
+		var notes []gitLabNote
+		header, err := doJSON(ctx, client, http.MethodGet, notesURL+"?sort=asc&order_by=created_at&per_page=100&page="+url.QueryEscape(page), nil, &notes)
+		if err != nil {
+			return err
+		}
+		for i := range notes {
+			if !notes[i].System && strings.HasPrefix(notes[i].Body, appCommentMarker) {
+				comment = &notes[i]
+			}
+		}
+		page = header.Get("X-Next-Page")
+	}
+
+	newComment := gitLabNote{Body: appCommentMarker + options.Body}
+	var err error
+	if comment == nil {
+		_, err = doJSON(ctx, client, http.MethodPost, notesURL, newComment, nil)
+	} else {
+		_, err = doJSON(ctx, client, http.MethodPut, fmt.Sprintf("%s/%d", notesURL, comment.ID), newComment, nil)
+	}
+	return err
+}
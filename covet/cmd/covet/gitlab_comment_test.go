@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsureAppGitLabComment(t *testing.T) {
+	t.Parallel()
+	created := false
+	updated := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer some-token", r.Header.Get("Authorization"))
+		switch fmt.Sprintf("%s:%s", r.Method, r.URL.EscapedPath()) {
+		// create new comment
+		case "GET:/api/v4/projects/group%2Fproject/merge_requests/456/notes":
+			require.NoError(t, json.NewEncoder(w).Encode([]gitLabNote{
+				{ID: 1, Body: "<!-- covet -->\n\nsystem note", System: true},
+			}))
+		case "POST:/api/v4/projects/group%2Fproject/merge_requests/456/notes":
+			created = true
+			var note gitLabNote
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&note))
+			assert.Equal(t, gitLabNote{
+				Body: "<!-- covet -->\n\nsome body",
+			}, note)
+			w.WriteHeader(http.StatusCreated)
+
+		// update existing comment on the second page
+		case "GET:/api/v4/projects/group%2Fproject/merge_requests/123/notes":
+			switch r.URL.Query().Get("page") {
+			case "1":
+				w.Header().Set("X-Next-Page", "2")
+				require.NoError(t, json.NewEncoder(w).Encode([]gitLabNote{
+					{ID: 1, Body: "some other comment"},
+				}))
+			case "2":
+				require.NoError(t, json.NewEncoder(w).Encode([]gitLabNote{
+					{ID: 2, Body: "<!-- covet -->\n\nsome body"},
+				}))
+			default:
+				t.Fatal("Unexpected page:", r.URL.Query().Get("page"))
+			}
+		case "PUT:/api/v4/projects/group%2Fproject/merge_requests/123/notes/2":
+			updated = true
+			var note gitLabNote
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&note))
+			assert.Equal(t, gitLabNote{
+				Body: "<!-- covet -->\n\nsome other body",
+			}, note)
+		default:
+			t.Fatal("Unknown request method and path:", r.Method, r.URL.EscapedPath())
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+	// create new comment
+	err := ensureAppGitLabComment(ctx, gitLabCommentOptions{
+		GitLabEndpoint:  server.URL + "/api/v4",
+		GitLabToken:     "some-token",
+		Project:         "group/project",
+		MergeRequestIID: 456,
+		Body:            "some body",
+	})
+	assert.NoError(t, err)
+	assert.True(t, created)
+	// update existing comment
+	err = ensureAppGitLabComment(ctx, gitLabCommentOptions{
+		GitLabEndpoint:  server.URL + "/api/v4",
+		GitLabToken:     "some-token",
+		Project:         "group/project",
+		MergeRequestIID: 123,
+		Body:            "some other body",
+	})
+	assert.NoError(t, err)
+	assert.True(t, updated)
+}
+
+func TestEnsureAppGitLabCommentError(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"message":"401 Unauthorized"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	err := ensureAppGitLabComment(context.Background(), gitLabCommentOptions{
+		GitLabEndpoint:  server.URL,
+		GitLabToken:     "some-token",
+		Project:         "group/project",
+		MergeRequestIID: 1,
+	})
+	assert.EqualError(t, err, fmt.Sprintf(`GET %s/projects/group%%2Fproject/merge_requests/1/notes?sort=asc&order_by=created_at&per_page=100&page=1: 401 {"message":"401 Unauthorized"}`, server.URL))
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/johnstarich/go/covet"
+	"github.com/johnstarich/go/covet/internal/gitrepo"
+)
+
+// compareHistory returns the baseline Snapshot from -history-dir, if one was recorded
+func compareHistory(args Args, deps Deps) (*covet.Snapshot, error) {
+	if args.HistoryDir == "" {
+		return nil, nil
+	}
+	baselineRef := args.HistoryBaseline
+	if baselineRef == "" {
+		baselineRef = args.GitBaseRef
+	}
+	if baselineRef == "" {
+		return nil, nil
+	}
+	history, err := covet.OpenHistory(deps.FS, args.HistoryDir)
+	if err != nil {
+		return nil, err
+	}
+	baselineCommit := baselineRef
+	if commit, err := gitrepo.ResolveCommit(deps.FS, args.DiffBaseDir, baselineRef); err == nil {
+		baselineCommit = commit
+	}
+	snapshot, found, err := history.Find(baselineCommit)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// recordHistory records the current coverage in -history-dir if -history-record is set.
+// If the current commit can't be found, prints a warning and skips recording.
+func recordHistory(cov *covet.Covet, args Args, deps Deps) error {
+	if args.HistoryDir == "" || !args.HistoryRecord {
+		return nil
+	}
+	commit := args.HistoryCommit
+	if commit == "" {
+		var err error
+		commit, err = gitrepo.ResolveCommit(deps.FS, args.DiffBaseDir, "HEAD")
+		if err != nil {
+			fmt.Fprintln(deps.Stderr, "\nFailed to find current commit for -history-dir, skipping. Set -history-commit instead. Error:", err)
+			return nil
+		}
+	}
+	history, err := covet.OpenHistory(deps.FS, args.HistoryDir)
+	if err != nil {
+		return err
+	}
+	return history.Record(cov.Snapshot(commit))
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hack-pad/hackpadfs"
+	"github.com/johnstarich/go/covet"
+	"github.com/johnstarich/go/covet/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+	newFS := func(t *testing.T) hackpadfs.FS {
+		t.Helper()
+		return testhelpers.FSWithFiles(t, map[string]string{
+			"go.mod":  `module github.com/org/repo`,
+			"main.go": "package main\n",
+			"cover.out": `
+mode: set
+github.com/org/repo/main.go:1.1,1.10 1 1
+`,
+			"history/history.jsonl": `{"commit":"base","covered":1,"total":2}` + "\n",
+		})
+	}
+	parse := func(t *testing.T, fs hackpadfs.FS) *covet.Covet {
+		t.Helper()
+		cov, err := covet.Parse(covet.Options{
+			FS:             fs,
+			Diff:           strings.NewReader(""),
+			DiffBaseDir:    ".",
+			GoCoveragePath: "cover.out",
+		})
+		require.NoError(t, err)
+		return cov
+	}
+
+	t.Run("history disabled", func(t *testing.T) {
+		t.Parallel()
+		fs := newFS(t)
+		baseline, err := compareHistory(Args{}, Deps{FS: fs})
+		assert.NoError(t, err)
+		assert.Nil(t, baseline)
+		assert.NoError(t, recordHistory(parse(t, fs), Args{HistoryRecord: true}, Deps{FS: fs}))
+	})
+
+	t.Run("compare and record", func(t *testing.T) {
+		t.Parallel()
+		fs := newFS(t)
+		args := Args{
+			DiffBaseDir:     ".",
+			HistoryDir:      "history",
+			HistoryRecord:   true,
+			HistoryCommit:   "head",
+			HistoryBaseline: "base",
+		}
+		baseline, err := compareHistory(args, Deps{FS: fs})
+		assert.NoError(t, err)
+		assert.Equal(t, &covet.Snapshot{Commit: "base", Covered: 1, Total: 2}, baseline)
+		assert.NoError(t, recordHistory(parse(t, fs), args, Deps{FS: fs}))
+
+		history, err := covet.OpenHistory(fs, "history")
+		require.NoError(t, err)
+		recorded, found, err := history.Find("head")
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, uint(1), recorded.Covered)
+		assert.Equal(t, uint(1), recorded.Total)
+	})
+
+	t.Run("compare without recording", func(t *testing.T) {
+		t.Parallel()
+		fs := newFS(t)
+		args := Args{
+			DiffBaseDir:     ".",
+			HistoryDir:      "history",
+			HistoryCommit:   "head",
+			HistoryBaseline: "base",
+		}
+		baseline, err := compareHistory(args, Deps{FS: fs})
+		assert.NoError(t, err)
+		assert.Equal(t, &covet.Snapshot{Commit: "base", Covered: 1, Total: 2}, baseline)
+		assert.NoError(t, recordHistory(parse(t, fs), args, Deps{FS: fs}))
+
+		history, err := covet.OpenHistory(fs, "history")
+		require.NoError(t, err)
+		_, found, err := history.Find("head")
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("baseline not recorded", func(t *testing.T) {
+		t.Parallel()
+		fs := newFS(t)
+		baseline, err := compareHistory(Args{
+			DiffBaseDir: ".",
+			HistoryDir:  "history",
+			GitBaseRef:  "other",
+		}, Deps{FS: fs})
+		assert.NoError(t, err)
+		assert.Nil(t, baseline)
+	})
+
+	t.Run("current commit not found outside git repository", func(t *testing.T) {
+		t.Parallel()
+		fs := newFS(t)
+		var output bytes.Buffer
+		err := recordHistory(parse(t, fs), Args{
+			DiffBaseDir:   ".",
+			HistoryDir:    "history",
+			HistoryRecord: true,
+		}, Deps{FS: fs, Stderr: &output})
+		assert.NoError(t, err)
+		assert.Contains(t, output.String(), "\nFailed to find current commit for -history-dir, skipping. Set -history-commit instead. Error: failed to open git repository")
+	})
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// commentProvider is a code hosting service which can receive summary comments
+type commentProvider int
+
+const (
+	providerGitHub commentProvider = iota
+	providerGitLab
+	providerBitbucketCloud
+	providerBitbucketServer
+)
+
+func (p commentProvider) String() string {
+	switch p {
+	case providerGitLab:
+		return "GitLab"
+	case providerBitbucketCloud:
+		return "Bitbucket Cloud"
+	case providerBitbucketServer:
+		return "Bitbucket Server"
+	case providerGitHub:
+	}
+	return "GitHub"
+}
+
+// issueURL identifies an issue, pull request, or merge request on a code hosting provider
+type issueURL struct {
+	Provider commentProvider
+	// BaseURL is the scheme, host, and any path prefix before the repository path. e.g. https://example.com/bitbucket
+	BaseURL string
+	// Owner is the GitHub org or user, GitLab namespace, Bitbucket Cloud workspace, or Bitbucket Server project key
+	Owner  string
+	Repo   string
+	Number int
+}
+
+const (
+	gitHubEndpoint           = "https://api.github.com"
+	bitbucketCloudHost       = "bitbucket.org"
+	bitbucketCloudEndpoint   = "https://api.bitbucket.org/2.0"
+	gitLabAPIPath            = "/api/v4"
+	bitbucketServerAPIPath   = "/rest/api/1.0"
+	gitLabMergeRequestsPath  = "merge_requests"
+	bitbucketPullRequestPath = "pull-requests"
+)
+
+// APIEndpoint returns the default REST API endpoint for this URL's provider
+func (u issueURL) APIEndpoint() string {
+	switch u.Provider {
+	case providerGitLab:
+		return u.BaseURL + gitLabAPIPath
+	case providerBitbucketCloud:
+		return bitbucketCloudEndpoint
+	case providerBitbucketServer:
+		return u.BaseURL + bitbucketServerAPIPath
+	case providerGitHub:
+	}
+	return gitHubEndpoint
+}
+
+// parseIssueURL parses issue, pull request, and merge request URLs for all supported providers.
+// The provider is detected from the URL's path, like '/-/merge_requests/' for GitLab. Unrecognized URLs default to GitHub.
+func parseIssueURL(s string) (issueURL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return issueURL{}, err
+	}
+	if u.Scheme == "" {
+		u, err = url.Parse("https://" + s)
+		if err != nil {
+			return issueURL{}, err
+		}
+	}
+	baseURL := u.Scheme + "://" + u.Host
+	tokens := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	// GitLab: group/subgroup/project/-/merge_requests/123
+	for i := 2; i+2 < len(tokens); i++ {
+		if tokens[i] == "-" && tokens[i+1] == gitLabMergeRequestsPath {
+			return newIssueURL(providerGitLab, baseURL, strings.Join(tokens[:i-1], "/"), tokens[i-1], tokens[i+2])
+		}
+	}
+	// Bitbucket Server: [context/]projects/KEY/repos/slug/pull-requests/123
+	for i := 0; i+5 < len(tokens); i++ {
+		if tokens[i] == "projects" && tokens[i+2] == "repos" && tokens[i+4] == bitbucketPullRequestPath {
+			serverURL := strings.TrimSuffix(baseURL+"/"+strings.Join(tokens[:i], "/"), "/")
+			return newIssueURL(providerBitbucketServer, serverURL, tokens[i+1], tokens[i+3], tokens[i+5])
+		}
+	}
+
+	const minIssueURLPathComponents = 4
+	if len(tokens) < minIssueURLPathComponents {
+		return issueURL{}, fmt.Errorf("malformed issue URL: expected 4+ path components, e.g. github.com/org/repo/pull/123")
+	}
+	// Bitbucket Cloud: bitbucket.org/workspace/repo/pull-requests/123
+	if u.Hostname() == bitbucketCloudHost && tokens[2] == bitbucketPullRequestPath {
+		return newIssueURL(providerBitbucketCloud, baseURL, tokens[0], tokens[1], tokens[3])
+	}
+	// GitHub: github.com/org/repo/pull/123
+	return newIssueURL(providerGitHub, baseURL, tokens[0], tokens[1], tokens[3])
+}
+
+func newIssueURL(provider commentProvider, baseURL, owner, repo, number string) (issueURL, error) {
+	n, err := strconv.ParseInt(number, decimalBase, maxIntBits)
+	if err != nil {
+		return issueURL{}, err
+	}
+	return issueURL{
+		Provider: provider,
+		BaseURL:  baseURL,
+		Owner:    owner,
+		Repo:     repo,
+		Number:   int(n),
+	}, nil
+}
+
+// parseIssueURLFlag parses the value of flag 'flagName' as an issue URL, then validates it belongs to one of 'providers'.
+// 'kind' and 'example' describe the expected URL if the provider does not match.
+func parseIssueURLFlag(flagName, value, kind, example string, providers ...commentProvider) (issueURL, error) {
+	if value == "" {
+		return issueURL{}, fmt.Errorf("-%s is required", flagName)
+	}
+	issue, err := parseIssueURL(value)
+	if err != nil {
+		return issueURL{}, err
+	}
+	for _, provider := range providers {
+		if issue.Provider == provider {
+			return issue, nil
+		}
+	}
+	return issueURL{}, fmt.Errorf("-%s must be a %s URL, e.g. %s", flagName, kind, example)
+}
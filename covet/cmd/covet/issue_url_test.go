@@ -0,0 +1,139 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIssueURL(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		url            string
+		expect         issueURL
+		expectEndpoint string
+		expectErr      string
+	}{
+		{
+			url:       "",
+			expectErr: "malformed issue URL: expected 4+ path components, e.g. github.com/org/repo/pull/123",
+		},
+		{
+			url:       "example.com",
+			expectErr: "malformed issue URL: expected 4+ path components, e.g. github.com/org/repo/pull/123",
+		},
+		{
+			url: "github.com/myorg/myrepo/pull/123",
+			expect: issueURL{
+				Provider: providerGitHub,
+				BaseURL:  "https://github.com",
+				Owner:    "myorg",
+				Repo:     "myrepo",
+				Number:   123,
+			},
+			expectEndpoint: "https://api.github.com",
+		},
+		{
+			url: "github.com/myorg/myrepo/pull/123/extra",
+			expect: issueURL{
+				Provider: providerGitHub,
+				BaseURL:  "https://github.com",
+				Owner:    "myorg",
+				Repo:     "myrepo",
+				Number:   123,
+			},
+			expectEndpoint: "https://api.github.com",
+		},
+		{
+			url:       "github.com/myorg/myrepo/pull/not-a-number",
+			expectErr: `strconv.ParseInt: parsing "not-a-number": invalid syntax`,
+		},
+		{
+			url: "https://gitlab.com/group/subgroup/project/-/merge_requests/45",
+			expect: issueURL{
+				Provider: providerGitLab,
+				BaseURL:  "https://gitlab.com",
+				Owner:    "group/subgroup",
+				Repo:     "project",
+				Number:   45,
+			},
+			expectEndpoint: "https://gitlab.com/api/v4",
+		},
+		{
+			url: "gitlab.example.com/group/project/-/merge_requests/45/diffs",
+			expect: issueURL{
+				Provider: providerGitLab,
+				BaseURL:  "https://gitlab.example.com",
+				Owner:    "group",
+				Repo:     "project",
+				Number:   45,
+			},
+			expectEndpoint: "https://gitlab.example.com/api/v4",
+		},
+		{
+			url:       "gitlab.com/group/project/-/merge_requests/not-a-number",
+			expectErr: `strconv.ParseInt: parsing "not-a-number": invalid syntax`,
+		},
+		{
+			url: "https://bitbucket.org/workspace/repo/pull-requests/7",
+			expect: issueURL{
+				Provider: providerBitbucketCloud,
+				BaseURL:  "https://bitbucket.org",
+				Owner:    "workspace",
+				Repo:     "repo",
+				Number:   7,
+			},
+			expectEndpoint: "https://api.bitbucket.org/2.0",
+		},
+		{
+			url: "https://bitbucket.example.com/projects/KEY/repos/repo/pull-requests/8/overview",
+			expect: issueURL{
+				Provider: providerBitbucketServer,
+				BaseURL:  "https://bitbucket.example.com",
+				Owner:    "KEY",
+				Repo:     "repo",
+				Number:   8,
+			},
+			expectEndpoint: "https://bitbucket.example.com/rest/api/1.0",
+		},
+		{
+			url: "http://example.com/bitbucket/projects/KEY/repos/repo/pull-requests/9",
+			expect: issueURL{
+				Provider: providerBitbucketServer,
+				BaseURL:  "http://example.com/bitbucket",
+				Owner:    "KEY",
+				Repo:     "repo",
+				Number:   9,
+			},
+			expectEndpoint: "http://example.com/bitbucket/rest/api/1.0",
+		},
+	} {
+		t.Run(tc.url, func(t *testing.T) {
+			t.Parallel()
+			issue, err := parseIssueURL(tc.url)
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, issue)
+			assert.Equal(t, tc.expectEndpoint, issue.APIEndpoint())
+		})
+	}
+}
+
+func TestParseIssueURLFlag(t *testing.T) {
+	t.Parallel()
+	_, err := parseIssueURLFlag("gitlab-mr", "", "GitLab merge request", "gitlab.com/group/project/-/merge_requests/123", providerGitLab)
+	assert.EqualError(t, err, "-gitlab-mr is required")
+
+	_, err = parseIssueURLFlag("gitlab-mr", "foo", "GitLab merge request", "gitlab.com/group/project/-/merge_requests/123", providerGitLab)
+	assert.EqualError(t, err, "malformed issue URL: expected 4+ path components, e.g. github.com/org/repo/pull/123")
+
+	_, err = parseIssueURLFlag("gitlab-mr", "github.com/org/repo/pull/123", "GitLab merge request", "gitlab.com/group/project/-/merge_requests/123", providerGitLab)
+	assert.EqualError(t, err, "-gitlab-mr must be a GitLab merge request URL, e.g. gitlab.com/group/project/-/merge_requests/123")
+
+	issue, err := parseIssueURLFlag("bitbucket-pr", "bitbucket.example.com/projects/KEY/repos/repo/pull-requests/1", "Bitbucket pull request", "bitbucket.org/workspace/repo/pull-requests/123", providerBitbucketCloud, providerBitbucketServer)
+	assert.NoError(t, err)
+	assert.Equal(t, providerBitbucketServer, issue.Provider)
+}
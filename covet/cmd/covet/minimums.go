@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/johnstarich/go/covet"
+	"github.com/johnstarich/go/covet/internal/summary"
+	"github.com/pkg/errors"
+)
+
+// fileMinimum is a minimum diff coverage percentage for files matching Pattern.
+// An empty Pattern matches all files.
+type fileMinimum struct {
+	Pattern string
+	Minimum uint
+}
+
+// fileMinimums is a flag.Value for repeated '[pattern=]percent' minimums. Later matching patterns take precedence.
+type fileMinimums []fileMinimum
+
+func (m *fileMinimums) String() string {
+	if m == nil {
+		return ""
+	}
+	var values []string
+	for _, f := range *m {
+		value := strconv.FormatUint(uint64(f.Minimum), decimalBase)
+		if f.Pattern != "" {
+			value = f.Pattern + "=" + value
+		}
+		values = append(values, value)
+	}
+	return strings.Join(values, ",")
+}
+
+func (m *fileMinimums) Set(value string) error {
+	var f fileMinimum
+	percent := value
+	if i := strings.LastIndex(value, "="); i != -1 {
+		f.Pattern, percent = value[:i], value[i+1:]
+		if _, err := path.Match(f.Pattern, ""); err != nil {
+			return errors.Wrapf(err, "invalid file pattern %q", f.Pattern)
+		}
+	}
+	minimum, err := parsePercent(percent)
+	if err != nil {
+		return err
+	}
+	f.Minimum = minimum
+	*m = append(*m, f)
+	return nil
+}
+
+func parsePercent(s string) (uint, error) {
+	const percentBits = 32
+	percent, err := strconv.ParseUint(s, decimalBase, percentBits)
+	if err != nil || percent > maxPercentInt {
+		return 0, errors.Errorf("invalid percentage %q: must be a number between 0 and 100", s)
+	}
+	return uint(percent), nil
+}
+
+// Minimum returns the minimum coverage percentage for the given file name, if any
+func (m fileMinimums) Minimum(name string) uint {
+	var minimum uint
+	for _, f := range m {
+		if f.Pattern == "" {
+			minimum = f.Minimum
+		} else if matched, _ := path.Match(f.Pattern, name); matched { // pattern is validated in Set()
+			minimum = f.Minimum
+		}
+	}
+	return minimum
+}
+
+// coverageFailure describes a total, file, or owner diff coverage percentage below its required minimum
+type coverageFailure struct {
+	File     covet.File // empty for the total or owner diff coverage
+	Owner    *covet.Group
+	Coverage float64
+	Minimum  uint
+}
+
+func (f coverageFailure) Error() string {
+	name := "total"
+	switch {
+	case f.File.Name != "":
+		name = f.File.Name
+	case f.Owner != nil && f.Owner.Name == "":
+		name = "unowned"
+	case f.Owner != nil:
+		name = "owner " + f.Owner.Name
+	}
+	return fmt.Sprintf("%s diff coverage %s is below the required minimum %d%%", name, strings.TrimSpace(summary.FormatPercent(f.Coverage)), f.Minimum)
+}
+
+// findCoverageFailures returns all failures to meet the minimum total, per-file, and per-owner diff coverage
+func findCoverageFailures(cov *covet.Covet, args Args) ([]coverageFailure, error) {
+	var failures []coverageFailure
+	files := cov.DiffCoverageFiles()
+	if len(files) == 0 {
+		return nil, nil
+	}
+	if total := cov.DiffCovered(); belowMinimum(total, args.MinDiffCoverage) {
+		failures = append(failures, coverageFailure{Coverage: total, Minimum: args.MinDiffCoverage})
+	}
+	for _, f := range sortedFiles(files) {
+		minimum := args.MinFileDiffCoverage.Minimum(f.Name)
+		if coverage := summary.FileCoverage(f); belowMinimum(coverage, minimum) {
+			failures = append(failures, coverageFailure{File: f, Coverage: coverage, Minimum: minimum})
+		}
+	}
+	if len(args.MinOwnerDiffCoverage) == 0 {
+		return failures, nil
+	}
+	owners, err := cov.DiffCoverageByOwner()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(owners, func(a, b int) bool {
+		return owners[a].Name < owners[b].Name
+	})
+	for i := range owners {
+		owner := &owners[i]
+		minimum := args.MinOwnerDiffCoverage.Minimum(owner.Name)
+		if coverage := owner.Coverage(); belowMinimum(coverage, minimum) {
+			failures = append(failures, coverageFailure{Owner: owner, Coverage: coverage, Minimum: minimum})
+		}
+	}
+	return failures, nil
+}
+
+func belowMinimum(coverage float64, minimum uint) bool {
+	return coverage < float64(minimum)/maxPercentInt
+}
+
+func sortedFiles(files []covet.File) []covet.File {
+	sorted := append([]covet.File(nil), files...)
+	sort.Slice(sorted, func(a, b int) bool {
+		return sorted[a].Name < sorted[b].Name
+	})
+	return sorted
+}
+
+func coverageFailuresError(failures []coverageFailure) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	var sb strings.Builder
+	sb.WriteString("diff coverage is below the required minimum:")
+	for _, f := range failures {
+		sb.WriteString("\n- ")
+		sb.WriteString(f.Error())
+	}
+	return errors.New(sb.String())
+}
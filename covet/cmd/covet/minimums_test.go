@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileMinimums(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		description   string
+		values        []string
+		file          string
+		expectMinimum uint
+		expectString  string
+		expectErr     string
+	}{
+		{
+			description:   "no minimums",
+			file:          "foo.go",
+			expectMinimum: 0,
+		},
+		{
+			description:   "all files",
+			values:        []string{"80"},
+			file:          "foo.go",
+			expectMinimum: 80,
+			expectString:  "80",
+		},
+		{
+			description:   "later patterns take precedence",
+			values:        []string{"80", "internal/*.go=50", "internal/bar.go=90"},
+			file:          "internal/foo.go",
+			expectMinimum: 50,
+			expectString:  "80,internal/*.go=50,internal/bar.go=90",
+		},
+		{
+			description:   "non-matching pattern",
+			values:        []string{"cmd/*.go=50"},
+			file:          "internal/foo.go",
+			expectMinimum: 0,
+			expectString:  "cmd/*.go=50",
+		},
+		{
+			description:   "owner pattern",
+			values:        []string{"@org/*=60"},
+			file:          "@org/web",
+			expectMinimum: 60,
+			expectString:  "@org/*=60",
+		},
+		{
+			description:   "owner pattern does not match slash",
+			values:        []string{"@org*=60"},
+			file:          "@org/web",
+			expectMinimum: 0,
+			expectString:  "@org*=60",
+		},
+		{
+			description: "invalid percent",
+			values:      []string{"101"},
+			expectErr:   `invalid percentage "101": must be a number between 0 and 100`,
+		},
+		{
+			description: "invalid pattern",
+			values:      []string{"[=50"},
+			expectErr:   `invalid file pattern "[": syntax error in pattern`,
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			var minimums fileMinimums
+			for _, value := range tc.values {
+				err := minimums.Set(value)
+				if tc.expectErr != "" {
+					assert.EqualError(t, err, tc.expectErr)
+					return
+				}
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectMinimum, minimums.Minimum(tc.file))
+			assert.Equal(t, tc.expectString, minimums.String())
+		})
+	}
+}
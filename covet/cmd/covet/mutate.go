@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hack-pad/hackpadfs"
+	"github.com/johnstarich/go/covet"
+	"github.com/pkg/errors"
+)
+
+// mutantOutcome is the result of running a package's tests with a mutated file
+type mutantOutcome int
+
+const (
+	// mutantSurvived means tests passed
+	mutantSurvived mutantOutcome = iota
+	// mutantKilled means tests failed or timed out
+	mutantKilled
+	// mutantInvalid means the mutated package failed to build, so the mutant is skipped
+	mutantInvalid
+)
+
+// mutantTester runs the tests for the package containing FS path 'file', with the file's contents replaced by 'src'
+type mutantTester func(ctx context.Context, file string, src []byte) (mutantOutcome, error)
+
+// testMutants runs the tests of each mutated package once per mutant and returns the results.
+// Each package's tests must pass without mutations first, otherwise every mutant would appear killed.
+func testMutants(ctx context.Context, cov *covet.Covet, args Args, deps Deps) ([]covet.MutantResult, error) {
+	mutants, err := cov.Mutants()
+	if err != nil {
+		return nil, err
+	}
+	results := []covet.MutantResult{}
+	testedPackages := make(map[string]bool)
+	for _, mutant := range mutants {
+		if pkg := path.Dir(mutant.Path()); !testedPackages[pkg] {
+			testedPackages[pkg] = true
+			src, err := hackpadfs.ReadFile(deps.FS, mutant.Path())
+			if err != nil {
+				return nil, err
+			}
+			outcome, err := runMutantTest(ctx, deps.TestMutant, args, mutant.Path(), src)
+			if err != nil {
+				return nil, err
+			}
+			if outcome != mutantSurvived {
+				return nil, errors.Errorf("tests must pass without mutations, but failed for package directory %s", path.Dir(mutant.File))
+			}
+		}
+
+		src, err := cov.MutatedSource(mutant)
+		if err != nil {
+			return nil, err
+		}
+		outcome, err := runMutantTest(ctx, deps.TestMutant, args, mutant.Path(), src)
+		if err != nil {
+			return nil, err
+		}
+		if outcome != mutantInvalid {
+			results = append(results, covet.MutantResult{
+				Mutant: mutant,
+				Killed: outcome == mutantKilled,
+			})
+		}
+	}
+	return results, nil
+}
+
+func runMutantTest(ctx context.Context, test mutantTester, args Args, file string, src []byte) (mutantOutcome, error) {
+	if args.MutateTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, args.MutateTimeout)
+		defer cancel()
+	}
+	return test(ctx, file, src)
+}
+
+// osPathFS is an FS which converts FS paths to OS paths, like hackpadfs's os.FS
+type osPathFS interface {
+	ToOSPath(fsPath string) (string, error)
+}
+
+// goTestMutant returns a mutantTester which runs 'go test' in the file's package directory.
+// The mutated source is swapped in with 'go test -overlay', so source files are never modified.
+func goTestMutant(fs hackpadfs.FS) mutantTester {
+	return func(ctx context.Context, file string, src []byte) (mutantOutcome, error) {
+		osFS, ok := fs.(osPathFS)
+		if !ok {
+			return 0, errors.New("mutation testing requires an OS file system")
+		}
+		osPath, err := osFS.ToOSPath(file)
+		if err != nil {
+			return 0, err
+		}
+		tempDir, err := os.MkdirTemp("", "covet-mutant-")
+		if err != nil {
+			return 0, err
+		}
+		defer os.RemoveAll(tempDir)
+
+		const filePerm = 0o600
+		mutatedPath := filepath.Join(tempDir, filepath.Base(osPath))
+		if err := os.WriteFile(mutatedPath, src, filePerm); err != nil {
+			return 0, err
+		}
+		overlay, err := json.Marshal(map[string]interface{}{
+			"Replace": map[string]string{osPath: mutatedPath},
+		})
+		if err != nil {
+			return 0, err
+		}
+		overlayPath := filepath.Join(tempDir, "overlay.json")
+		if err := os.WriteFile(overlayPath, overlay, filePerm); err != nil {
+			return 0, err
+		}
+
+		cmd := exec.CommandContext(ctx, "go", "test", "-count=1", "-failfast", "-overlay="+overlayPath, ".")
+		cmd.Dir = filepath.Dir(osPath)
+		output, err := cmd.CombinedOutput()
+		var exitErr *exec.ExitError
+		switch {
+		case err == nil:
+			return mutantSurvived, nil
+		case ctx.Err() != nil:
+			return mutantKilled, nil // timed out, like an infinite loop
+		case errors.As(err, &exitErr) && isBuildFailure(string(output)):
+			return mutantInvalid, nil
+		case errors.As(err, &exitErr):
+			return mutantKilled, nil
+		default:
+			return 0, fmt.Errorf("failed to run go test: %w", err)
+		}
+	}
+}
+
+func isBuildFailure(output string) bool {
+	return strings.Contains(output, "[build failed]") || strings.Contains(output, "[setup failed]")
+}
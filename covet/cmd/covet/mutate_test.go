@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hack-pad/hackpadfs"
+	"github.com/hack-pad/hackpadfs/mem"
+	"github.com/hack-pad/hackpadfs/os"
+	"github.com/johnstarich/go/covet/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	mutantsPatch = `
+diff --git a/max.go b/max.go
+index 0000000..1111111 100644
+--- a/max.go
++++ b/max.go
+@@ -0,0 +1,8 @@
++package repo
++
++func Max(a, b int) int {
++	if a < b {
++		return b
++	}
++	return a
++}
+`
+	mutantsSource = `package repo
+
+func Max(a, b int) int {
+	if a < b {
+		return b
+	}
+	return a
+}
+`
+	mutantsCoverage = `
+mode: set
+github.com/org/repo/max.go:3.24,4.11 1 1
+github.com/org/repo/max.go:4.11,6.3 1 1
+github.com/org/repo/max.go:7.2,7.10 1 1
+`
+)
+
+func TestRunArgsMutate(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		description string
+		outcomes    map[string]mutantOutcome
+		expectOut   string
+		expectErr   string
+	}{
+		{
+			description: "report survived mutants",
+			outcomes: map[string]mutantOutcome{
+				mutantsSource: mutantSurvived,
+				strings.Replace(mutantsSource, "a < b", "!(a < b)", 1): mutantKilled,
+				strings.Replace(mutantsSource, "a < b", "a >= b", 1):   mutantSurvived,
+			},
+			expectOut: `
+Total diff coverage: 100.0%
+
+Successfully reached diff coverage target: >90%
+
+Total coverage of packages in the diff:
+┌────────┬────────┬─────────┐
+│ DIFF   │ TOTAL  │ PACKAGE │
+├────────┼────────┼─────────┤
+│ 100.0% │ 100.0% │ .       │
+└────────┴────────┴─────────┘
+
+Mutation testing killed 1 of 2 mutants (50.0%).
+Survived mutants, tests still pass with these changes:
+┌──────────┬──────────────────┐
+│ LINE     │ MUTATION         │
+├──────────┼──────────────────┤
+│ max.go:4 │ flip '<' to '>=' │
+└──────────┴──────────────────┘
+`,
+		},
+		{
+			description: "skip mutants which fail to build",
+			outcomes: map[string]mutantOutcome{
+				mutantsSource: mutantSurvived,
+				strings.Replace(mutantsSource, "a < b", "!(a < b)", 1): mutantKilled,
+				strings.Replace(mutantsSource, "a < b", "a >= b", 1):   mutantInvalid,
+			},
+			expectOut: `
+Total diff coverage: 100.0%
+
+Successfully reached diff coverage target: >90%
+
+Total coverage of packages in the diff:
+┌────────┬────────┬─────────┐
+│ DIFF   │ TOTAL  │ PACKAGE │
+├────────┼────────┼─────────┤
+│ 100.0% │ 100.0% │ .       │
+└────────┴────────┴─────────┘
+
+Mutation testing killed 1 of 1 mutants (100.0%).
+`,
+		},
+		{
+			description: "tests fail without mutations",
+			outcomes: map[string]mutantOutcome{
+				mutantsSource: mutantKilled,
+			},
+			expectErr: "tests must pass without mutations, but failed for package directory .",
+		},
+	} {
+		tc := tc // enable parallel sub-tests
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			fs := testhelpers.FSWithFiles(t, map[string]string{
+				"my.patch":  mutantsPatch,
+				"cover.out": mutantsCoverage,
+				"go.mod":    `module github.com/org/repo`,
+				"max.go":    mutantsSource,
+			})
+			var output bytes.Buffer
+			deps := Deps{
+				Stdout: &output,
+				FS:     fs,
+				TestMutant: func(_ context.Context, file string, src []byte) (mutantOutcome, error) {
+					assert.Equal(t, "max.go", file)
+					outcome, ok := tc.outcomes[string(src)]
+					require.True(t, ok, "Unexpected mutated source:\n%s", src)
+					return outcome, nil
+				},
+			}
+			err := runArgs(Args{
+				DiffFile:           "my.patch",
+				DiffBaseDir:        ".",
+				GoCoverageFiles:    []string{"cover.out"},
+				TargetDiffCoverage: 90,
+				Mutate:             true,
+			}, deps)
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, strings.TrimSpace(tc.expectOut), strings.TrimSpace(output.String()))
+		})
+	}
+}
+
+func TestGoTestMutant(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found:", err)
+	}
+
+	dir := t.TempDir()
+	osFS := os.NewFS()
+	fsDir, err := osFS.FromOSPath(dir)
+	require.NoError(t, err)
+	files := map[string]string{
+		"go.mod": "module github.com/org/repo\n",
+		"max.go": mutantsSource,
+		"max_test.go": `package repo
+
+import "testing"
+
+func TestMax(t *testing.T) {
+	if Max(1, 2) != 2 {
+		t.Error("expected 2")
+	}
+}
+`,
+	}
+	for name, contents := range files {
+		require.NoError(t, hackpadfs.WriteFullFile(osFS, filepath.ToSlash(filepath.Join(fsDir, name)), []byte(contents), 0o600))
+	}
+	file := fsDir + "/max.go"
+
+	test := goTestMutant(osFS)
+	for _, tc := range []struct {
+		description string
+		src         string
+		expect      mutantOutcome
+	}{
+		{description: "original", src: mutantsSource, expect: mutantSurvived},
+		{description: "killed", src: strings.Replace(mutantsSource, "a < b", "a >= b", 1), expect: mutantKilled},
+		{description: "survived", src: strings.Replace(mutantsSource, "a < b", "a <= b", 1), expect: mutantSurvived},
+		{description: "invalid", src: strings.Replace(mutantsSource, "a < b", "a < ", 1), expect: mutantInvalid},
+	} {
+		outcome, err := test(context.Background(), file, []byte(tc.src))
+		assert.NoError(t, err, tc.description)
+		assert.Equal(t, tc.expect, outcome, tc.description)
+	}
+	contents, err := hackpadfs.ReadFile(osFS, file)
+	require.NoError(t, err)
+	assert.Equal(t, mutantsSource, string(contents), "Source file should not be modified")
+
+	memFS, err := mem.NewFS()
+	require.NoError(t, err)
+	_, err = goTestMutant(memFS)(context.Background(), "max.go", nil)
+	assert.EqualError(t, err, "mutation testing requires an OS file system")
+}
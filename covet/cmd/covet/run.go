@@ -6,15 +6,16 @@ import (
 	"flag"
 	"fmt"
 	"io"
-	"net/url"
 	"path/filepath"
+	"regexp"
 	"sort"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hack-pad/hackpadfs"
 	"github.com/hack-pad/hackpadfs/os"
 	"github.com/johnstarich/go/covet"
+	"github.com/johnstarich/go/covet/internal/coverfile"
 	"github.com/johnstarich/go/covet/internal/coverstatus"
 	"github.com/johnstarich/go/covet/internal/fspath"
 	"github.com/johnstarich/go/covet/internal/span"
@@ -24,17 +25,63 @@ import (
 
 const maxPercentInt = 100
 
+//nolint:gochecknoglobals // Read-only regular expression
+var coverageLabelPattern = regexp.MustCompile(`^([A-Za-z0-9_-]+)=(.+)$`)
+
 // Args contains all flag values for a covet run
 type Args struct {
+	// Serve starts an interactive coverage viewer on ServeAddr instead of printing a report, set by 'covet serve'
+	Serve     bool
+	ServeAddr string
+
+	ConfigFile         string
 	DiffFile           string
 	DiffBaseDir        string
-	GoCoverageFile     string
+	GitBaseRef         string
+	GitHeadRef         string
+	GitMergeBase       bool
+	GoCoverageFiles    []string
+	CoverageFiles      []string
+	IgnorePatterns     []string
+	IncludeGenerated   bool
 	ShowCoverage       bool
+	OnlyLabel          string
+	ShowHits           bool
+	MinHits            uint
+	ShowFunctions      bool
+	SuggestTests       bool
+	Mutate             bool
+	MutateTimeout      time.Duration
 	TargetDiffCoverage uint
+	Format             summary.Format
+	ContextLines       uint
+
+	HistoryDir      string
+	HistoryRecord   bool
+	HistoryCommit   string
+	HistoryBaseline string
+
+	MinDiffCoverage     uint
+	MinFileDiffCoverage fileMinimums
+	// MinOwnerDiffCoverage uses path patterns to match owner names, like '@org/team=80' or '@org/*=80'
+	MinOwnerDiffCoverage fileMinimums
+
+	GroupByPackage bool
+	GroupByOwner   bool
+	CodeOwnersFile string
 
 	GitHubToken    string
 	GitHubIssue    string
 	GitHubEndpoint string
+	GitHubReview   bool
+
+	GitLabToken        string
+	GitLabMergeRequest string
+	GitLabEndpoint     string
+
+	BitbucketToken       string
+	BitbucketPullRequest string
+	BitbucketEndpoint    string
 }
 
 func run(
@@ -54,34 +101,119 @@ func run(
 	deps := Deps{
 		Stdin:  stdin,
 		Stdout: stdout,
+		Stderr: stderr,
 		FS:     fs,
 	}
 	return runArgs(args, deps)
 }
 
 func parseArgs(strArgs []string, output io.Writer) (Args, error) {
-	const defaultTargetDiffCov = 90
+	const (
+		defaultTargetDiffCov = 90
+		defaultContextLines  = 2
+		defaultMutateTimeout = 5 * time.Minute
+		defaultServeAddr     = "localhost:8080"
+	)
 	var args Args
-	set := flag.NewFlagSet("covet", flag.ContinueOnError)
+	name := "covet"
+	if len(strArgs) > 0 && strArgs[0] == "serve" {
+		args.Serve = true
+		strArgs = strArgs[1:]
+		name = "covet serve"
+	}
+	set := flag.NewFlagSet(name, flag.ContinueOnError)
 	set.SetOutput(output)
-	set.StringVar(&args.DiffFile, "diff-file", "", "Required. Path to a diff file. Use '-' for stdin.")
+	if args.Serve {
+		set.StringVar(&args.ServeAddr, "addr", defaultServeAddr, "Address for the coverage viewer to listen on.")
+	}
+	set.StringVar(&args.ConfigFile, "config", "", "Path to a YAML or TOML config file. Keys are flag names without the dash, like 'target-diff-coverage: 80'. Relative paths start from the config file's directory. Flags take precedence over the config file. Defaults to .covet.yaml, .covet.yml, or .covet.toml in -diff-base-dir.")
+	set.StringVar(&args.DiffFile, "diff-file", "", "Path to a diff file. Use '-' for stdin. Required unless -git-base is set.")
 	set.StringVar(&args.DiffBaseDir, "diff-base-dir", ".", "Path to the diff's base directory. Defaults to the current directory.")
-	set.StringVar(&args.GoCoverageFile, "cover-go", "", "Required. Path to a Go coverage profile.")
+	set.StringVar(&args.GitBaseRef, "git-base", "", "Git revision to compare against, like 'origin/main'. Computes the diff from the git repository containing -diff-base-dir instead of reading -diff-file. Only files inside -diff-base-dir are compared.")
+	set.StringVar(&args.GitHeadRef, "git-head", "", "Git revision with new changes, like 'HEAD'. Defaults to the working tree, including uncommitted changes to tracked files.")
+	set.BoolVar(&args.GitMergeBase, "git-merge-base", false, "Compare against the merge base of -git-base and -git-head, like 'git diff base...head'.")
+	set.Var((*stringSliceFlag)(&args.GoCoverageFiles), "cover-go", "Path to a Go coverage profile, or a GOCOVERDIR directory from a binary built with 'go build -cover'. Repeat the flag or separate paths with commas to merge multiple profiles. Prefix a path with a label to break down coverage by kind of test, like 'unit=cover.out,integration=integ.out'. Suffix a path with its module's directory if the profile is somewhere else, like './out/a.out=./a'. Existing files named like a label are not split. Required unless -cover is set.")
+	set.Var((*stringSliceFlag)(&args.CoverageFiles), "cover", "Path to a coverage file in any supported format: Go, LCOV, or Cobertura XML. The format is detected from the file's contents. Relative paths inside the file start from -diff-base-dir, or from a directory suffix like 'web/lcov.info=web'. Repeat the flag or separate paths with commas to merge with other profiles. Supports labels like -cover-go.")
+	set.Var((*stringSliceFlag)(&args.IgnorePatterns), "ignore", "Path pattern for files to exclude from diff coverage, relative to -diff-base-dir. Supports '**' to match any number of directories, like 'vendor/**'. Patterns without a slash match file names in any directory, like '*.pb.go'. Repeat the flag or separate patterns with commas to add more.")
+	set.BoolVar(&args.IncludeGenerated, "include-generated", false, "Include Go files with a '// Code generated ... DO NOT EDIT.' header. Generated files are excluded by default.")
 	set.BoolVar(&args.ShowCoverage, "show-diff-coverage", false, "Show the coverage diff in addition to the summary.")
+	set.BoolVar(&args.ShowHits, "show-hits", false, "Show how many times each covered line ran in -show-diff-coverage, HTML, and JSON reports. Requires 'count' or 'atomic' mode Go coverage profiles, like 'go test -covermode=count'.")
+	set.UintVar(&args.MinHits, "min-hits", 0, "Report covered lines which ran fewer than this many times as weakly covered. Requires 'count' or 'atomic' mode Go coverage profiles.")
+	set.StringVar(&args.OnlyLabel, "only-label", "", "Mark lines covered only by coverage profiles with this label in -show-diff-coverage, like 'integration'.")
+	set.BoolVar(&args.ShowFunctions, "show-functions", false, "Show Go functions and methods with uncovered lines in the summary, like '(*Server).Handle'.")
+	set.BoolVar(&args.SuggestTests, "suggest-tests", false, "Suggest where to add tests for each Go function with uncovered lines, like 'add a case to TestHandle in server_test.go'.")
+	set.BoolVar(&args.Mutate, "mutate", false, "Run mutation testing on covered Go lines in the diff. Flips comparisons, negates conditions, and replaces returned values one at a time, then runs 'go test' for the mutated package. Reports mutants which survive, where tests still pass.")
+	set.DurationVar(&args.MutateTimeout, "mutate-timeout", defaultMutateTimeout, "Maximum time to run a package's tests for each mutant. Mutants which time out count as killed.")
 	set.UintVar(&args.TargetDiffCoverage, "target-diff-coverage", defaultTargetDiffCov, "Target total test coverage of new lines. Reports the biggest gaps needed to reach the target. Any number between 0 and 100.")
+	set.Func("min-diff-coverage", "Minimum total test coverage of new lines. Exits with a non-zero status if coverage is below this minimum. Any number between 0 and 100.", func(s string) error {
+		var err error
+		args.MinDiffCoverage, err = parsePercent(s)
+		return err
+	})
+	set.Var(&args.MinFileDiffCoverage, "min-file-diff-coverage", "Minimum test coverage of new lines in each file. Exits with a non-zero status if any file is below its minimum. Use 'percent' for all files or 'pattern=percent' for files matching a path pattern, like 'internal/*.go=80'. May be repeated, later matches take precedence.")
+	set.Var(&args.MinOwnerDiffCoverage, "min-owner-diff-coverage", "Minimum test coverage of new lines owned by each code owner in CODEOWNERS. Exits with a non-zero status if any owner is below its minimum. Use 'percent' for all owners or 'owner=percent' for one owner, like '@org/team=80'. Owners are matched as path patterns, so '@org/*=80' matches every team in @org, but '*' does not match '/'. May be repeated, later matches take precedence.")
+	set.Func("group-by", "Add diff coverage tables grouped by 'package' or 'owner' to the summary. Owners are read from CODEOWNERS. Repeat the flag or separate values with commas for both.", func(s string) error {
+		for _, group := range strings.Split(s, ",") {
+			switch group {
+			case "package":
+				args.GroupByPackage = true
+			case "owner":
+				args.GroupByOwner = true
+			default:
+				return fmt.Errorf("unsupported group %q, must be one of: package, owner", group)
+			}
+		}
+		return nil
+	})
+	set.StringVar(&args.CodeOwnersFile, "codeowners", "", "Path to a CODEOWNERS file. Defaults to .github/CODEOWNERS, CODEOWNERS, or docs/CODEOWNERS in -diff-base-dir.")
+	set.Func("format", "Output format for the report. One of: terminal, markdown, json, sarif, html. Defaults to terminal.", func(s string) error {
+		var err error
+		args.Format, err = summary.ParseFormat(s)
+		return err
+	})
+	set.UintVar(&args.ContextLines, "context-lines", defaultContextLines, "Number of unchanged lines to show around each diff line in HTML reports.")
+	set.StringVar(&args.HistoryDir, "history-dir", "", "Directory to store coverage history. Compares coverage against the baseline's recorded coverage. Use -history-record to record the current commit.")
+	set.BoolVar(&args.HistoryRecord, "history-record", false, "Record total and per-package coverage for the current commit in -history-dir. Typically set only for runs on the default branch, so pull requests are compared against merged code.")
+	set.StringVar(&args.HistoryCommit, "history-commit", "", "Commit to record in -history-dir with -history-record. Defaults to the git repository's HEAD commit at -diff-base-dir.")
+	set.StringVar(&args.HistoryBaseline, "history-baseline", "", "Git revision or commit to compare coverage against, using history from -history-dir. Defaults to -git-base. Skipped if the baseline has no recorded coverage.")
 	set.StringVar(&args.GitHubToken, "gh-token", "", "GitHub access token to post and update a PR comment. If running in GitHub Actions, a comment may not be necessary.")
-	set.StringVar(&args.GitHubEndpoint, "gh-api", "https://api.github.com", "GitHub API endpoint. Required for GitHub Enterprise.")
+	set.StringVar(&args.GitHubEndpoint, "gh-api", gitHubEndpoint, "GitHub API endpoint. Required for GitHub Enterprise.")
 	set.StringVar(&args.GitHubIssue, "gh-issue", "", "GitHub issue or pull request URL. Example: github.com/org/repo/pull/123. Typically inside a CI environment variable.")
+	set.BoolVar(&args.GitHubReview, "gh-review", false, "Post a pull request review with inline comments on uncovered lines. Comments from previous runs are updated in place, or edited to note the lines are covered or no longer in the diff. Review threads are not resolved. Requires -gh-token and a pull request URL for -gh-issue.")
+	set.StringVar(&args.GitLabToken, "gitlab-token", "", "GitLab access token to post and update a merge request comment.")
+	set.StringVar(&args.GitLabEndpoint, "gitlab-api", "", "GitLab API endpoint. Defaults to the merge request's host, e.g. https://gitlab.com/api/v4.")
+	set.StringVar(&args.GitLabMergeRequest, "gitlab-mr", "", "GitLab merge request URL. Example: gitlab.com/group/project/-/merge_requests/123. Typically inside a CI environment variable.")
+	set.StringVar(&args.BitbucketToken, "bitbucket-token", "", "Bitbucket access token to post and update a pull request comment. Use 'username:app-password' for basic auth.")
+	set.StringVar(&args.BitbucketEndpoint, "bitbucket-api", "", "Bitbucket API endpoint. Defaults to https://api.bitbucket.org/2.0 for Bitbucket Cloud, or the pull request's host for Bitbucket Server.")
+	set.StringVar(&args.BitbucketPullRequest, "bitbucket-pr", "", "Bitbucket pull request URL. Example: bitbucket.org/workspace/repo/pull-requests/123 or bitbucket.example.com/projects/KEY/repos/repo/pull-requests/123.")
 	err := set.Parse(strArgs)
 	if err != nil {
 		return Args{}, err
 	}
+	if args.ConfigFile == "" {
+		args.ConfigFile, err = findConfigFile(args.DiffBaseDir)
+	}
+	if err == nil && args.ConfigFile != "" {
+		err = applyConfigFile(set, args.ConfigFile)
+	}
+	if err != nil {
+		return Args{}, err
+	}
 
 	set.VisitAll(func(f *flag.Flag) {
 		if err == nil && strings.HasPrefix(f.Usage, "Required.") && f.Value.String() == "" {
 			err = fmt.Errorf("flag -%s is required", f.Name)
 		}
 	})
+	switch {
+	case err != nil:
+	case len(args.GoCoverageFiles) == 0 && len(args.CoverageFiles) == 0:
+		err = errors.New("flag -cover-go or -cover is required")
+	case args.DiffFile == "" && args.GitBaseRef == "":
+		err = errors.New("flag -diff-file or -git-base is required")
+	case args.DiffFile != "" && args.GitBaseRef != "":
+		err = errors.New("flags -diff-file and -git-base are mutually exclusive")
+	}
 	if err != nil {
 		set.Usage()
 		return Args{}, err
@@ -91,20 +223,105 @@ func parseArgs(strArgs []string, output io.Writer) (Args, error) {
 	if err != nil {
 		return args, err
 	}
-	if args.DiffFile != "-" {
+	if args.DiffFile != "" && args.DiffFile != "-" {
 		args.DiffFile = toFSPathSetErr(osFS, args.DiffFile, &err)
 	}
 	args.DiffBaseDir = toFSPathSetErr(osFS, args.DiffBaseDir, &err)
-	args.GoCoverageFile = toFSPathSetErr(osFS, args.GoCoverageFile, &err)
+	if args.HistoryDir != "" {
+		args.HistoryDir = toFSPathSetErr(osFS, args.HistoryDir, &err)
+	}
+	if args.CodeOwnersFile != "" {
+		args.CodeOwnersFile = toFSPathSetErr(osFS, args.CodeOwnersFile, &err)
+	}
+	for i := range args.GoCoverageFiles {
+		args.GoCoverageFiles[i] = toLabelledFSPathSetErr(osFS, args.GoCoverageFiles[i], &err)
+	}
+	for i := range args.CoverageFiles {
+		args.CoverageFiles[i] = toLabelledFSPathSetErr(osFS, args.CoverageFiles[i], &err)
+	}
 	return args, err
 }
 
+// stringSliceFlag is a flag.Value which collects repeated flags and comma-separated values
+type stringSliceFlag []string
+
+func (s *stringSliceFlag) String() string {
+	if s == nil {
+		return ""
+	}
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSliceFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v != "" {
+			*s = append(*s, v)
+		}
+	}
+	return nil
+}
+
 func toFSPathSetErr(fs *os.FS, p string, err *error) string {
 	p, pathErr := toFSPath(fs, p)
 	setErr(pathErr, err)
 	return p
 }
 
+// toLabelledFSPathSetErr is like toFSPathSetErr, but preserves a coverage label prefix. See splitCoverageLabel.
+// Existing files with a label-like name, like 'unit=cover.out', are not split.
+func toLabelledFSPathSetErr(fs *os.FS, p string, err *error) string {
+	if fsPath, pathErr := toFSPath(fs, p); pathErr == nil {
+		if _, statErr := hackpadfs.Stat(fs, fsPath); statErr == nil {
+			return fsPath
+		}
+	}
+	arg := parseCoverageArg(p)
+	arg.Path = toFSPathSetErr(fs, arg.Path, err)
+	if arg.BaseDir != "" {
+		arg.BaseDir = toFSPathSetErr(fs, arg.BaseDir, err)
+	}
+	return arg.String()
+}
+
+// splitCoverageLabel splits a coverage file argument into an optional label and path, like 'unit=cover.out'
+func splitCoverageLabel(s string) (label, p string) {
+	match := coverageLabelPattern.FindStringSubmatch(s)
+	if match == nil {
+		return "", s
+	}
+	return match[1], match[2]
+}
+
+// coverageArg is a coverage file argument, with an optional label and base directory
+type coverageArg struct {
+	Label   string
+	Path    string
+	BaseDir string
+}
+
+// parseCoverageArg parses a coverage file argument formatted as '[label=]path[=basedir]', like 'unit=a/cover.out=a'.
+// The first part is only a label if it looks like one, so 'a/cover.out=a' is a path and base directory.
+// Prefix label-like paths with './' to set only a base directory, like './cover=a'.
+func parseCoverageArg(s string) coverageArg {
+	label, p := splitCoverageLabel(s)
+	arg := coverageArg{Label: label, Path: p}
+	if i := strings.LastIndex(p, "="); i != -1 {
+		arg.Path, arg.BaseDir = p[:i], p[i+1:]
+	}
+	return arg
+}
+
+func (a coverageArg) String() string {
+	s := a.Path
+	if a.Label != "" {
+		s = a.Label + "=" + s
+	}
+	if a.BaseDir != "" {
+		s += "=" + a.BaseDir
+	}
+	return s
+}
+
 func setErr(err error, setErr *error) {
 	if err != nil && *setErr == nil {
 		*setErr = err
@@ -123,43 +340,175 @@ func toFSPath(fs *os.FS, p string) (string, error) {
 type Deps struct {
 	Stdin  io.Reader
 	Stdout io.Writer
+	// Stderr receives warnings, keeping Stdout free for reports like JSON and SARIF
+	Stderr io.Writer
 	FS     hackpadfs.FS
+	// TestMutant runs a package's tests with a mutated file. Defaults to running 'go test'.
+	TestMutant mutantTester
 }
 
 func runArgs(args Args, deps Deps) (err error) {
 	defer func() { err = errors.WithStack(err) }()
 
+	if args.Serve {
+		return serve(args, deps)
+	}
+	cov, err := parseCovet(args, deps)
+	if err != nil {
+		return err
+	}
+	hasDiffCoverage := len(cov.DiffCoverageFiles()) > 0
+	failures, err := findCoverageFailures(cov, args)
+	if err != nil {
+		return err
+	}
+	baseline, err := compareHistory(args, deps)
+	if err != nil {
+		return err
+	}
+	var mutants []covet.MutantResult
+	if args.Mutate {
+		if deps.TestMutant == nil {
+			deps.TestMutant = goTestMutant(deps.FS)
+		}
+		mutants, err = testMutants(context.Background(), cov, args, deps)
+		if err != nil {
+			return err
+		}
+	}
+	summaryOptions := covet.ReportSummaryOptions{
+		Target:         args.TargetDiffCoverage,
+		Functions:      args.ShowFunctions,
+		SuggestTests:   args.SuggestTests,
+		GroupByPackage: args.GroupByPackage,
+		GroupByOwner:   args.GroupByOwner,
+		MinHits:        args.MinHits,
+		Mutants:        mutants,
+		Baseline:       baseline,
+	}
+	switch args.Format {
+	case summary.FormatJSON:
+		err = cov.ReportJSON(deps.Stdout, covet.ReportJSONOptions{
+			Target:   args.TargetDiffCoverage,
+			ShowHits: args.ShowHits,
+			MinHits:  args.MinHits,
+			Mutants:  mutants,
+		})
+	case summary.FormatSARIF:
+		err = cov.ReportSARIF(deps.Stdout, covet.ReportSARIFOptions{Mutants: mutants})
+	case summary.FormatHTML:
+		err = cov.ReportHTML(deps.Stdout, covet.ReportHTMLOptions{
+			Target:       args.TargetDiffCoverage,
+			ContextLines: args.ContextLines,
+			ShowHits:     args.ShowHits,
+			MinHits:      args.MinHits,
+		})
+	case summary.FormatColorTerminal, summary.FormatMarkdown:
+		err = reportText(cov, args, deps, summaryOptions)
+	}
+	if err != nil {
+		return err
+	}
+	if inGitHubActions() {
+		// annotate on stderr, so reports on stdout stay parseable
+		writeWorkflowCommands(deps.Stderr, cov, args, failures)
+	}
+	if err := recordHistory(cov, args, deps); err != nil {
+		return err
+	}
+	if !hasDiffCoverage {
+		// update review comments left by previous runs, since their lines may no longer be in the diff
+		return ensureAppReviewComments(context.Background(), cov, args, deps.Stderr)
+	}
+
+	if args.GitHubToken != "" || args.GitLabToken != "" || args.BitbucketToken != "" {
+		var markdownReport bytes.Buffer
+		err = cov.ReportSummaryMarkdown(&markdownReport, summaryOptions)
+		if err != nil {
+			return err
+		}
+		err = ensureAppComments(context.Background(), cov, args, deps.Stderr, markdownReport.String())
+		if err != nil {
+			return err
+		}
+	}
+	return coverageFailuresError(failures)
+}
+
+// parseCovet reads the diff and coverage files in 'args' and parses them
+func parseCovet(args Args, deps Deps) (*covet.Covet, error) {
 	var diffFile io.Reader
-	if args.DiffFile == "-" {
+	var gitDiff *covet.GitDiffOptions
+	switch args.DiffFile {
+	case "":
+		gitDiff = &covet.GitDiffOptions{
+			BaseRef:   args.GitBaseRef,
+			HeadRef:   args.GitHeadRef,
+			MergeBase: args.GitMergeBase,
+		}
+	case "-":
 		diffFile = deps.Stdin
-	} else {
+	default:
 		f, err := deps.FS.Open(args.DiffFile)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		defer f.Close()
 		diffFile = f
 	}
 
-	cov, err := covet.Parse(covet.Options{
-		FS:             deps.FS,
-		Diff:           diffFile,
-		DiffBaseDir:    args.DiffBaseDir,
-		GoCoveragePath: args.GoCoverageFile,
-	})
-	if err != nil {
-		return err
+	var coverageProfiles []covet.GoCoverageProfile
+	for _, coverageFile := range args.GoCoverageFiles {
+		arg := parseCoverageArg(coverageFile)
+		coverageProfiles = append(coverageProfiles, covet.GoCoverageProfile{
+			Path:    arg.Path,
+			BaseDir: arg.BaseDir,
+			Label:   arg.Label,
+		})
+	}
+	var otherCoverageProfiles []covet.CoverageProfile
+	for _, coverageFile := range args.CoverageFiles {
+		arg := parseCoverageArg(coverageFile)
+		baseDir := arg.BaseDir
+		if baseDir == "" {
+			baseDir = args.DiffBaseDir
+		}
+		otherCoverageProfiles = append(otherCoverageProfiles, covet.CoverageProfile{
+			Path:    arg.Path,
+			BaseDir: baseDir,
+			Label:   arg.Label,
+		})
 	}
+	return covet.Parse(covet.Options{
+		FS:                 deps.FS,
+		Diff:               diffFile,
+		GitDiff:            gitDiff,
+		DiffBaseDir:        args.DiffBaseDir,
+		GoCoverageProfiles: coverageProfiles,
+		CoverageProfiles:   otherCoverageProfiles,
+		CodeOwnersPath:     args.CodeOwnersFile,
+		Ignore: covet.IgnoreOptions{
+			Patterns:         args.IgnorePatterns,
+			IncludeGenerated: args.IncludeGenerated,
+		},
+	})
+}
+
+// reportText writes human-readable terminal or markdown reports
+func reportText(cov *covet.Covet, args Args, deps Deps, summaryOptions covet.ReportSummaryOptions) error {
 	if len(cov.DiffCoverageFiles()) == 0 {
 		fmt.Fprintln(deps.Stdout, "No coverage information intersects with diff.")
 		return nil
 	}
 
 	uncoveredFiles := cov.PriorityUncoveredFiles(args.TargetDiffCoverage)
-	if args.ShowCoverage {
+	if args.ShowCoverage && args.Format == summary.FormatColorTerminal {
 		for _, f := range uncoveredFiles {
 			fmt.Fprintln(deps.Stdout, "Coverage diff:", f.Name)
-			err := cov.ReportFileCoverage(deps.Stdout, f, covet.ReportFileCoverageOptions{})
+			err := cov.ReportFileCoverage(deps.Stdout, f, covet.ReportFileCoverageOptions{
+				ShowHits:  args.ShowHits,
+				OnlyLabel: args.OnlyLabel,
+			})
 			if err != nil {
 				return err
 			}
@@ -168,120 +517,27 @@ func runArgs(args Args, deps Deps) (err error) {
 
 	fmt.Fprintln(deps.Stdout)
 	totalCovered := cov.DiffCovered()
+	if args.Format == summary.FormatMarkdown {
+		fmt.Fprintln(deps.Stdout, "Total diff coverage:", summary.FormatPercent(totalCovered))
+		fmt.Fprintln(deps.Stdout)
+		return cov.ReportSummaryMarkdown(deps.Stdout, summaryOptions)
+	}
+
 	totalCoveredStatus := coverstatus.New(totalCovered)
 	fmt.Fprintln(deps.Stdout, "Total diff coverage:", totalCoveredStatus.Colorize(summary.FormatPercent(totalCovered)))
 	fmt.Fprintln(deps.Stdout)
-	summaryOptions := covet.ReportSummaryOptions{Target: args.TargetDiffCoverage}
-	err = cov.ReportSummaryColorTerminal(deps.Stdout, summaryOptions)
-	if err != nil {
-		return err
-	}
-
-	runWorkflow(coverageCommand(totalCovered, "", nil))
-	for _, f := range uncoveredFiles {
-		runWorkflow(coverageCommand(summary.FileCoverage(f), f.Name, findUncoveredLines(f)))
-	}
-
-	if args.GitHubToken != "" {
-		org, repo, number, err := parseIssueURL(args.GitHubIssue)
-		if err != nil {
-			return err
-		}
-		var markdownReport bytes.Buffer
-		err = cov.ReportSummaryMarkdown(&markdownReport, summaryOptions)
-		if err != nil {
-			return err
-		}
-		err = ensureAppGitHubComment(context.Background(), gitHubCommentOptions{
-			GitHubEndpoint: args.GitHubEndpoint,
-			GitHubToken:    args.GitHubToken,
-			RepoOwner:      org,
-			Repo:           repo,
-			IssueNumber:    number,
-			Body:           markdownReport.String(),
-		})
-		if err != nil {
-			fmt.Fprintln(deps.Stdout, "\nFailed to update GitHub comment, skipping. Error:", err)
-		}
-	}
-	return nil
+	return cov.ReportSummaryColorTerminal(deps.Stdout, summaryOptions)
 }
 
 func findUncoveredLines(f covet.File) []span.Span {
-	var uncoveredLines []span.Span
-	ok := true
-	var nextLineIndex int
-	for ok {
-		var uncovered span.Span
-		uncovered, ok, nextLineIndex = findFirstUncoveredLines(f.Lines, nextLineIndex)
-		if ok {
-			uncoveredLines = append(uncoveredLines, uncovered)
-		}
-	}
+	uncoveredLines := coverfile.UncoveredSpans(f)
 	sort.SliceStable(uncoveredLines, func(a, b int) bool {
 		return uncoveredLines[a].Len() > uncoveredLines[b].Len()
 	})
 	return uncoveredLines
 }
 
-func findFirstUncoveredLines(lines []covet.Line, startIndex int) (uncovered span.Span, ok bool, nextLineIndex int) {
-	// find start
-	nextLineIndex = startIndex
-	for _, l := range lines[nextLineIndex:] {
-		nextLineIndex++
-		if !l.Covered {
-			n := l.LineNumber
-			uncovered = span.Span{
-				Start: n,
-				End:   n + 1,
-			}
-			ok = true
-			break
-		}
-	}
-	// find next line number jump or covered line
-	for _, l := range lines[nextLineIndex:] {
-		if l.Covered || l.LineNumber != uncovered.End {
-			break
-		}
-		nextLineIndex++
-		uncovered.End++
-	}
-	return
-}
-
 const (
 	decimalBase = 10
 	maxIntBits  = 64
 )
-
-func parseIssueURL(s string) (org, repo string, number int, err error) {
-	if s == "" {
-		err = fmt.Errorf("-gh-issue is required")
-		return
-	}
-	issueURL, err := url.Parse(s)
-	if err != nil {
-		return
-	}
-	if issueURL.Scheme == "" {
-		issueURL, err = url.Parse("https://" + s)
-		if err != nil {
-			return
-		}
-	}
-	const minIssueURLPathComponents = 4
-	tokens := strings.SplitN(strings.TrimPrefix(issueURL.Path, "/"), "/", minIssueURLPathComponents+1)
-	if len(tokens) < minIssueURLPathComponents {
-		err = fmt.Errorf("malformed issue URL: expected 4+ path components, e.g. github.com/org/repo/pull/123")
-		return
-	}
-	n, err := strconv.ParseInt(tokens[3], decimalBase, maxIntBits)
-	if err != nil {
-		return
-	}
-	org = tokens[0]
-	repo = tokens[1]
-	number = int(n)
-	return
-}
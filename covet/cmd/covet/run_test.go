@@ -9,10 +9,12 @@ import (
 	"strings"
 	"testing"
 	"text/template"
+	"time"
 
 	"github.com/hack-pad/hackpadfs/mem"
 	"github.com/johnstarich/go/covet"
 	"github.com/johnstarich/go/covet/internal/span"
+	"github.com/johnstarich/go/covet/internal/summary"
 	"github.com/johnstarich/go/covet/internal/testhelpers"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -32,7 +34,19 @@ func TestRun(t *testing.T) {
 			description: "missing required args",
 			args:        nil,
 			expectOut:   "Usage of covet:",
-			expectErr:   "flag -cover-go is required",
+			expectErr:   "flag -cover-go or -cover is required",
+		},
+		{
+			description: "missing diff",
+			args:        []string{"-cover-go", "cover.out"},
+			expectOut:   "Usage of covet:",
+			expectErr:   "flag -diff-file or -git-base is required",
+		},
+		{
+			description: "diff file and git diff",
+			args:        []string{"-cover-go", "cover.out", "-diff-file", "my.patch", "-git-base", "main"},
+			expectOut:   "Usage of covet:",
+			expectErr:   "flags -diff-file and -git-base are mutually exclusive",
 		},
 		{
 			description: "help",
@@ -81,18 +95,19 @@ func TestRun(t *testing.T) {
 func TestRunArgs(t *testing.T) {
 	t.Parallel()
 	for _, tc := range []struct {
-		description string
-		args        Args
-		stdin       string
-		files       map[string]string
-		expectOut   string
-		expectErr   string
+		description  string
+		args         Args
+		stdin        string
+		files        map[string]string
+		expectOut    string
+		expectErrOut string
+		expectErr    string
 	}{
 		{
 			description: "print empty covet summary",
 			args: Args{
-				DiffFile:       "my.patch",
-				GoCoverageFile: "cover.out",
+				DiffFile:        "my.patch",
+				GoCoverageFiles: []string{"cover.out"},
 			},
 			files: map[string]string{
 				"my.patch": ``,
@@ -107,8 +122,8 @@ No coverage information intersects with diff.
 		{
 			description: "print covet summary",
 			args: Args{
-				DiffFile:       "my.patch",
-				GoCoverageFile: "cover.out",
+				DiffFile:        "my.patch",
+				GoCoverageFiles: []string{"cover.out"},
 			},
 			files: map[string]string{
 				"my.patch": `
@@ -150,13 +165,167 @@ Diff coverage is below target. Add tests for these files:
 ├───────┼──────────────┼───────────────────┤
 │  1/2  │  50.0% ██▌   │ cmd/covet/main.go │
 └───────┴──────────────┴───────────────────┘
+
+Total coverage of packages in the diff:
+┌────────┬────────┬───────────┐
+│ DIFF   │ TOTAL  │ PACKAGE   │
+├────────┼────────┼───────────┤
+│  50.0% │  50.0% │ cmd/covet │
+└────────┴────────┴───────────┘
+`,
+		},
+		{
+			description: "print covet summary with profile base directory",
+			args: Args{
+				DiffFile:        "my.patch",
+				GoCoverageFiles: []string{"out/cover.out=."},
+			},
+			files: map[string]string{
+				"my.patch": `
+diff --git a/run.go b/run.go
+index 0000000..1111111 100644
+--- a/cmd/covet/main.go
++++ b/cmd/covet/main.go
+@@ -1,4 +1,6 @@
+ package main
+
+ func main() {
++	println(1)
++	println(2)
+ }
+`,
+				"out/cover.out": `
+mode: atomic
+github.com/johnstarich/go/covet/cmd/covet/main.go:4.1,4.9 1 1
+github.com/johnstarich/go/covet/cmd/covet/main.go:5.1,5.9 1 0
+`,
+				"go.mod": `
+module github.com/johnstarich/go/covet
+`,
+				"cmd/covet/main.go": `
+package main
+
+func main() {
+	println(1)
+	println(2)
+}
+`,
+			},
+			expectOut: `
+Total diff coverage:  50.0%
+
+Diff coverage is below target. Add tests for these files:
+┌───────┬──────────────┬───────────────────┐
+│ LINES │ COVERAGE     │ FILE              │
+├───────┼──────────────┼───────────────────┤
+│  1/2  │  50.0% ██▌   │ cmd/covet/main.go │
+└───────┴──────────────┴───────────────────┘
+
+Total coverage of packages in the diff:
+┌────────┬────────┬───────────┐
+│ DIFF   │ TOTAL  │ PACKAGE   │
+├────────┼────────┼───────────┤
+│  50.0% │  50.0% │ cmd/covet │
+└────────┴────────┴───────────┘
+`,
+		},
+		{
+			description: "print covet summary from LCOV",
+			args: Args{
+				DiffFile:      "my.patch",
+				CoverageFiles: []string{"coverage/lcov.info"},
+			},
+			files: map[string]string{
+				"my.patch": `
+diff --git a/web/app.ts b/web/app.ts
+index 0000000..1111111 100644
+--- a/web/app.ts
++++ b/web/app.ts
+@@ -1,2 +1,4 @@
+ export function main() {
++	console.log(1)
++	console.log(2)
+ }
+`,
+				"coverage/lcov.info": `
+TN:
+SF:web/app.ts
+DA:2,1
+DA:3,0
+end_of_record
+`,
+				"web/app.ts": `
+export function main() {
+	console.log(1)
+	console.log(2)
+}
+`,
+			},
+			expectOut: `
+Total diff coverage:  50.0%
+
+Diff coverage is below target. Add tests for these files:
+┌───────┬──────────────┬────────────┐
+│ LINES │ COVERAGE     │ FILE       │
+├───────┼──────────────┼────────────┤
+│  1/2  │  50.0% ██▌   │ web/app.ts │
+└───────┴──────────────┴────────────┘
+
+Total coverage of packages in the diff:
+┌────────┬────────┬─────────┐
+│ DIFF   │ TOTAL  │ PACKAGE │
+├────────┼────────┼─────────┤
+│  50.0% │  50.0% │ web     │
+└────────┴────────┴─────────┘
+`,
+		},
+		{
+			description: "ignore files",
+			args: Args{
+				DiffFile:        "my.patch",
+				GoCoverageFiles: []string{"cover.out"},
+				IgnorePatterns:  []string{"cmd/**"},
+			},
+			files: map[string]string{
+				"my.patch": `
+diff --git a/run.go b/run.go
+index 0000000..1111111 100644
+--- a/cmd/covet/main.go
++++ b/cmd/covet/main.go
+@@ -1,4 +1,6 @@
+ package main
+
+ func main() {
++	println(1)
++	println(2)
+ }
+`,
+				"cover.out": `
+mode: atomic
+github.com/johnstarich/go/covet/cmd/covet/main.go:4.1,4.9 1 1
+github.com/johnstarich/go/covet/cmd/covet/main.go:5.1,5.9 1 0
+`,
+				"go.mod": `
+module github.com/johnstarich/go/covet
+`,
+				"cmd/covet/main.go": `
+package main
+
+func main() {
+	println(1)
+	println(2)
+}
+`,
+			},
+			expectOut: `
+No coverage information intersects with diff.
 `,
 		},
 		{
 			description: "print covet summary subdirectory",
 			args: Args{
-				DiffFile:       "my.patch",
-				GoCoverageFile: "mypkg/cover.out",
+				DiffFile:        "my.patch",
+				GoCoverageFiles: []string{"mypkg/cover.out"},
 			},
 			files: map[string]string{
 				"my.patch": `
@@ -198,13 +367,20 @@ Diff coverage is below target. Add tests for these files:
 ├───────┼──────────────┼─────────┤
 │  1/2  │  50.0% ██▌   │ main.go │
 └───────┴──────────────┴─────────┘
+
+Total coverage of packages in the diff:
+┌────────┬────────┬─────────┐
+│ DIFF   │ TOTAL  │ PACKAGE │
+├────────┼────────┼─────────┤
+│  50.0% │  50.0% │ mypkg   │
+└────────┴────────┴─────────┘
 `,
 		},
 		{
 			description: "print covet summary parent directory",
 			args: Args{
-				DiffFile:       "my.patch",
-				GoCoverageFile: "cover.out",
+				DiffFile:        "my.patch",
+				GoCoverageFiles: []string{"cover.out"},
 			},
 			files: map[string]string{
 				"my.patch": `
@@ -246,14 +422,21 @@ Diff coverage is below target. Add tests for these files:
 ├───────┼──────────────┼───────────────┤
 │  1/2  │  50.0% ██▌   │ mypkg/main.go │
 └───────┴──────────────┴───────────────┘
+
+Total coverage of packages in the diff:
+┌────────┬────────┬─────────┐
+│ DIFF   │ TOTAL  │ PACKAGE │
+├────────┼────────┼─────────┤
+│  50.0% │  50.0% │ mypkg   │
+└────────┴────────┴─────────┘
 `,
 		},
 		{
 			description: "print covet",
 			args: Args{
-				DiffFile:       "my.patch",
-				GoCoverageFile: "cover.out",
-				ShowCoverage:   true,
+				DiffFile:        "my.patch",
+				GoCoverageFiles: []string{"cover.out"},
+				ShowCoverage:    true,
 			},
 			files: map[string]string{
 				"my.patch": `
@@ -303,16 +486,269 @@ Diff coverage is below target. Add tests for these files:
 ├───────┼──────────────┼───────────────────┤
 │  1/2  │  50.0% ██▌   │ cmd/covet/main.go │
 └───────┴──────────────┴───────────────────┘
+
+Total coverage of packages in the diff:
+┌────────┬────────┬───────────┐
+│ DIFF   │ TOTAL  │ PACKAGE   │
+├────────┼────────┼───────────┤
+│  50.0% │  50.0% │ cmd/covet │
+└────────┴────────┴───────────┘
+`,
+		},
+		{
+			description: "print markdown report",
+			args: Args{
+				DiffFile:        "my.patch",
+				GoCoverageFiles: []string{"cover.out"},
+				Format:          summary.FormatMarkdown,
+			},
+			files: map[string]string{
+				"my.patch": `
+diff --git a/run.go b/run.go
+index 0000000..1111111 100644
+--- a/cmd/covet/main.go
++++ b/cmd/covet/main.go
+@@ -1,4 +1,6 @@
+ package main
+
+ func main() {
++	println(1)
++	println(2)
+ }
+`,
+				"cover.out": `
+mode: atomic
+github.com/johnstarich/go/covet/cmd/covet/main.go:4.1,4.9 1 1
+github.com/johnstarich/go/covet/cmd/covet/main.go:5.1,5.9 1 0
+`,
+				"go.mod": `
+module github.com/johnstarich/go/covet
+`,
+				"cmd/covet/main.go": `
+package main
+
+func main() {
+	println(1)
+	println(2)
+}
+`,
+			},
+			expectOut: `
+Total diff coverage:  50.0%
+
+Diff coverage is below target. Add tests for these files:
+|  | Lines | Coverage | File |
+| --- |:---:| --- | --- |
+| 🟠 | ` + "``1/2``" + ` | ` + "``\u00a050.0%\u00a0██▌\u00a0\u00a0``" + ` | cmd/covet/main.go |
+
+Total coverage of packages in the diff:
+|  | Diff | Total | Package |
+| --- |:---:|:---:| --- |
+| 🟠 | ` + "``\u00a050.0%``" + ` | ` + "``\u00a050.0%``" + ` | cmd/covet |
+`,
+		},
+		{
+			description: "print json report",
+			args: Args{
+				DiffFile:        "my.patch",
+				GoCoverageFiles: []string{"cover.out"},
+				Format:          summary.FormatJSON,
+			},
+			files: map[string]string{
+				"my.patch": `
+diff --git a/run.go b/run.go
+index 0000000..1111111 100644
+--- a/cmd/covet/main.go
++++ b/cmd/covet/main.go
+@@ -1,4 +1,6 @@
+ package main
+
+ func main() {
++	println(1)
++	println(2)
+ }
+`,
+				"cover.out": `
+mode: atomic
+github.com/johnstarich/go/covet/cmd/covet/main.go:4.1,4.9 1 1
+github.com/johnstarich/go/covet/cmd/covet/main.go:5.1,5.9 1 0
+`,
+				"go.mod": `
+module github.com/johnstarich/go/covet
+`,
+				"cmd/covet/main.go": `
+package main
+
+func main() {
+	println(1)
+	println(2)
+}
+`,
+			},
+			expectOut: `
+{
+  "diffCoverage": 0.5,
+  "target": 90,
+  "covered": 1,
+  "uncovered": 1,
+  "files": [
+    {
+      "name": "cmd/covet/main.go",
+      "diffCoverage": 0.5,
+      "covered": 1,
+      "uncovered": 1,
+      "uncoveredLines": [
+        {
+          "startLine": 5,
+          "endLine": 5
+        }
+      ]
+    }
+  ]
+}
+`,
+		},
+		{
+			description: "fail below minimum diff coverage",
+			args: Args{
+				DiffFile:        "my.patch",
+				GoCoverageFiles: []string{"cover.out"},
+				MinDiffCoverage: 60,
+				MinFileDiffCoverage: fileMinimums{
+					{Pattern: "cmd/*/*.go", Minimum: 70},
+				},
+			},
+			files: map[string]string{
+				"my.patch": `
+diff --git a/run.go b/run.go
+index 0000000..1111111 100644
+--- a/cmd/covet/main.go
++++ b/cmd/covet/main.go
+@@ -1,4 +1,6 @@
+ package main
+
+ func main() {
++	println(1)
++	println(2)
+ }
+`,
+				"cover.out": `
+mode: atomic
+github.com/johnstarich/go/covet/cmd/covet/main.go:4.1,4.9 1 1
+github.com/johnstarich/go/covet/cmd/covet/main.go:5.1,5.9 1 0
+`,
+				"go.mod": `
+module github.com/johnstarich/go/covet
+`,
+				"cmd/covet/main.go": `
+package main
+
+func main() {
+	println(1)
+	println(2)
+}
+`,
+			},
+			expectOut: `
+Total diff coverage:  50.0%
+
+Diff coverage is below target. Add tests for these files:
+┌───────┬──────────────┬───────────────────┐
+│ LINES │ COVERAGE     │ FILE              │
+├───────┼──────────────┼───────────────────┤
+│  1/2  │  50.0% ██▌   │ cmd/covet/main.go │
+└───────┴──────────────┴───────────────────┘
+
+Total coverage of packages in the diff:
+┌────────┬────────┬───────────┐
+│ DIFF   │ TOTAL  │ PACKAGE   │
+├────────┼────────┼───────────┤
+│  50.0% │  50.0% │ cmd/covet │
+└────────┴────────┴───────────┘
+`,
+			expectErr: `diff coverage is below the required minimum:
+- total diff coverage 50.0% is below the required minimum 60%
+- cmd/covet/main.go diff coverage 50.0% is below the required minimum 70%`,
+		},
+		{
+			description: "fail below minimum owner diff coverage",
+			args: Args{
+				DiffFile:        "my.patch",
+				GoCoverageFiles: []string{"cover.out"},
+				GroupByOwner:    true,
+				MinOwnerDiffCoverage: fileMinimums{
+					{Pattern: "@org/cli", Minimum: 70},
+				},
+			},
+			files: map[string]string{
+				"my.patch": `
+diff --git a/run.go b/run.go
+index 0000000..1111111 100644
+--- a/cmd/covet/main.go
++++ b/cmd/covet/main.go
+@@ -1,4 +1,6 @@
+ package main
+
+ func main() {
++	println(1)
++	println(2)
+ }
+`,
+				"cover.out": `
+mode: atomic
+github.com/johnstarich/go/covet/cmd/covet/main.go:4.1,4.9 1 1
+github.com/johnstarich/go/covet/cmd/covet/main.go:5.1,5.9 1 0
+`,
+				"go.mod": `
+module github.com/johnstarich/go/covet
+`,
+				".github/CODEOWNERS": `
+/cmd/ @org/cli
+`,
+				"cmd/covet/main.go": `
+package main
+
+func main() {
+	println(1)
+	println(2)
+}
+`,
+			},
+			expectOut: `
+Total diff coverage:  50.0%
+
+Diff coverage is below target. Add tests for these files:
+┌───────┬──────────────┬───────────────────┐
+│ LINES │ COVERAGE     │ FILE              │
+├───────┼──────────────┼───────────────────┤
+│  1/2  │  50.0% ██▌   │ cmd/covet/main.go │
+└───────┴──────────────┴───────────────────┘
+
+Total coverage of packages in the diff:
+┌────────┬────────┬───────────┐
+│ DIFF   │ TOTAL  │ PACKAGE   │
+├────────┼────────┼───────────┤
+│  50.0% │  50.0% │ cmd/covet │
+└────────┴────────┴───────────┘
+
+Diff coverage by owner:
+┌───────┬──────────────┬──────────┐
+│ LINES │ COVERAGE     │ OWNER    │
+├───────┼──────────────┼──────────┤
+│  1/2  │  50.0% ██▌   │ @org/cli │
+└───────┴──────────────┴──────────┘
 `,
+			expectErr: `diff coverage is below the required minimum:
+- owner @org/cli diff coverage 50.0% is below the required minimum 70%`,
 		},
 		{
 			description: "post to github comment - bad status does not fail command",
 			args: Args{
-				DiffFile:       "my.patch",
-				GoCoverageFile: "cover.out",
-				GitHubEndpoint: "replace-me",
-				GitHubToken:    "some-gh-token",
-				GitHubIssue:    "github.com/org/repo/pull/123",
+				DiffFile:        "my.patch",
+				GoCoverageFiles: []string{"cover.out"},
+				GitHubEndpoint:  "replace-me",
+				GitHubToken:     "some-gh-token",
+				GitHubIssue:     "github.com/org/repo/pull/123",
 			},
 			files: map[string]string{
 				"my.patch": `
@@ -355,16 +791,47 @@ Diff coverage is below target. Add tests for these files:
 │  1/2  │  50.0% ██▌   │ cmd/covet/main.go │
 └───────┴──────────────┴───────────────────┘
 
+Total coverage of packages in the diff:
+┌────────┬────────┬───────────┐
+│ DIFF   │ TOTAL  │ PACKAGE   │
+├────────┼────────┼───────────┤
+│  50.0% │  50.0% │ cmd/covet │
+└────────┴────────┴───────────┘
+`,
+			expectErrOut: `
 Failed to update GitHub comment, skipping. Error: GET {{.ServerURL}}/api/v3/repos/org/repo/issues/123/comments?sort=created: 500  []
+`,
+		},
+		{
+			description: "update github review comments without diff coverage",
+			args: Args{
+				DiffFile:        "my.patch",
+				GoCoverageFiles: []string{"cover.out"},
+				GitHubEndpoint:  "replace-me",
+				GitHubToken:     "some-gh-token",
+				GitHubIssue:     "github.com/org/repo/pull/123",
+				GitHubReview:    true,
+			},
+			files: map[string]string{
+				"my.patch": ``,
+				"cover.out": `
+mode: atomic
+`,
+			},
+			expectOut: `
+No coverage information intersects with diff.
+`,
+			expectErrOut: `
+Failed to update GitHub review comments, skipping. Error: GET {{.ServerURL}}/api/v3/repos/org/repo/pulls/123/comments?per_page=100: 500  []
 `,
 		},
 		{
 			description: "post to github comment - bad issue URL",
 			args: Args{
-				DiffFile:       "my.patch",
-				GoCoverageFile: "cover.out",
-				GitHubToken:    "some-gh-token",
-				GitHubIssue:    "foo",
+				DiffFile:        "my.patch",
+				GoCoverageFiles: []string{"cover.out"},
+				GitHubToken:     "some-gh-token",
+				GitHubIssue:     "foo",
 			},
 			files: map[string]string{
 				"my.patch": `
@@ -406,17 +873,83 @@ Diff coverage is below target. Add tests for these files:
 ├───────┼──────────────┼───────────────────┤
 │  1/2  │  50.0% ██▌   │ cmd/covet/main.go │
 └───────┴──────────────┴───────────────────┘
+
+Total coverage of packages in the diff:
+┌────────┬────────┬───────────┐
+│ DIFF   │ TOTAL  │ PACKAGE   │
+├────────┼────────┼───────────┤
+│  50.0% │  50.0% │ cmd/covet │
+└────────┴────────┴───────────┘
 `,
 			expectErr: "malformed issue URL: expected 4+ path components, e.g. github.com/org/repo/pull/123",
 		},
+		{
+			description: "post to gitlab comment - bad merge request URL",
+			args: Args{
+				DiffFile:           "my.patch",
+				GoCoverageFiles:    []string{"cover.out"},
+				GitLabToken:        "some-gitlab-token",
+				GitLabMergeRequest: "github.com/org/repo/pull/123",
+			},
+			files: map[string]string{
+				"my.patch": `
+diff --git a/run.go b/run.go
+index 0000000..1111111 100644
+--- a/cmd/covet/main.go
++++ b/cmd/covet/main.go
+@@ -1,4 +1,6 @@
+ package main
+
+ func main() {
++	println(1)
++	println(2)
+ }
+`,
+				"cover.out": `
+mode: atomic
+github.com/johnstarich/go/covet/cmd/covet/main.go:4.1,4.9 1 1
+github.com/johnstarich/go/covet/cmd/covet/main.go:5.1,5.9 1 0
+`,
+				"go.mod": `
+module github.com/johnstarich/go/covet
+`,
+				"cmd/covet/main.go": `
+package main
+
+func main() {
+	println(1)
+	println(2)
+}
+`,
+			},
+			expectOut: `
+Total diff coverage:  50.0%
+
+Diff coverage is below target. Add tests for these files:
+┌───────┬──────────────┬───────────────────┐
+│ LINES │ COVERAGE     │ FILE              │
+├───────┼──────────────┼───────────────────┤
+│  1/2  │  50.0% ██▌   │ cmd/covet/main.go │
+└───────┴──────────────┴───────────────────┘
+
+Total coverage of packages in the diff:
+┌────────┬────────┬───────────┐
+│ DIFF   │ TOTAL  │ PACKAGE   │
+├────────┼────────┼───────────┤
+│  50.0% │  50.0% │ cmd/covet │
+└────────┴────────┴───────────┘
+`,
+			expectErr: "-gitlab-mr must be a GitLab merge request URL, e.g. gitlab.com/group/project/-/merge_requests/123",
+		},
 	} {
 		t.Run(tc.description, func(t *testing.T) {
 			t.Parallel()
 			fs := testhelpers.FSWithFiles(t, tc.files)
-			var output bytes.Buffer
+			var output, errOutput bytes.Buffer
 			deps := Deps{
 				Stdin:  strings.NewReader(tc.stdin),
 				Stdout: &output,
+				Stderr: &errOutput,
 				FS:     fs,
 			}
 			args := tc.args
@@ -433,13 +966,16 @@ Diff coverage is below target. Add tests for these files:
 				t.Cleanup(server.Close)
 			}
 
-			var expectOut bytes.Buffer
-			require.NoError(t, template.Must(template.New("").Parse(tc.expectOut)).Execute(&expectOut, map[string]interface{}{
+			var expectOut, expectErrOut bytes.Buffer
+			templateData := map[string]interface{}{
 				"ServerURL": args.GitHubEndpoint,
-			}))
+			}
+			require.NoError(t, template.Must(template.New("").Parse(tc.expectOut)).Execute(&expectOut, templateData))
+			require.NoError(t, template.Must(template.New("").Parse(tc.expectErrOut)).Execute(&expectErrOut, templateData))
 
 			err := runArgs(args, deps)
 			assert.Equal(t, strings.TrimSpace(expectOut.String()), strings.TrimSpace(output.String()))
+			assert.Equal(t, strings.TrimSpace(expectErrOut.String()), strings.TrimSpace(errOutput.String()))
 			if tc.expectErr != "" {
 				assert.EqualError(t, err, tc.expectErr)
 				return
@@ -449,55 +985,6 @@ Diff coverage is below target. Add tests for these files:
 	}
 }
 
-func TestParseIssueURL(t *testing.T) {
-	t.Parallel()
-	for _, tc := range []struct {
-		url          string
-		expectOrg    string
-		expectRepo   string
-		expectNumber int
-		expectErr    string
-	}{
-		{
-			url:       "",
-			expectErr: "-gh-issue is required",
-		},
-		{
-			url:       "example.com",
-			expectErr: "malformed issue URL: expected 4+ path components, e.g. github.com/org/repo/pull/123",
-		},
-		{
-			url:          "github.com/myorg/myrepo/pull/123",
-			expectOrg:    "myorg",
-			expectRepo:   "myrepo",
-			expectNumber: 123,
-		},
-		{
-			url:          "github.com/myorg/myrepo/pull/123/extra",
-			expectOrg:    "myorg",
-			expectRepo:   "myrepo",
-			expectNumber: 123,
-		},
-		{
-			url:       "github.com/myorg/myrepo/pull/not-a-number",
-			expectErr: `strconv.ParseInt: parsing "not-a-number": invalid syntax`,
-		},
-	} {
-		t.Run(tc.url, func(t *testing.T) {
-			t.Parallel()
-			org, repo, number, err := parseIssueURL(tc.url)
-			assert.Equal(t, tc.expectOrg, org)
-			assert.Equal(t, tc.expectRepo, repo)
-			assert.Equal(t, tc.expectNumber, number)
-			if tc.expectErr != "" {
-				assert.EqualError(t, err, tc.expectErr)
-			} else {
-				assert.NoError(t, err)
-			}
-		})
-	}
-}
-
 func TestParseArgs(t *testing.T) {
 	t.Parallel()
 
@@ -505,7 +992,7 @@ func TestParseArgs(t *testing.T) {
 		t.Parallel()
 		var buf bytes.Buffer
 		_, err := parseArgs([]string{}, &buf)
-		assert.EqualError(t, err, "flag -cover-go is required")
+		assert.EqualError(t, err, "flag -cover-go or -cover is required")
 	})
 
 	t.Run("invalid flags", func(t *testing.T) {
@@ -517,6 +1004,128 @@ func TestParseArgs(t *testing.T) {
 		assert.EqualError(t, err, `invalid value "not-a-number" for flag -target-diff-coverage: parse error`)
 	})
 
+	t.Run("multiple coverage profiles", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		args, err := parseArgs([]string{
+			"-cover-go", "a.out,b.out",
+			"-cover-go", "c.out",
+			"-diff-file", "-",
+		}, &buf)
+		assert.NoError(t, err)
+
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		_, workingDir := testhelpers.FromOSToFS(t, wd)
+		assert.Equal(t, []string{
+			path.Join(workingDir, "a.out"),
+			path.Join(workingDir, "b.out"),
+			path.Join(workingDir, "c.out"),
+		}, args.GoCoverageFiles)
+	})
+
+	t.Run("labelled coverage profiles", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		args, err := parseArgs([]string{
+			"-cover-go", "unit=a.out,b.out",
+			"-cover-go", "integration=out/d.out=mod,./out/e.out=mod",
+			"-cover", "integration=c.xml",
+			"-diff-file", "-",
+		}, &buf)
+		assert.NoError(t, err)
+
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		_, workingDir := testhelpers.FromOSToFS(t, wd)
+		assert.Equal(t, []string{
+			"unit=" + path.Join(workingDir, "a.out"),
+			path.Join(workingDir, "b.out"),
+			"integration=" + path.Join(workingDir, "out/d.out") + "=" + path.Join(workingDir, "mod"),
+			path.Join(workingDir, "out/e.out") + "=" + path.Join(workingDir, "mod"),
+		}, args.GoCoverageFiles)
+		assert.Equal(t, []string{
+			"integration=" + path.Join(workingDir, "c.xml"),
+		}, args.CoverageFiles)
+	})
+
+	t.Run("existing file with label-like name", func(t *testing.T) {
+		t.Parallel()
+		f, err := os.CreateTemp(".", "unit=*.out")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		t.Cleanup(func() { _ = os.Remove(f.Name()) })
+
+		var buf bytes.Buffer
+		args, err := parseArgs([]string{
+			"-cover-go", f.Name(),
+			"-diff-file", "-",
+		}, &buf)
+		assert.NoError(t, err)
+
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		_, workingDir := testhelpers.FromOSToFS(t, wd)
+		assert.Equal(t, []string{
+			path.Join(workingDir, f.Name()),
+		}, args.GoCoverageFiles)
+	})
+
+	t.Run("group by", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		args, err := parseArgs([]string{
+			"-cover-go", "cover.out",
+			"-diff-file", "-",
+			"-group-by", "package,owner",
+		}, &buf)
+		assert.NoError(t, err)
+		assert.True(t, args.GroupByPackage)
+		assert.True(t, args.GroupByOwner)
+
+		_, err = parseArgs([]string{
+			"-cover-go", "cover.out",
+			"-diff-file", "-",
+			"-group-by", "team",
+		}, &buf)
+		assert.EqualError(t, err, `invalid value "team" for flag -group-by: unsupported group "team", must be one of: package, owner`)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		_, err := parseArgs([]string{
+			"-format", "xml",
+		}, &buf)
+		assert.EqualError(t, err, `invalid value "xml" for flag -format: unsupported format "xml", must be one of: terminal, markdown, json, sarif, html`)
+	})
+
+	t.Run("set format", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		args, err := parseArgs([]string{
+			"-cover-go", "cover.out",
+			"-diff-file", "-",
+			"-format", "sarif",
+		}, &buf)
+		assert.NoError(t, err)
+		assert.Equal(t, summary.FormatSARIF, args.Format)
+	})
+
+	t.Run("set html format", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		args, err := parseArgs([]string{
+			"-cover-go", "cover.out",
+			"-diff-file", "-",
+			"-format", "html",
+			"-context-lines", "5",
+		}, &buf)
+		assert.NoError(t, err)
+		assert.Equal(t, summary.FormatHTML, args.Format)
+		assert.Equal(t, uint(5), args.ContextLines)
+	})
+
 	t.Run("set fs paths", func(t *testing.T) {
 		t.Parallel()
 		const (
@@ -537,13 +1146,52 @@ func TestParseArgs(t *testing.T) {
 		assert.Equal(t, Args{
 			DiffFile:           path.Join(workingDir, someDiffPath),
 			DiffBaseDir:        workingDir,
-			GoCoverageFile:     path.Join(workingDir, someCoverPath),
+			GoCoverageFiles:    []string{path.Join(workingDir, someCoverPath)},
 			TargetDiffCoverage: 90,
+			ContextLines:       2,
+			MutateTimeout:      5 * time.Minute,
 			GitHubEndpoint:     "https://api.github.com",
 		}, args)
 	})
 }
 
+func TestSplitCoverageLabel(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		value       string
+		expectLabel string
+		expectPath  string
+	}{
+		{value: "cover.out", expectPath: "cover.out"},
+		{value: "unit=cover.out", expectLabel: "unit", expectPath: "cover.out"},
+		{value: "e2e_tests=out/cover.out", expectLabel: "e2e_tests", expectPath: "out/cover.out"},
+		{value: "out/a=b.out", expectPath: "out/a=b.out"},
+		{value: "=cover.out", expectPath: "=cover.out"},
+	} {
+		label, p := splitCoverageLabel(tc.value)
+		assert.Equal(t, tc.expectLabel, label, tc.value)
+		assert.Equal(t, tc.expectPath, p, tc.value)
+	}
+}
+
+func TestParseCoverageArg(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		value  string
+		expect coverageArg
+	}{
+		{value: "cover.out", expect: coverageArg{Path: "cover.out"}},
+		{value: "unit=cover.out", expect: coverageArg{Label: "unit", Path: "cover.out"}},
+		{value: "a/cover.out=a", expect: coverageArg{Path: "a/cover.out", BaseDir: "a"}},
+		{value: "unit=a/cover.out=a", expect: coverageArg{Label: "unit", Path: "a/cover.out", BaseDir: "a"}},
+		{value: "./cover=a", expect: coverageArg{Path: "./cover", BaseDir: "a"}},
+	} {
+		arg := parseCoverageArg(tc.value)
+		assert.Equal(t, tc.expect, arg, tc.value)
+		assert.Equal(t, tc.value, arg.String())
+	}
+}
+
 func TestSetErr(t *testing.T) {
 	t.Parallel()
 	someError := errors.New("some error")
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hack-pad/hackpadfs"
+	"github.com/johnstarich/go/covet"
+	"github.com/johnstarich/go/covet/internal/coverstatus"
+	"github.com/johnstarich/go/covet/internal/gitrepo"
+	"github.com/johnstarich/go/covet/internal/summary"
+)
+
+//go:embed serve.html
+var serveHTMLTemplate string
+
+// serve runs an interactive coverage viewer on args.ServeAddr until the server fails
+func serve(args Args, deps Deps) error {
+	srv, err := newServer(args, deps)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", args.ServeAddr)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(deps.Stdout, "Serving coverage viewer at http://%s\n", listener.Addr())
+	const readHeaderTimeout = 10 * time.Second
+	httpServer := &http.Server{
+		Handler:           srv,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	return httpServer.Serve(listener)
+}
+
+// server is an interactive coverage viewer for a diff and coverage files.
+// Parses coverage again whenever the diff or coverage files change on disk.
+type server struct {
+	args Args
+	deps Deps
+	tmpl *template.Template
+	mux  *http.ServeMux
+	// stdinDiff is the diff read from stdin, since stdin can only be read once
+	stdinDiff []byte
+
+	mu sync.Mutex
+	// inputs identifies the diff and coverage files' state from the last parse
+	inputs string
+	// version increments on every parse. Pages reload when it changes.
+	version  uint
+	cov      *covet.Covet
+	parseErr error
+}
+
+func newServer(args Args, deps Deps) (*server, error) {
+	tmpl, err := template.New("serve").Parse(serveHTMLTemplate)
+	if err != nil {
+		return nil, err
+	}
+	s := &server{
+		args: args,
+		deps: deps,
+		tmpl: tmpl,
+		mux:  http.NewServeMux(),
+	}
+	if args.DiffFile == "-" {
+		s.stdinDiff, err = io.ReadAll(deps.Stdin)
+		if err != nil {
+			return nil, err
+		}
+	}
+	s.mux.HandleFunc("/", s.serveIndex)
+	s.mux.HandleFunc("/file", s.serveFile)
+	s.mux.HandleFunc("/version", s.serveVersion)
+	return s, nil
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// refresh parses the diff and coverage files again if they changed since the last parse
+func (s *server) refresh() (*covet.Covet, uint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	inputs := s.inputsState()
+	if s.version == 0 || inputs != s.inputs {
+		deps := s.deps
+		if s.args.DiffFile == "-" {
+			deps.Stdin = bytes.NewReader(s.stdinDiff)
+		}
+		s.cov, s.parseErr = parseCovet(s.args, deps)
+		s.inputs = s.inputsState() // include files in the new diff
+		s.version++
+	}
+	return s.cov, s.version, s.parseErr
+}
+
+// inputsState returns the size and modified time of each diff and coverage file.
+// Diffs from stdin are not tracked, since they have no file on disk.
+// Diffs from git track the HEAD commit, staged files, and the working tree's files in the last parsed diff.
+// Edits to other tracked files are picked up once they are staged or committed.
+func (s *server) inputsState() string {
+	var paths []string
+	if s.args.DiffFile != "" && s.args.DiffFile != "-" {
+		paths = append(paths, s.args.DiffFile)
+	}
+	for _, coverageFile := range append(append([]string(nil), s.args.GoCoverageFiles...), s.args.CoverageFiles...) {
+		_, coverageFile = splitCoverageLabel(coverageFile)
+		paths = append(paths, coverageFile)
+	}
+	var state strings.Builder
+	if s.args.GitBaseRef != "" {
+		gitState, err := gitrepo.State(s.deps.FS, s.args.DiffBaseDir)
+		if err != nil {
+			gitState = err.Error() + "\n"
+		}
+		state.WriteString(gitState)
+		if s.args.GitHeadRef == "" && s.cov != nil {
+			for _, f := range s.cov.DiffCoverageFiles() {
+				paths = append(paths, path.Join(s.args.DiffBaseDir, s.cov.DiffFilePath(f)))
+			}
+		}
+	}
+	for _, p := range paths {
+		info, err := hackpadfs.Stat(s.deps.FS, p)
+		if err != nil {
+			fmt.Fprintf(&state, "%s: %v\n", p, err)
+			continue
+		}
+		fmt.Fprintf(&state, "%s: %d %d\n", p, info.Size(), info.ModTime().UnixNano())
+	}
+	return state.String()
+}
+
+type servePage struct {
+	Version      uint
+	Error        string
+	DiffCoverage string
+	Status       string
+	Target       uint
+	Covered      uint
+	Total        uint
+	Files        []serveFileLink
+	File         *serveFile
+}
+
+type serveFileLink struct {
+	Name     string
+	Coverage string
+	Status   string
+	Covered  uint
+	Total    uint
+}
+
+type serveFile struct {
+	serveFileLink
+	Full   bool
+	Chunks []covet.HTMLChunk
+}
+
+func (s *server) serveIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	cov, version, err := s.refresh()
+	page := servePage{Version: version, Target: s.args.TargetDiffCoverage}
+	if err != nil {
+		s.render(w, http.StatusInternalServerError, page, err)
+		return
+	}
+	files := cov.DiffCoverageFiles()
+	totalCoverage := 1.0
+	if len(files) > 0 {
+		totalCoverage = cov.DiffCovered()
+	}
+	page.DiffCoverage = summary.FormatPercent(totalCoverage)
+	page.Status = coverstatus.New(totalCoverage).Name()
+	for _, f := range files {
+		link := newServeFileLink(cov.DiffFilePath(f), f)
+		page.Covered += link.Covered
+		page.Total += link.Total
+		page.Files = append(page.Files, link)
+	}
+	sort.Slice(page.Files, func(a, b int) bool {
+		return page.Files[a].Name < page.Files[b].Name
+	})
+	s.render(w, http.StatusOK, page, nil)
+}
+
+func newServeFileLink(name string, f covet.File) serveFileLink {
+	percent := summary.FileCoverage(f)
+	return serveFileLink{
+		Name:     name,
+		Coverage: summary.FormatPercent(percent),
+		Status:   coverstatus.New(percent).Name(),
+		Covered:  f.Covered,
+		Total:    f.Covered + f.Uncovered,
+	}
+}
+
+func (s *server) serveFile(w http.ResponseWriter, r *http.Request) {
+	cov, version, err := s.refresh()
+	page := servePage{Version: version, Target: s.args.TargetDiffCoverage}
+	if err != nil {
+		s.render(w, http.StatusInternalServerError, page, err)
+		return
+	}
+	name := r.URL.Query().Get("name")
+	full := r.URL.Query().Get("view") == "full"
+	for _, f := range cov.DiffCoverageFiles() {
+		if cov.DiffFilePath(f) != name {
+			continue
+		}
+		file, err := s.newServeFile(cov, name, f, full)
+		if err != nil {
+			s.render(w, http.StatusInternalServerError, page, err)
+			return
+		}
+		page.File = &file
+		s.render(w, http.StatusOK, page, nil)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// newServeFile returns the source of 'f' with coverage marked.
+// If 'full' is true, shows every line's coverage. Otherwise shows only lines in the diff, with some context.
+func (s *server) newServeFile(cov *covet.Covet, name string, f covet.File, full bool) (serveFile, error) {
+	if !full {
+		chunks, err := cov.HTMLChunks(f, s.args.ContextLines, s.args.MinHits)
+		return serveFile{serveFileLink: newServeFileLink(name, f), Chunks: chunks}, err
+	}
+	chunk, err := cov.HTMLFullChunk(f)
+	return serveFile{
+		serveFileLink: newServeFileLink(name, cov.FileCoverage(f)),
+		Full:          true,
+		Chunks:        []covet.HTMLChunk{chunk},
+	}, err
+}
+
+func (s *server) serveVersion(w http.ResponseWriter, _ *http.Request) {
+	_, version, _ := s.refresh() // parse errors are shown after the page reloads
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, version)
+}
+
+func (s *server) render(w http.ResponseWriter, status int, page servePage, err error) {
+	if err != nil {
+		page.Error = err.Error()
+	}
+	var buf bytes.Buffer
+	if err := s.tmpl.Execute(&buf, page); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = buf.WriteTo(w)
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/hack-pad/hackpadfs"
+	"github.com/johnstarich/go/covet/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveRequest(t *testing.T, srv http.Handler, target string) (int, string) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	srv.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	return recorder.Code, recorder.Body.String()
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+	fs := testhelpers.FSWithFiles(t, map[string]string{
+		"go.mod": `module github.com/org/repo`,
+		"main.go": `package main
+
+func main() {
+	println(1)
+	println(2)
+	println(3)
+}
+`,
+		"cover.out": `
+mode: set
+github.com/org/repo/main.go:4.1,4.11 1 1
+github.com/org/repo/main.go:5.1,5.11 1 0
+github.com/org/repo/main.go:6.1,6.11 1 0
+`,
+	})
+	diff := `
+diff --git a/main.go b/main.go
+index 0000000..1111111 100644
+--- a/main.go
++++ b/main.go
+@@ -3,3 +3,4 @@
+ func main() {
+ 	println(1)
+ 	println(2)
++	println(3)
+`
+	srv, err := newServer(Args{
+		DiffFile:           "-",
+		DiffBaseDir:        ".",
+		GoCoverageFiles:    []string{"unit=cover.out"},
+		TargetDiffCoverage: 90,
+		ContextLines:       2,
+	}, Deps{
+		Stdin: strings.NewReader(strings.TrimSpace(diff)),
+		FS:    fs,
+	})
+	require.NoError(t, err)
+
+	status, body := serveRequest(t, srv, "/")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `<h1>Diff coverage <span class="status-error">  0.0%</span></h1>`)
+	assert.Contains(t, body, `<td><a href="file?name=main.go">main.go</a></td>`)
+	assert.Contains(t, body, `<body data-version="1">`)
+
+	status, body = serveRequest(t, srv, "/file?name=main.go")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `<tr class="separator"><td colspan="3">Lines 4 to 7</td></tr>`)
+	assert.Contains(t, body, `<tr class="uncovered" id="L6"><td class="line-number"><a href="#L6">6</a></td><td class="op">-</td>`)
+	assert.NotContains(t, body, `id="L1"`)
+
+	status, body = serveRequest(t, srv, "/file?name=main.go&view=full")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `<tr class="context" id="L1">`)
+	assert.Contains(t, body, `<tr class="covered" id="L4"><td class="line-number"><a href="#L4">4</a></td><td class="op"> </td>`)
+	assert.Contains(t, body, `<tr class="uncovered" id="L6"><td class="line-number"><a href="#L6">6</a></td><td class="op">&#43;</td>`)
+	assert.Contains(t, body, `1/3  33.3%`)
+
+	status, _ = serveRequest(t, srv, "/file?name=../secret.go")
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = serveRequest(t, srv, "/not-found")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, body = serveRequest(t, srv, "/version")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "1", body)
+
+	require.NoError(t, hackpadfs.WriteFullFile(fs, "cover.out", []byte(`mode: set
+github.com/org/repo/main.go:4.1,4.11 1 1
+github.com/org/repo/main.go:5.1,5.11 1 1
+github.com/org/repo/main.go:6.1,6.11 1 1
+`), 0o600))
+	_, body = serveRequest(t, srv, "/version")
+	assert.Equal(t, "2", body)
+	_, body = serveRequest(t, srv, "/")
+	assert.Contains(t, body, `<h1>Diff coverage <span class="status-excellent">100.0%</span></h1>`)
+
+	require.NoError(t, hackpadfs.WriteFullFile(fs, "cover.out", []byte(`not a coverage file`), 0o600))
+	status, body = serveRequest(t, srv, "/")
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Contains(t, body, `<p class="error">`)
+	assert.Contains(t, body, `<body data-version="3">`)
+}
+
+func TestServerGitRefresh(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	writeFile := func(name, contents string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600))
+	}
+	commit := func(message string) string {
+		t.Helper()
+		hash, err := worktree.Commit(message, &git.CommitOptions{
+			All:    true,
+			Author: &object.Signature{Name: "Tester", Email: "tester@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+		return hash.String()
+	}
+	const mainGo = "package main\n\nfunc main() {\n\tprintln(1)\n}\n"
+	writeFile("go.mod", "module github.com/org/repo\n")
+	writeFile("main.go", mainGo)
+	_, err = worktree.Add(".")
+	require.NoError(t, err)
+	base := commit("initial commit")
+	writeFile("main.go", mainGo+"\nfunc other() {}\n")
+	commit("add other")
+	writeFile("cover.out", "mode: set\ngithub.com/org/repo/main.go:7.1,7.16 1 1\n")
+
+	fs, fsDir := testhelpers.FromOSToFS(t, dir)
+	srv, err := newServer(Args{
+		GitBaseRef:      base,
+		DiffBaseDir:     fsDir,
+		GoCoverageFiles: []string{path.Join(fsDir, "cover.out")},
+	}, Deps{FS: fs})
+	require.NoError(t, err)
+
+	_, body := serveRequest(t, srv, "/version")
+	assert.Equal(t, "1", body)
+	_, body = serveRequest(t, srv, "/version")
+	assert.Equal(t, "1", body)
+
+	later := time.Now().Add(time.Minute)
+	writeFile("main.go", mainGo+"\nfunc other() {}\n\nfunc uncommitted() {}\n")
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "main.go"), later, later))
+	_, body = serveRequest(t, srv, "/version")
+	assert.Equal(t, "2", body, "edits to files in the diff should refresh")
+
+	commit("commit uncommitted")
+	_, body = serveRequest(t, srv, "/version")
+	assert.Equal(t, "3", body, "new commits should refresh")
+}
+
+func TestParseArgsServe(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	args, err := parseArgs([]string{
+		"serve",
+		"-addr", "localhost:1234",
+		"-cover-go", "cover.out",
+		"-diff-file", "-",
+	}, &buf)
+	require.NoError(t, err)
+	assert.True(t, args.Serve)
+	assert.Equal(t, "localhost:1234", args.ServeAddr)
+
+	_, err = parseArgs([]string{
+		"-addr", "localhost:1234",
+		"-cover-go", "cover.out",
+		"-diff-file", "-",
+	}, &buf)
+	assert.EqualError(t, err, "flag provided but not defined: -addr")
+}
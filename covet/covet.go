@@ -2,18 +2,26 @@
 package covet
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/fs"
 	"math"
 	"path"
+	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/bluekeyes/go-gitdiff/gitdiff"
 	"github.com/fatih/color"
 	"github.com/hack-pad/hackpadfs"
+	"github.com/johnstarich/go/covet/internal/covdata"
+	"github.com/johnstarich/go/covet/internal/coverprofile"
 	"github.com/johnstarich/go/covet/internal/fspath"
+	"github.com/johnstarich/go/covet/internal/gitrepo"
+	"github.com/johnstarich/go/covet/internal/ignore"
+	"github.com/johnstarich/go/covet/internal/minmax"
 	"github.com/johnstarich/go/covet/internal/packages"
 	"github.com/johnstarich/go/covet/internal/span"
 	"github.com/pkg/errors"
@@ -23,9 +31,18 @@ import (
 // Covet generates reports for a diff and coverage combination
 type Covet struct {
 	options Options
+	// coverageBaseDir is the FS path to the common base directory of all coverage profiles.
+	// Coverage file names are relative to this directory.
+	coverageBaseDir string
 	addedLines,
 	coveredLines,
 	uncoveredLines map[string][]span.Span
+	// labelCoveredLines contains covered lines for each coverage profile label, then file. Unlabelled profiles use an empty label.
+	labelCoveredLines map[string]map[string][]span.Span
+	// lineLabelHits contains the sorted labels which covered each line, by file then line number. Empty unless a coverage profile is labelled.
+	lineLabelHits map[string]map[uint][]string
+	// hitCounts contains the number of times each line ran, by file then line number. Only 'count' and 'atomic' mode Go profiles have hit counts.
+	hitCounts map[string]map[uint]uint
 }
 
 // Options contains parse options
@@ -35,28 +52,143 @@ type Options struct {
 	FS fs.FS
 	// Diff is a reader with patch or diff formatted contents
 	Diff io.Reader
+	// GitDiff computes the diff from the git repository at DiffBaseDir instead of reading Diff
+	GitDiff *GitDiffOptions
 	// DiffBaseDir is the FS path to the repo's root directory
 	DiffBaseDir string
 	// GoCoverage is the FS path to a Go coverage file
 	GoCoveragePath string
 	// GoCoverageBaseDir is the FS path to the coverage file's module. Defaults to the coverage file's directory.
 	GoCoverageBaseDir string
+	// GoCoverageProfiles are additional Go coverage files to merge with GoCoveragePath.
+	// A line is covered if any profile covered it. Profiles may use different modes, like 'set' and 'count'.
+	GoCoverageProfiles []GoCoverageProfile
+	// CoverageProfiles are coverage files in any supported format, like LCOV or Cobertura XML from other languages' test runners.
+	// Merged with the Go coverage profiles.
+	CoverageProfiles []CoverageProfile
+	// Ignore excludes files and lines from the diff before computing coverage
+	Ignore IgnoreOptions
+	// CodeOwnersPath is the FS path to a CODEOWNERS file, used to group files by owner.
+	// Defaults to searching DiffBaseDir for .github/CODEOWNERS, CODEOWNERS, then docs/CODEOWNERS.
+	CodeOwnersPath string
 }
 
-// Parse reads and parses both a diff file and Go coverage file, then returns a Covet instance to render reports
+// CoverageFormat is a coverage file format
+type CoverageFormat = coverprofile.Format
+
+// Supported coverage file formats
+const (
+	// CoverageFormatAuto detects the format from the coverage file's contents
+	CoverageFormatAuto = coverprofile.FormatAuto
+	// CoverageFormatGo is a Go coverage profile, written by 'go test -coverprofile'
+	CoverageFormatGo = coverprofile.FormatGo
+	// CoverageFormatLCOV is an LCOV tracefile, written by tools like Istanbul, c8, and genhtml
+	CoverageFormatLCOV = coverprofile.FormatLCOV
+	// CoverageFormatCobertura is a Cobertura XML report, written by tools like coverage.py and Jest
+	CoverageFormatCobertura = coverprofile.FormatCobertura
+)
+
+// CoverageProfile is a coverage file in any supported format
+type CoverageProfile struct {
+	// Path is the FS path to a coverage file.
+	// May also be a GOCOVERDIR directory containing binary Go coverage data, written by binaries built with 'go build -cover'.
+	Path string
+	// BaseDir is the FS path that relative file paths in the coverage file start from, like the project's root directory.
+	// For Go coverage files, this is the module's directory. Defaults to the coverage file's directory.
+	BaseDir string
+	// Format is the coverage file's format. Defaults to detecting it from the file's contents.
+	Format CoverageFormat
+	// Label names the kind of tests which produced this coverage, like "unit" or "integration".
+	// Labelled profiles break down diff coverage by label. See Covet.DiffCoverageByLabel.
+	Label string
+}
+
+// IgnoreOptions contains rules to exclude files and lines from diff coverage.
+// Lines marked with a '// covet:ignore' comment are always excluded. See Marker for details.
+type IgnoreOptions struct {
+	// Patterns exclude files matching any of these slash-separated path patterns, relative to DiffBaseDir.
+	// Uses path.Match syntax, plus '**' to match zero or more directories, like "vendor/**".
+	// Patterns without a slash match file names in any directory, like "*.pb.go".
+	Patterns []string
+	// IncludeGenerated includes Go files with the standard generated code header, like "// Code generated by protoc-gen-go. DO NOT EDIT."
+	// Generated files are excluded by default.
+	IncludeGenerated bool
+}
+
+// Marker is the comment text which excludes Go source lines from diff coverage.
+// A '// covet:ignore' comment at the end of a line excludes that line, or on its own line excludes the next line.
+// If the excluded line begins a multi-line statement or declaration, like an 'if' block or a function, the whole statement is excluded.
+const Marker = ignore.Marker
+
+// GitDiffOptions contains options to compute a diff from a git repository
+type GitDiffOptions struct {
+	// BaseRef is the git revision to compare against, like "origin/main". Required.
+	BaseRef string
+	// HeadRef is the git revision with new changes, like "HEAD". Defaults to the working tree, including uncommitted changes to tracked files.
+	HeadRef string
+	// MergeBase compares HeadRef against the merge base of BaseRef and HeadRef, like 'git diff BaseRef...HeadRef'
+	MergeBase bool
+}
+
+// GoCoverageProfile is a Go coverage file and its module's base directory
+type GoCoverageProfile struct {
+	// Path is the FS path to a Go coverage file.
+	// May also be a GOCOVERDIR directory containing binary coverage data, written by binaries built with 'go build -cover'.
+	Path string
+	// BaseDir is the FS path to the coverage file's module. Defaults to the coverage file's directory.
+	BaseDir string
+	// Label names the kind of tests which produced this coverage, like "unit" or "integration". See CoverageProfile.Label.
+	Label string
+}
+
+// Parse reads and parses both a diff file and Go coverage files, then returns a Covet instance to render reports
 func Parse(options Options) (covet *Covet, err error) {
 	defer func() { err = errors.Wrap(err, "covet") }()
 	if !hackpadfs.ValidPath(options.DiffBaseDir) {
 		return nil, errors.Errorf("invalid diff base directory FS path: %s", options.DiffBaseDir)
 	}
-	if !hackpadfs.ValidPath(options.GoCoveragePath) {
-		return nil, errors.Errorf("invalid coverage FS path: %s", options.GoCoveragePath)
+	var profiles []CoverageProfile
+	if options.GoCoveragePath != "" || len(options.GoCoverageProfiles)+len(options.CoverageProfiles) == 0 {
+		profiles = append(profiles, CoverageProfile{
+			Path:    options.GoCoveragePath,
+			BaseDir: options.GoCoverageBaseDir,
+			Format:  CoverageFormatGo,
+		})
 	}
-	if options.GoCoverageBaseDir == "" {
-		options.GoCoverageBaseDir = path.Dir(options.GoCoveragePath)
+	for _, profile := range options.GoCoverageProfiles {
+		profiles = append(profiles, CoverageProfile{
+			Path:    profile.Path,
+			BaseDir: profile.BaseDir,
+			Format:  CoverageFormatGo,
+			Label:   profile.Label,
+		})
 	}
-	if !hackpadfs.ValidPath(options.GoCoverageBaseDir) {
-		return nil, errors.Errorf("invalid coverage base directory FS path: %s", options.GoCoverageBaseDir)
+	profiles = append(profiles, options.CoverageProfiles...)
+	var coverageBaseDir string
+	for i := range profiles {
+		profile := &profiles[i]
+		if !hackpadfs.ValidPath(profile.Path) {
+			return nil, errors.Errorf("invalid coverage FS path: %s", profile.Path)
+		}
+		if profile.BaseDir == "" {
+			profile.BaseDir = path.Dir(profile.Path)
+		}
+		if !hackpadfs.ValidPath(profile.BaseDir) {
+			return nil, errors.Errorf("invalid coverage base directory FS path: %s", profile.BaseDir)
+		}
+		if i == 0 {
+			coverageBaseDir = profile.BaseDir
+		} else {
+			coverageBaseDir = fspath.CommonBase(coverageBaseDir, profile.BaseDir)
+		}
+	}
+	if options.CodeOwnersPath != "" && !hackpadfs.ValidPath(options.CodeOwnersPath) {
+		return nil, errors.Errorf("invalid CODEOWNERS FS path: %s", options.CodeOwnersPath)
+	}
+	for _, pattern := range options.Ignore.Patterns {
+		if err := ignore.ValidatePattern(pattern); err != nil {
+			return nil, errors.Wrapf(err, "invalid ignore pattern %q", pattern)
+		}
 	}
 	if options.FS == nil {
 		options.FS, err = fspath.WorkingDirectoryFS()
@@ -64,30 +196,24 @@ func Parse(options Options) (covet *Covet, err error) {
 			return nil, err
 		}
 	}
-	if options.Diff == nil {
-		return nil, errors.New("diff reader must not be nil")
-	}
-
-	diffFiles, _, err := gitdiff.Parse(options.Diff)
+	diffReader, err := readDiff(options)
 	if err != nil {
 		return nil, err
 	}
 
-	coverageFile, err := options.FS.Open(options.GoCoveragePath)
-	if err != nil {
-		return nil, err
-	}
-	defer coverageFile.Close()
-	coverageFiles, err := cover.ParseProfilesFromReader(coverageFile)
+	diffFiles, _, err := gitdiff.Parse(diffReader)
 	if err != nil {
 		return nil, err
 	}
 
 	covet = &Covet{
-		options:        options,
-		addedLines:     make(map[string][]span.Span),
-		coveredLines:   make(map[string][]span.Span),
-		uncoveredLines: make(map[string][]span.Span),
+		options:           options,
+		coverageBaseDir:   coverageBaseDir,
+		addedLines:        make(map[string][]span.Span),
+		coveredLines:      make(map[string][]span.Span),
+		uncoveredLines:    make(map[string][]span.Span),
+		labelCoveredLines: make(map[string]map[string][]span.Span),
+		hitCounts:         make(map[string]map[uint]uint),
 	}
 	_, err = covet.coverageToDiffRel()
 	if err != nil {
@@ -95,12 +221,76 @@ func Parse(options Options) (covet *Covet, err error) {
 	}
 
 	covet.addDiff(diffFiles)
-	if err := covet.addCoverage(options.FS, options.GoCoverageBaseDir, coverageFiles); err != nil {
+	if err := covet.removeIgnoredLines(); err != nil {
 		return nil, err
 	}
+	for _, profile := range profiles {
+		if err := covet.addCoverageProfile(profile); err != nil {
+			return nil, err
+		}
+	}
+	covet.mergeCoverage()
 	return covet, nil
 }
 
+func readDiff(options Options) (io.Reader, error) {
+	switch {
+	case options.Diff != nil && options.GitDiff != nil:
+		return nil, errors.New("diff reader and git diff options are mutually exclusive")
+	case options.Diff != nil:
+		return options.Diff, nil
+	case options.GitDiff != nil:
+		diff, err := gitrepo.Diff(context.Background(), options.FS, options.DiffBaseDir, gitrepo.Options(*options.GitDiff))
+		return strings.NewReader(diff), err
+	default:
+		return nil, errors.New("diff reader must not be nil")
+	}
+}
+
+func (c *Covet) addCoverageProfile(profile CoverageProfile) error {
+	coverageFile, err := c.options.FS.Open(profile.Path)
+	if err != nil {
+		return err
+	}
+	defer coverageFile.Close()
+	info, err := coverageFile.Stat()
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		goProfiles, err := covdata.ReadDir(c.options.FS, profile.Path)
+		if err != nil {
+			return err
+		}
+		return c.addGoCoverage(profile.BaseDir, profile.Label, goProfiles)
+	}
+
+	contents, err := io.ReadAll(coverageFile)
+	if err != nil {
+		return err
+	}
+	format := profile.Format
+	if format == CoverageFormatAuto {
+		format = coverprofile.DetectFormat(contents)
+	}
+	switch format {
+	case CoverageFormatAuto:
+		return errors.Errorf("unrecognized coverage file format: %s", profile.Path)
+	case CoverageFormatGo:
+		goProfiles, err := cover.ParseProfilesFromReader(bytes.NewReader(contents))
+		if err != nil {
+			return err
+		}
+		return c.addGoCoverage(profile.BaseDir, profile.Label, goProfiles)
+	default:
+		parsedProfile, err := coverprofile.Parse(format, bytes.NewReader(contents))
+		if err != nil {
+			return errors.Wrap(err, profile.Path)
+		}
+		return c.addProfileCoverage(profile.BaseDir, profile.Label, parsedProfile)
+	}
+}
+
 func (c *Covet) addDiff(diffFiles []*gitdiff.File) {
 	for _, file := range diffFiles {
 		spans := findDiffAddSpans(file.TextFragments)
@@ -108,6 +298,47 @@ func (c *Covet) addDiff(diffFiles []*gitdiff.File) {
 	}
 }
 
+// removeIgnoredLines drops added lines matching any ignore rule
+func (c *Covet) removeIgnoredLines() error {
+	for file, added := range c.addedLines {
+		ignoredFile, ignoredLines, err := c.findIgnored(file)
+		if err != nil {
+			return err
+		}
+		if ignoredFile {
+			delete(c.addedLines, file)
+			continue
+		}
+		if len(ignoredLines) > 0 {
+			c.addedLines[file] = span.Subtract(span.Union(added), ignoredLines)
+		}
+	}
+	return nil
+}
+
+// findIgnored returns true if the diff file 'name' is ignored entirely, otherwise returns the file's ignored lines
+func (c *Covet) findIgnored(name string) (bool, []span.Span, error) {
+	for _, pattern := range c.options.Ignore.Patterns {
+		if matched, _ := ignore.MatchPattern(pattern, name); matched { // pattern is validated in Parse()
+			return true, nil, nil
+		}
+	}
+	if !strings.HasSuffix(name, ".go") {
+		return false, nil, nil
+	}
+	src, err := hackpadfs.ReadFile(c.options.FS, path.Join(c.options.DiffBaseDir, name))
+	if errors.Is(err, hackpadfs.ErrNotExist) {
+		return false, nil, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	if !c.options.Ignore.IncludeGenerated && ignore.IsGenerated(src) {
+		return true, nil, nil
+	}
+	return false, ignore.Lines(src), nil
+}
+
 type signedInteger interface {
 	~int | ~int64
 }
@@ -144,31 +375,149 @@ func findDiffAddSpans(fragments []*gitdiff.TextFragment) []span.Span {
 	return spans
 }
 
-func (c *Covet) addCoverage(fs hackpadfs.FS, baseDir string, coverageFiles []*cover.Profile) error {
+func (c *Covet) addGoCoverage(baseDir, label string, coverageFiles []*cover.Profile) error {
+	baseDirRel, err := fspath.Rel(c.coverageBaseDir, baseDir)
+	if err != nil {
+		return err
+	}
 	for _, file := range coverageFiles {
+		fileHits := make(map[string]map[uint]uint)
 		for _, block := range file.Blocks {
-			coverageFile, err := packages.FilePath(fs, baseDir, file.FileName, packages.Options{})
+			coverageFile, err := packages.FilePath(c.options.FS, baseDir, file.FileName, packages.Options{})
 			if err != nil {
 				return err
 			}
-			if block.Count > 0 {
-				c.coveredLines[coverageFile] = append(c.coveredLines[coverageFile], span.Span{
-					Start: uintFromBoundedSignedInt(block.StartLine),
-					End:   uintFromBoundedSignedInt(block.EndLine + 1),
-				})
-			} else {
-				c.uncoveredLines[coverageFile] = append(c.uncoveredLines[coverageFile], span.Span{
-					Start: uintFromBoundedSignedInt(block.StartLine),
-					End:   uintFromBoundedSignedInt(block.EndLine + 1),
-				})
+			coverageFile = path.Join(baseDirRel, coverageFile)
+			blockSpan := span.Span{
+				Start: uintFromBoundedSignedInt(block.StartLine),
+				End:   uintFromBoundedSignedInt(block.EndLine + 1),
+			}
+			c.addCoverageSpan(label, coverageFile, blockSpan, block.Count > 0)
+			if file.Mode != "set" && block.Count > 0 {
+				addBlockHits(fileHits, coverageFile, blockSpan, uintFromBoundedSignedInt(block.Count))
 			}
 		}
+		c.addHitCounts(fileHits)
 	}
 	return nil
 }
 
+// addBlockHits records 'count' hits for each line in 's'. Lines shared by several blocks in the same profile keep the highest count.
+func addBlockHits(hits map[string]map[uint]uint, coverageFile string, s span.Span, count uint) {
+	if hits[coverageFile] == nil {
+		hits[coverageFile] = make(map[uint]uint)
+	}
+	for line := s.Start; line < s.End; line++ {
+		hits[coverageFile][line] = minmax.Max(hits[coverageFile][line], count)
+	}
+}
+
+// addHitCounts sums one profile's hit counts into the hit counts of all profiles
+func (c *Covet) addHitCounts(hits map[string]map[uint]uint) {
+	for coverageFile, lineHits := range hits {
+		if c.hitCounts[coverageFile] == nil {
+			c.hitCounts[coverageFile] = make(map[uint]uint)
+		}
+		for line, count := range lineHits {
+			c.hitCounts[coverageFile][line] += count
+		}
+	}
+}
+
+// addProfileCoverage adds coverage from a non-Go coverage file, like LCOV or Cobertura
+func (c *Covet) addProfileCoverage(baseDir, label string, profile coverprofile.Profile) error {
+	for _, file := range profile.Files {
+		filePath, err := c.findProfileFile(baseDir, profile.Sources, file.Name)
+		if err != nil {
+			return err
+		}
+		coverageFile, err := fspath.Rel(c.coverageBaseDir, filePath)
+		if err != nil {
+			return err
+		}
+		for _, s := range file.Covered {
+			c.addCoverageSpan(label, coverageFile, s, true)
+		}
+		for _, s := range file.Uncovered {
+			c.addCoverageSpan(label, coverageFile, s, false)
+		}
+	}
+	return nil
+}
+
+func (c *Covet) addCoverageSpan(label, coverageFile string, s span.Span, covered bool) {
+	if covered {
+		c.coveredLines[coverageFile] = append(c.coveredLines[coverageFile], s)
+		if c.labelCoveredLines[label] == nil {
+			c.labelCoveredLines[label] = make(map[string][]span.Span)
+		}
+		c.labelCoveredLines[label][coverageFile] = append(c.labelCoveredLines[label][coverageFile], s)
+	} else {
+		c.uncoveredLines[coverageFile] = append(c.uncoveredLines[coverageFile], s)
+	}
+}
+
+// osPathFS is an FS which converts OS paths to FS paths, like hackpadfs's os.FS
+type osPathFS interface {
+	FromOSPath(osPath string) (string, error)
+}
+
+// findProfileFile returns the FS path for a file 'name' from a coverage file.
+// Relative names are tried inside each of 'sources' first, then 'baseDir'. Returns the first path that exists, or the 'baseDir' path if none exist.
+func (c *Covet) findProfileFile(baseDir string, sources []string, name string) (string, error) {
+	var candidates []string
+	for _, source := range sources {
+		candidates = append(candidates, path.Join(filepath.ToSlash(source), filepath.ToSlash(name)))
+	}
+	candidates = append(candidates, name)
+
+	var fallback string
+	for i, candidate := range candidates {
+		fsPath, err := c.toFSPath(baseDir, candidate)
+		if err != nil {
+			return "", err
+		}
+		if i == len(candidates)-1 {
+			fallback = fsPath
+		}
+		if _, err := hackpadfs.Stat(c.options.FS, fsPath); err == nil {
+			return fsPath, nil
+		}
+	}
+	return fallback, nil
+}
+
+// toFSPath converts a relative or absolute OS path 'p' from a coverage file to an FS path
+func (c *Covet) toFSPath(baseDir, p string) (string, error) {
+	p = filepath.ToSlash(p)
+	if !path.IsAbs(p) && filepath.VolumeName(p) == "" {
+		return path.Join(baseDir, p), nil
+	}
+	if fs, ok := c.options.FS.(osPathFS); ok {
+		return fs.FromOSPath(filepath.FromSlash(p))
+	}
+	return strings.TrimPrefix(path.Clean(p), "/"), nil
+}
+
+// mergeCoverage combines overlapping coverage blocks from all profiles.
+// Lines covered by any block are removed from the uncovered lines.
+func (c *Covet) mergeCoverage() {
+	for file, covered := range c.coveredLines {
+		c.coveredLines[file] = span.Union(covered)
+	}
+	for file, uncovered := range c.uncoveredLines {
+		c.uncoveredLines[file] = span.Subtract(span.Union(uncovered), c.coveredLines[file])
+	}
+	for _, labelCovered := range c.labelCoveredLines {
+		for file, covered := range labelCovered {
+			labelCovered[file] = span.Union(covered)
+		}
+	}
+	c.indexLabelHits()
+}
+
 func (c *Covet) coverageToDiffRel() (string, error) {
-	return fspath.Rel(c.options.DiffBaseDir, c.options.GoCoverageBaseDir)
+	return fspath.Rel(c.options.DiffBaseDir, c.coverageBaseDir)
 }
 
 func (c *Covet) coveredAndUncovered() (fileNames map[string]bool, coveredDiff, uncoveredDiff map[string][]span.Span) {
@@ -231,6 +580,8 @@ func (c *Covet) DiffCoverageFiles() []File {
 				coveredFile.Lines = append(coveredFile.Lines, Line{
 					Covered:    true,
 					LineNumber: i,
+					Labels:     c.lineLabels(file, i),
+					Hits:       c.hitCounts[file][i],
 				})
 			}
 			coveredFile.Covered += s.Len()
@@ -252,13 +603,54 @@ func (c *Covet) DiffCoverageFiles() []File {
 	return coveredFiles
 }
 
-// ReportFileCoverageOptions contains options to format a file coverage report.
-// Reserved for future use.
-type ReportFileCoverageOptions struct{}
+// FileCoverage returns coverage for every line of 'f', including lines outside the diff.
+// 'f' is typically one of DiffCoverageFiles.
+func (c *Covet) FileCoverage(f File) File {
+	file := File{Name: f.Name}
+	for _, s := range c.coveredLines[f.Name] {
+		for i := s.Start; i < s.End; i++ {
+			file.Lines = append(file.Lines, Line{
+				Covered:    true,
+				LineNumber: i,
+				Labels:     c.lineLabels(f.Name, i),
+				Hits:       c.hitCounts[f.Name][i],
+			})
+		}
+		file.Covered += s.Len()
+	}
+	for _, s := range c.uncoveredLines[f.Name] {
+		for i := s.Start; i < s.End; i++ {
+			file.Lines = append(file.Lines, Line{
+				Covered:    false,
+				LineNumber: i,
+			})
+		}
+		file.Uncovered += s.Len()
+	}
+	sort.Slice(file.Lines, func(a, b int) bool {
+		return file.Lines[a].LineNumber < file.Lines[b].LineNumber
+	})
+	return file
+}
+
+// DiffFilePath returns the path to 'f' relative to the diff's base directory, like a repository-relative path
+func (c *Covet) DiffFilePath(f File) string {
+	covToDiffRel, _ := c.coverageToDiffRel() // ignore error since it's checked during setup
+	return path.Join(covToDiffRel, f.Name)
+}
+
+// ReportFileCoverageOptions contains options to format a file coverage report
+type ReportFileCoverageOptions struct {
+	// ShowHits ends each covered line with its hit count, like "(12 hits)". Requires 'count' or 'atomic' mode Go coverage profiles.
+	ShowHits bool
+	// OnlyLabel marks lines covered only by profiles with this label with a '~' prefix instead of '+', like lines only covered by "integration" tests
+	OnlyLabel string
+}
 
 // ReportFileCoverage writes a diff-like plain text report with color to 'w'.
-func (c *Covet) ReportFileCoverage(w io.Writer, f File, _ ReportFileCoverageOptions) error {
-	name := path.Join(c.options.GoCoverageBaseDir, f.Name)
+// If any coverage profiles are labelled, covered lines end with the labels which covered them, like "[unit, integration]".
+func (c *Covet) ReportFileCoverage(w io.Writer, f File, options ReportFileCoverageOptions) error {
+	name := path.Join(c.coverageBaseDir, f.Name)
 	r, err := c.options.FS.Open(name)
 	if err != nil {
 		return err
@@ -269,10 +661,32 @@ func (c *Covet) ReportFileCoverage(w io.Writer, f File, _ ReportFileCoverageOpti
 	if err != nil {
 		return err
 	}
+	lineLabels := make(map[uint][]string)
+	lineHits := make(map[uint]uint)
+	for _, line := range f.Lines {
+		if len(line.Labels) > 0 {
+			lineLabels[line.LineNumber] = line.Labels
+		}
+		if line.Hits > 0 {
+			lineHits[line.LineNumber] = line.Hits
+		}
+	}
 	for _, chunk := range chunks {
 		fmt.Fprintln(w, "Coverage:", chunk.FirstLine, "to", chunk.LastLine)
-		for _, line := range chunk.Lines {
+		for i, line := range chunk.Lines {
+			lineNumber := chunk.FirstLine + uint(i)
+			if labels := lineLabels[lineNumber]; len(labels) > 0 {
+				if options.OnlyLabel != "" && c.coveredOnlyBy(f.Name, lineNumber, options.OnlyLabel) {
+					line = "~" + strings.TrimPrefix(line, "+")
+				}
+				line += "  [" + strings.Join(labels, ", ") + "]"
+			}
+			if hits := lineHits[lineNumber]; options.ShowHits && hits > 0 {
+				line += "  (" + formatHits(hits) + ")"
+			}
 			switch {
+			case strings.HasPrefix(line, "~"):
+				line = color.YellowString(line)
 			case strings.HasPrefix(line, "+"):
 				line = color.GreenString(line)
 			case strings.HasPrefix(line, "-"):
@@ -6,10 +6,14 @@ import (
 	"io"
 	goos "os"
 	"path"
+	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 	"testing/iotest"
 
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/hack-pad/hackpadfs"
 	"github.com/johnstarich/go/covet/internal/testhelpers"
 	"github.com/stretchr/testify/assert"
@@ -127,6 +131,371 @@ foo
 	}
 }
 
+func TestParseMultipleProfiles(t *testing.T) {
+	t.Parallel()
+	fs := testhelpers.FSWithFiles(t, map[string]string{
+		"a/go.mod": `module example.com/a`,
+		"a/a.go": `
+package a
+`,
+		"a/cover.out": `
+mode: set
+example.com/a/a.go:1.1,1.7 1 1
+example.com/a/a.go:2.1,2.7 1 0
+example.com/a/a.go:3.1,3.7 1 0
+`,
+		"a/integration.out": `
+mode: count
+example.com/a/a.go:2.1,2.7 1 5
+example.com/a/a.go:3.1,3.7 1 0
+`,
+		"b/go.mod": `module example.com/b`,
+		"b/b.go": `
+package b
+`,
+		"b/cover.out": `
+mode: atomic
+example.com/b/b.go:1.1,1.7 1 0
+`,
+	})
+	diff := `
+diff --git a/a/a.go b/a/a.go
+index 0000000..1111111 100644
+--- a/a/a.go
++++ b/a/a.go
+@@ -0,0 +1,3 @@
++added 1
++added 2
++added 3
+diff --git a/b/b.go b/b/b.go
+index 0000000..1111111 100644
+--- a/b/b.go
++++ b/b/b.go
+@@ -0,0 +1,1 @@
++added 1
+`
+	covet, err := Parse(Options{
+		FS:          fs,
+		Diff:        strings.NewReader(strings.TrimSpace(diff)),
+		DiffBaseDir: ".",
+		GoCoverageProfiles: []GoCoverageProfile{
+			{Path: "a/cover.out"},
+			{Path: "a/integration.out", BaseDir: "a"},
+			{Path: "b/cover.out"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, covet.DiffCovered())
+	files := covet.DiffCoverageFiles()
+	sort.Slice(files, func(a, b int) bool {
+		return files[a].Name < files[b].Name
+	})
+	assert.Equal(t, []File{
+		{
+			Name:      "a/a.go",
+			Covered:   2,
+			Uncovered: 1,
+			Lines: []Line{
+				{Covered: true, LineNumber: 1},
+				{Covered: true, LineNumber: 2, Hits: 5},
+				{Covered: false, LineNumber: 3},
+			},
+		},
+		{
+			Name:      "b/b.go",
+			Covered:   0,
+			Uncovered: 1,
+			Lines: []Line{
+				{Covered: false, LineNumber: 1},
+			},
+		},
+	}, files)
+}
+
+func TestParseIgnore(t *testing.T) {
+	t.Parallel()
+	fs := testhelpers.FSWithFiles(t, map[string]string{
+		"go.mod": `module example.com/a`,
+		"main.go": `package main
+
+func main() {
+	if err := run(); err != nil { // covet:ignore unreachable
+		panic(err)
+	}
+	println(1)
+}
+`,
+		"api/api.pb.go": `// Code generated by protoc-gen-go. DO NOT EDIT.
+
+package api
+`,
+		"vendor/example.com/b/b.go": `package b
+`,
+		"cover.out": `
+mode: set
+example.com/a/main.go:4.1,4.10 1 1
+example.com/a/main.go:5.1,5.10 1 0
+example.com/a/main.go:6.1,6.10 1 0
+example.com/a/main.go:7.1,7.10 1 0
+example.com/a/api/api.pb.go:1.1,3.10 1 0
+example.com/a/vendor/example.com/b/b.go:1.1,1.10 1 0
+`,
+	})
+	diff := `
+diff --git a/main.go b/main.go
+index 0000000..1111111 100644
+--- a/main.go
++++ b/main.go
+@@ -0,0 +1,8 @@
++package main
++
++func main() {
++	if err := run(); err != nil { // covet:ignore unreachable
++		panic(err)
++	}
++	println(1)
++}
+diff --git a/api/api.pb.go b/api/api.pb.go
+index 0000000..1111111 100644
+--- a/api/api.pb.go
++++ b/api/api.pb.go
+@@ -0,0 +1,3 @@
++// Code generated by protoc-gen-go. DO NOT EDIT.
++
++package api
+diff --git a/vendor/example.com/b/b.go b/vendor/example.com/b/b.go
+index 0000000..1111111 100644
+--- a/vendor/example.com/b/b.go
++++ b/vendor/example.com/b/b.go
+@@ -0,0 +1 @@
++package b
+`
+	for _, tc := range []struct {
+		description string
+		ignore      IgnoreOptions
+		expectFiles []string
+	}{
+		{
+			description: "default",
+			expectFiles: []string{"main.go", "vendor/example.com/b/b.go"},
+		},
+		{
+			description: "patterns",
+			ignore:      IgnoreOptions{Patterns: []string{"vendor/**"}},
+			expectFiles: []string{"main.go"},
+		},
+		{
+			description: "include generated",
+			ignore:      IgnoreOptions{IncludeGenerated: true},
+			expectFiles: []string{"api/api.pb.go", "main.go", "vendor/example.com/b/b.go"},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			covet, err := Parse(Options{
+				FS:             fs,
+				Diff:           strings.NewReader(strings.TrimSpace(diff)),
+				DiffBaseDir:    ".",
+				GoCoveragePath: "cover.out",
+				Ignore:         tc.ignore,
+			})
+			require.NoError(t, err)
+			var fileNames []string
+			for _, f := range covet.DiffCoverageFiles() {
+				fileNames = append(fileNames, f.Name)
+				if f.Name == "main.go" {
+					assert.Equal(t, []Line{
+						{Covered: false, LineNumber: 7},
+					}, f.Lines)
+				}
+			}
+			sort.Strings(fileNames)
+			assert.Equal(t, tc.expectFiles, fileNames)
+		})
+	}
+}
+
+func TestParseCoverageProfiles(t *testing.T) {
+	t.Parallel()
+	fs := testhelpers.FSWithFiles(t, map[string]string{
+		"go.mod":     `module example.com/a`,
+		"main.go":    "package main\n",
+		"web/app.ts": "export {}\n",
+		"py/app.py":  "import os\n",
+		"cover.out": `
+mode: set
+example.com/a/main.go:1.1,1.10 1 1
+`,
+		"web/coverage/lcov.info": `
+SF:web/app.ts
+DA:1,1
+DA:2,0
+end_of_record
+`,
+		"py/coverage.xml": `<?xml version="1.0" ?>
+<coverage>
+	<sources><source>/py</source></sources>
+	<packages><package><classes>
+		<class filename="app.py"><lines>
+			<line number="1" hits="0"/>
+			<line number="2" hits="0"/>
+		</lines></class>
+	</classes></package></packages>
+</coverage>
+`,
+		"unknown.txt": `hello`,
+	})
+	diff := `
+diff --git a/main.go b/main.go
+index 0000000..1111111 100644
+--- a/main.go
++++ b/main.go
+@@ -0,0 +1 @@
++package main
+diff --git a/web/app.ts b/web/app.ts
+index 0000000..1111111 100644
+--- a/web/app.ts
++++ b/web/app.ts
+@@ -0,0 +1,2 @@
++export {}
++export {}
+diff --git a/py/app.py b/py/app.py
+index 0000000..1111111 100644
+--- a/py/app.py
++++ b/py/app.py
+@@ -0,0 +1,2 @@
++import os
++import sys
+`
+	covet, err := Parse(Options{
+		FS:             fs,
+		Diff:           strings.NewReader(strings.TrimSpace(diff)),
+		DiffBaseDir:    ".",
+		GoCoveragePath: "cover.out",
+		CoverageProfiles: []CoverageProfile{
+			{Path: "web/coverage/lcov.info", BaseDir: "."},
+			{Path: "py/coverage.xml", Format: CoverageFormatCobertura},
+		},
+	})
+	require.NoError(t, err)
+	files := covet.DiffCoverageFiles()
+	sort.Slice(files, func(a, b int) bool {
+		return files[a].Name < files[b].Name
+	})
+	assert.Equal(t, []File{
+		{Name: "main.go", Covered: 1, Lines: []Line{{Covered: true, LineNumber: 1}}},
+		{Name: "py/app.py", Uncovered: 2, Lines: []Line{{LineNumber: 1}, {LineNumber: 2}}},
+		{Name: "web/app.ts", Covered: 1, Uncovered: 1, Lines: []Line{{Covered: true, LineNumber: 1}, {LineNumber: 2}}},
+	}, files)
+
+	_, err = Parse(Options{
+		FS:               fs,
+		Diff:             strings.NewReader(strings.TrimSpace(diff)),
+		DiffBaseDir:      ".",
+		CoverageProfiles: []CoverageProfile{{Path: "unknown.txt"}},
+	})
+	assert.EqualError(t, err, "covet: unrecognized coverage file format: unknown.txt")
+}
+
+func TestParseGoCoverageDir(t *testing.T) {
+	t.Parallel()
+	wd, err := goos.Getwd()
+	require.NoError(t, err)
+	fs, workingDirectory := testhelpers.FromOSToFS(t, wd)
+	baseDir := path.Join(workingDirectory, "testdata", "gocoverdir")
+	diff := `
+diff --git a/greet/greet.go b/greet/greet.go
+index 0000000..1111111 100644
+--- a/greet/greet.go
++++ b/greet/greet.go
+@@ -9,1 +9,6 @@
+ }
++
++// Goodbye returns a farewell
++func Goodbye(name string) string {
++	return "Goodbye, " + name + "!"
++}
+`
+	covet, err := Parse(Options{
+		FS:          fs,
+		Diff:        strings.NewReader(strings.TrimSpace(diff)),
+		DiffBaseDir: baseDir,
+		GoCoverageProfiles: []GoCoverageProfile{
+			{Path: path.Join(baseDir, "covdata"), BaseDir: baseDir},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []File{
+		{
+			Name:      "greet/greet.go",
+			Uncovered: 2,
+			Lines: []Line{
+				{Covered: false, LineNumber: 13},
+				{Covered: false, LineNumber: 14},
+			},
+		},
+	}, covet.DiffCoverageFiles())
+}
+
+func TestParseGitDiff(t *testing.T) {
+	t.Parallel()
+	repoDir := t.TempDir()
+	writeFile := func(name, contents string) {
+		require.NoError(t, goos.WriteFile(filepath.Join(repoDir, name), []byte(strings.TrimSpace(contents)+"\n"), 0o600))
+	}
+	writeFile("go.mod", `module example.com/app`)
+	writeFile("main.go", `
+package main
+
+func main() {
+}
+`)
+	repo, err := git.PlainInit(repoDir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = worktree.Add(".")
+	require.NoError(t, err)
+	_, err = worktree.Commit("initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "Tester", Email: "tester@example.com"},
+	})
+	require.NoError(t, err)
+
+	writeFile("main.go", `
+package main
+
+func main() {
+	println(1)
+	println(2)
+}
+`)
+	writeFile("cover.out", `
+mode: set
+example.com/app/main.go:4.1,4.12 1 1
+example.com/app/main.go:5.1,5.12 1 0
+`)
+
+	fs, fsRepoDir := testhelpers.FromOSToFS(t, repoDir)
+	covet, err := Parse(Options{
+		FS:             fs,
+		GitDiff:        &GitDiffOptions{BaseRef: "HEAD"},
+		DiffBaseDir:    fsRepoDir,
+		GoCoveragePath: path.Join(fsRepoDir, "cover.out"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []File{
+		{
+			Name:      "main.go",
+			Covered:   1,
+			Uncovered: 1,
+			Lines: []Line{
+				{Covered: true, LineNumber: 4},
+				{Covered: false, LineNumber: 5},
+			},
+		},
+	}, covet.DiffCoverageFiles())
+}
+
 func TestParseInvalidOptions(t *testing.T) {
 	t.Parallel()
 	wd, err := goos.Getwd()
@@ -197,6 +566,28 @@ func TestParseInvalidOptions(t *testing.T) {
 			},
 			expectErr: "diff reader must not be nil",
 		},
+		{
+			description: "invalid ignore pattern",
+			options: Options{
+				FS:             wdFS,
+				Diff:           bytes.NewReader(nil),
+				DiffBaseDir:    ".",
+				GoCoveragePath: coverFile,
+				Ignore:         IgnoreOptions{Patterns: []string{"internal/["}},
+			},
+			expectErr: `invalid ignore pattern "internal/[": syntax error in pattern`,
+		},
+		{
+			description: "diff and git diff are mutually exclusive",
+			options: Options{
+				FS:             wdFS,
+				Diff:           bytes.NewReader(nil),
+				GitDiff:        &GitDiffOptions{BaseRef: "HEAD"},
+				DiffBaseDir:    ".",
+				GoCoveragePath: coverFile,
+			},
+			expectErr: "diff reader and git diff options are mutually exclusive",
+		},
 	} {
 		t.Run(tc.description, func(t *testing.T) {
 			t.Parallel()
@@ -229,6 +620,7 @@ func main() {
 			GoCoveragePath:    "subdir/cover.out",
 			GoCoverageBaseDir: ".",
 		},
+		coverageBaseDir: ".",
 	}
 	file := File{
 		Name:      "main.go",
@@ -250,3 +642,88 @@ Coverage: 2 to 6
  }
 `), strings.TrimSpace(buf.String()))
 }
+
+func TestDiffFilePath(t *testing.T) {
+	t.Parallel()
+	fs := testhelpers.FSWithFiles(t, map[string]string{
+		"sub/go.mod": `module example.com/sub`,
+		"sub/main.go": `
+package main
+`,
+		"sub/cover.out": `
+mode: set
+example.com/sub/main.go:1.1,1.7 1 0
+`,
+	})
+	diff := `
+diff --git a/sub/main.go b/sub/main.go
+index 0000000..1111111 100644
+--- a/sub/main.go
++++ b/sub/main.go
+@@ -0,0 +1,1 @@
++added 1
+`
+	covet, err := Parse(Options{
+		FS:             fs,
+		Diff:           strings.NewReader(strings.TrimSpace(diff)),
+		DiffBaseDir:    ".",
+		GoCoveragePath: "sub/cover.out",
+	})
+	require.NoError(t, err)
+	files := covet.DiffCoverageFiles()
+	require.Len(t, files, 1)
+	assert.Equal(t, "main.go", files[0].Name)
+	assert.Equal(t, "sub/main.go", covet.DiffFilePath(files[0]))
+}
+
+func TestFileCoverage(t *testing.T) {
+	t.Parallel()
+	fs := testhelpers.FSWithFiles(t, map[string]string{
+		"go.mod": `module example.com/repo`,
+		"main.go": `package main
+
+func main() {
+	println(1)
+	println(2)
+	println(3)
+}
+`,
+		"cover.out": `
+mode: count
+example.com/repo/main.go:4.1,4.11 1 2
+example.com/repo/main.go:5.1,5.11 1 0
+example.com/repo/main.go:6.1,6.11 1 1
+`,
+	})
+	diff := `
+diff --git a/main.go b/main.go
+index 0000000..1111111 100644
+--- a/main.go
++++ b/main.go
+@@ -5,1 +5,1 @@
+-	println(0)
++	println(2)
+`
+	covet, err := Parse(Options{
+		FS:             fs,
+		Diff:           strings.NewReader(strings.TrimSpace(diff)),
+		DiffBaseDir:    ".",
+		GoCoveragePath: "cover.out",
+	})
+	require.NoError(t, err)
+	files := covet.DiffCoverageFiles()
+	require.Len(t, files, 1)
+	assert.Equal(t, []Line{
+		{Covered: false, LineNumber: 5},
+	}, files[0].Lines)
+	assert.Equal(t, File{
+		Name:      "main.go",
+		Covered:   2,
+		Uncovered: 1,
+		Lines: []Line{
+			{Covered: true, LineNumber: 4, Hits: 2},
+			{Covered: false, LineNumber: 5},
+			{Covered: true, LineNumber: 6, Hits: 1},
+		},
+	}, covet.FileCoverage(files[0]))
+}
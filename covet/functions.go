@@ -0,0 +1,78 @@
+package covet
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/hack-pad/hackpadfs"
+	"github.com/johnstarich/go/covet/internal/coverfile"
+	"github.com/johnstarich/go/covet/internal/funcs"
+	"github.com/pkg/errors"
+)
+
+// Function represents a Go function or method in a Covet report. Includes its name and how many of its lines in the diff are covered.
+type Function = coverfile.Function
+
+// FunctionName returns a function's name qualified by its receiver, like "(*Server).Handle" for methods or "Handle" for functions
+func FunctionName(f Function) string {
+	return funcs.FullName(f.Receiver, f.Name)
+}
+
+// DiffCoverageFunctions returns all Go functions and methods with lines in the diff and coverage information, ordered by file and line number.
+// Source files are read from Options.FS and parsed to find each line's enclosing declaration. Lines outside functions are skipped.
+func (c *Covet) DiffCoverageFunctions() ([]Function, error) {
+	files := c.DiffCoverageFiles()
+	sort.Slice(files, func(a, b int) bool {
+		return files[a].Name < files[b].Name
+	})
+	var functions []Function
+	for _, f := range files {
+		fileFunctions, err := c.fileFunctions(f)
+		if err != nil {
+			return nil, err
+		}
+		functions = append(functions, fileFunctions...)
+	}
+	return functions, nil
+}
+
+func (c *Covet) fileFunctions(f File) ([]Function, error) {
+	if !strings.HasSuffix(f.Name, ".go") {
+		return nil, nil
+	}
+	contents, err := hackpadfs.ReadFile(c.options.FS, path.Join(c.coverageBaseDir, f.Name))
+	if err != nil {
+		return nil, err
+	}
+	decls, err := funcs.Find(contents)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse Go file %q", f.Name)
+	}
+
+	var functions []Function
+	lineIndex := 0
+	for _, decl := range decls {
+		function := Function{
+			File:      f.Name,
+			Name:      decl.Name,
+			Receiver:  decl.Receiver,
+			StartLine: decl.Lines.Start,
+		}
+		for ; lineIndex < len(f.Lines) && f.Lines[lineIndex].LineNumber < decl.Lines.End; lineIndex++ {
+			line := f.Lines[lineIndex]
+			if line.LineNumber < decl.Lines.Start {
+				continue
+			}
+			if line.Covered {
+				function.Covered++
+			} else {
+				function.Uncovered++
+			}
+		}
+		if function.Covered+function.Uncovered > 0 {
+			functions = append(functions, function)
+		}
+	}
+	return functions, nil
+}
@@ -0,0 +1,145 @@
+package covet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/johnstarich/go/covet/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseFunctionsCovet(t *testing.T) *Covet {
+	t.Helper()
+	fs := testhelpers.FSWithFiles(t, map[string]string{
+		"go.mod": `module github.com/org/repo`,
+		"server.go": `package repo
+
+var x = 1
+
+func (s *Server) Handle() {
+	println(1)
+	println(2)
+}
+
+func run() {
+	println(3)
+}
+`,
+		"README.md": `# repo`,
+		"cover.out": `
+mode: set
+github.com/org/repo/server.go:3.1,3.10 1 1
+github.com/org/repo/server.go:6.1,6.11 1 0
+github.com/org/repo/server.go:7.1,7.11 1 0
+github.com/org/repo/server.go:11.1,11.11 1 1
+github.com/org/repo/README.md:1.1,1.7 1 1
+`,
+	})
+	diff := `
+diff --git a/server.go b/server.go
+index 0000000..1111111 100644
+--- a/server.go
++++ b/server.go
+@@ -0,0 +1,12 @@
++package repo
++
++var x = 1
++
++func (s *Server) Handle() {
++	println(1)
++	println(2)
++}
++
++func run() {
++	println(3)
++}
+diff --git a/README.md b/README.md
+index 0000000..1111111 100644
+--- a/README.md
++++ b/README.md
+@@ -0,0 +1 @@
++# repo
+`
+	cov, err := Parse(Options{
+		FS:             fs,
+		Diff:           strings.NewReader(strings.TrimSpace(diff)),
+		DiffBaseDir:    ".",
+		GoCoveragePath: "cover.out",
+	})
+	require.NoError(t, err)
+	return cov
+}
+
+func TestDiffCoverageFunctions(t *testing.T) {
+	t.Parallel()
+	cov := parseFunctionsCovet(t)
+	functions, err := cov.DiffCoverageFunctions()
+	assert.NoError(t, err)
+	assert.Equal(t, []Function{
+		{File: "server.go", Name: "Handle", Receiver: "*Server", StartLine: 5, Uncovered: 2},
+		{File: "server.go", Name: "run", StartLine: 10, Covered: 1},
+	}, functions)
+}
+
+func TestDiffCoverageFunctionsInvalidGo(t *testing.T) {
+	t.Parallel()
+	fs := testhelpers.FSWithFiles(t, map[string]string{
+		"go.mod":  `module github.com/org/repo`,
+		"main.go": `not go`,
+		"cover.out": `
+mode: set
+github.com/org/repo/main.go:1.1,1.7 1 1
+`,
+	})
+	diff := `
+diff --git a/main.go b/main.go
+index 0000000..1111111 100644
+--- a/main.go
++++ b/main.go
+@@ -0,0 +1 @@
++not go
+`
+	cov, err := Parse(Options{
+		FS:             fs,
+		Diff:           strings.NewReader(strings.TrimSpace(diff)),
+		DiffBaseDir:    ".",
+		GoCoveragePath: "cover.out",
+	})
+	require.NoError(t, err)
+	_, err = cov.DiffCoverageFunctions()
+	assert.ErrorContains(t, err, `failed to parse Go file "main.go"`)
+}
+
+func TestFunctionName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "run", FunctionName(Function{Name: "run"}))
+	assert.Equal(t, "(*Server).Handle", FunctionName(Function{Name: "Handle", Receiver: "*Server"}))
+}
+
+func TestReportSummaryFunctions(t *testing.T) {
+	t.Parallel()
+	cov := parseFunctionsCovet(t)
+	var buf bytes.Buffer
+	require.NoError(t, cov.ReportSummaryMarkdown(&buf, ReportSummaryOptions{
+		Target:    90,
+		Functions: true,
+	}))
+	assert.Equal(t, strings.TrimSpace(strings.ReplaceAll(`
+Diff coverage is below target. Add tests for these files:
+|  | Lines | Coverage | File |
+| --- |:---:| --- | --- |
+| 🟠 | ~~2/4~~ | ~~ 50.0% ██▌  ~~ | server.go |
+
+Functions with uncovered lines:
+|  | Lines | Coverage | Function | File |
+| --- |:---:| --- | --- | --- |
+| 🔴 | ~~0/2~~ | ~~  0.0% ▏    ~~ | ~~(*Server).Handle~~ | server.go |
+
+Total coverage of packages in the diff:
+|  | Diff | Total | Package |
+| --- |:---:|:---:| --- |
+| 🟠 | ~~ 60.0%~~ | ~~ 60.0%~~ | . |
+`, "~~", "``")), strings.TrimSpace(buf.String()))
+}
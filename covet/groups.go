@@ -0,0 +1,103 @@
+package covet
+
+import (
+	"bytes"
+	"path"
+	"sort"
+
+	"github.com/hack-pad/hackpadfs"
+	"github.com/johnstarich/go/covet/internal/codeowners"
+	"github.com/pkg/errors"
+)
+
+// Group is a set of files in the diff and their combined diff coverage, like a package or a code owner's files
+type Group struct {
+	// Name is the package's directory relative to DiffBaseDir, or an owner like "@org/team". Empty for files without an owner.
+	Name      string
+	Covered   uint
+	Uncovered uint
+	Files     []File
+}
+
+// Coverage returns the group's diff coverage percentage between 0 and 1
+func (g Group) Coverage() float64 {
+	return float64(g.Covered) / float64(g.Covered+g.Uncovered)
+}
+
+// DiffCoverageByPackage groups DiffCoverageFiles by package directory, sorted by most uncovered lines
+func (c *Covet) DiffCoverageByPackage() []Group {
+	return groupFiles(c.DiffCoverageFiles(), func(f File) []string {
+		return []string{path.Dir(c.DiffFilePath(f))}
+	})
+}
+
+// DiffCoverageByOwner groups DiffCoverageFiles by their owners in a CODEOWNERS file, sorted by most uncovered lines.
+// Files with several owners count toward each owner. Files without an owner are grouped under an empty Name.
+//
+// Reads the CODEOWNERS file from Options.CodeOwnersPath, or searches DiffBaseDir in the same locations as GitHub.
+func (c *Covet) DiffCoverageByOwner() ([]Group, error) {
+	rules, err := c.readCodeOwners()
+	if err != nil {
+		return nil, err
+	}
+	return groupFiles(c.DiffCoverageFiles(), func(f File) []string {
+		owners := rules.Owners(c.DiffFilePath(f))
+		if len(owners) == 0 {
+			return []string{""}
+		}
+		return owners
+	}), nil
+}
+
+func (c *Covet) readCodeOwners() (codeowners.Rules, error) {
+	candidates := []string{c.options.CodeOwnersPath}
+	if c.options.CodeOwnersPath == "" {
+		candidates = nil
+		for _, p := range codeowners.DefaultPaths {
+			candidates = append(candidates, path.Join(c.options.DiffBaseDir, p))
+		}
+	}
+	for _, p := range candidates {
+		contents, err := hackpadfs.ReadFile(c.options.FS, p)
+		if errors.Is(err, hackpadfs.ErrNotExist) && c.options.CodeOwnersPath == "" {
+			continue
+		}
+		if err != nil {
+			return codeowners.Rules{}, err
+		}
+		return codeowners.Parse(bytes.NewReader(contents))
+	}
+	return codeowners.Rules{}, errors.Errorf("no CODEOWNERS file found in %s", c.options.DiffBaseDir)
+}
+
+func groupFiles(files []File, groupNames func(File) []string) []Group {
+	groups := make(map[string]*Group)
+	for _, f := range files {
+		for _, name := range groupNames(f) {
+			group, exists := groups[name]
+			if !exists {
+				group = &Group{Name: name}
+				groups[name] = group
+			}
+			group.Covered += f.Covered
+			group.Uncovered += f.Uncovered
+			group.Files = append(group.Files, f)
+		}
+	}
+
+	sortedGroups := make([]Group, 0, len(groups))
+	for _, group := range groups {
+		sortByPriority(group.Files)
+		sortedGroups = append(sortedGroups, *group)
+	}
+	sort.Slice(sortedGroups, func(aIndex, bIndex int) bool {
+		a, b := sortedGroups[aIndex], sortedGroups[bIndex]
+		switch {
+		case a.Uncovered != b.Uncovered:
+			return a.Uncovered > b.Uncovered
+		default:
+			return a.Name < b.Name
+		}
+	})
+	return sortedGroups
+}
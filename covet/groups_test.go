@@ -0,0 +1,166 @@
+package covet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hack-pad/hackpadfs"
+	"github.com/johnstarich/go/covet/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseGroupsCovet(t *testing.T, files map[string]string, codeOwnersPath string) *Covet {
+	t.Helper()
+	allFiles := map[string]string{
+		"go.mod":    `module example.com/a`,
+		"main.go":   "package main\n",
+		"api/a.go":  "package api\n",
+		"api/b.go":  "package api\n",
+		"web/ui.go": "package web\n",
+		"cover.out": `
+mode: set
+example.com/a/main.go:1.1,1.10 1 1
+example.com/a/api/a.go:1.1,1.10 1 0
+example.com/a/api/b.go:1.1,2.10 1 0
+example.com/a/web/ui.go:1.1,1.10 1 1
+example.com/a/web/ui.go:2.1,2.10 1 0
+`,
+	}
+	for name, contents := range files {
+		allFiles[name] = contents
+	}
+	fs := testhelpers.FSWithFiles(t, allFiles)
+	var diff strings.Builder
+	for _, name := range []string{"main.go", "api/a.go", "api/b.go", "web/ui.go"} {
+		diff.WriteString(`diff --git a/` + name + ` b/` + name + `
+index 0000000..1111111 100644
+--- a/` + name + `
++++ b/` + name + `
+@@ -0,0 +1,2 @@
++added 1
++added 2
+`)
+	}
+	cov, err := Parse(Options{
+		FS:             fs,
+		Diff:           strings.NewReader(diff.String()),
+		DiffBaseDir:    ".",
+		GoCoveragePath: "cover.out",
+		CodeOwnersPath: codeOwnersPath,
+	})
+	require.NoError(t, err)
+	return cov
+}
+
+func TestDiffCoverageByPackage(t *testing.T) {
+	t.Parallel()
+	cov := parseGroupsCovet(t, nil, "")
+	groups := cov.DiffCoverageByPackage()
+	var names []string
+	for _, g := range groups {
+		names = append(names, g.Name)
+	}
+	assert.Equal(t, []string{"api", "web", "."}, names)
+	assert.Equal(t, uint(3), groups[0].Uncovered)
+	assert.Equal(t, []string{"api/b.go", "api/a.go"}, []string{groups[0].Files[0].Name, groups[0].Files[1].Name})
+	assert.Equal(t, 0.5, groups[1].Coverage())
+}
+
+func TestDiffCoverageByOwner(t *testing.T) {
+	t.Parallel()
+	const codeOwners = `
+/api/   @org/api @org/backend
+/web/   @org/web
+/api/b.go
+`
+
+	t.Run("default path", func(t *testing.T) {
+		t.Parallel()
+		cov := parseGroupsCovet(t, map[string]string{
+			".github/CODEOWNERS": codeOwners,
+		}, "")
+		groups, err := cov.DiffCoverageByOwner()
+		assert.NoError(t, err)
+		type groupSummary struct {
+			Name               string
+			Covered, Uncovered uint
+			Files              int
+		}
+		var summaries []groupSummary
+		for _, g := range groups {
+			summaries = append(summaries, groupSummary{g.Name, g.Covered, g.Uncovered, len(g.Files)})
+		}
+		assert.Equal(t, []groupSummary{
+			{Name: "", Covered: 1, Uncovered: 2, Files: 2},
+			{Name: "@org/api", Covered: 0, Uncovered: 1, Files: 1},
+			{Name: "@org/backend", Covered: 0, Uncovered: 1, Files: 1},
+			{Name: "@org/web", Covered: 1, Uncovered: 1, Files: 1},
+		}, summaries)
+	})
+
+	t.Run("custom path", func(t *testing.T) {
+		t.Parallel()
+		cov := parseGroupsCovet(t, map[string]string{
+			"owners.txt": "* @org/all",
+		}, "owners.txt")
+		groups, err := cov.DiffCoverageByOwner()
+		assert.NoError(t, err)
+		require.Len(t, groups, 1)
+		assert.Equal(t, "@org/all", groups[0].Name)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		cov := parseGroupsCovet(t, nil, "")
+		_, err := cov.DiffCoverageByOwner()
+		assert.EqualError(t, err, "no CODEOWNERS file found in .")
+
+		cov = parseGroupsCovet(t, nil, "owners.txt")
+		_, err = cov.DiffCoverageByOwner()
+		assert.ErrorIs(t, err, hackpadfs.ErrNotExist)
+	})
+}
+
+func TestReportSummaryGroups(t *testing.T) {
+	t.Parallel()
+	cov := parseGroupsCovet(t, map[string]string{
+		"CODEOWNERS": "/web/ @org/web",
+	}, "")
+	var buf bytes.Buffer
+	require.NoError(t, cov.ReportSummaryColorTerminal(&buf, ReportSummaryOptions{
+		Target:         0,
+		GroupByPackage: true,
+		GroupByOwner:   true,
+	}))
+	assert.Equal(t, strings.TrimSpace(`
+Successfully reached diff coverage target: >0%
+
+Total coverage of packages in the diff:
+┌────────┬────────┬─────────┐
+│ DIFF   │ TOTAL  │ PACKAGE │
+├────────┼────────┼─────────┤
+│  0.0%  │  0.0%  │ api     │
+│  50.0% │  50.0% │ web     │
+│ 100.0% │ 100.0% │ .       │
+└────────┴────────┴─────────┘
+
+Diff coverage by package:
+┌───────┬───────────────┬─────────┐
+│ LINES │ COVERAGE      │ PACKAGE │
+├───────┼───────────────┼─────────┤
+│  0/3  │   0.0% ▏      │ api     │
+│  1/2  │  50.0% ██▌    │ web     │
+│  1/1  │ 100.0% █████▏ │ .       │
+└───────┴───────────────┴─────────┘
+
+Diff coverage by owner:
+┌───────┬──────────────┬────────────┐
+│ LINES │ COVERAGE     │ OWNER      │
+├───────┼──────────────┼────────────┤
+│  1/4  │  25.0% █▎    │ (no owner) │
+│  1/2  │  50.0% ██▌   │ @org/web   │
+└───────┴──────────────┴────────────┘
+`), strings.TrimSpace(buf.String()))
+}
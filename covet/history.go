@@ -0,0 +1,190 @@
+package covet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/hack-pad/hackpadfs"
+	"github.com/johnstarich/go/covet/internal/span"
+	"github.com/pkg/errors"
+)
+
+const historyFileName = "history.jsonl"
+
+// PackageCoverage is the number of covered and total lines in a package.
+// Includes all lines in the coverage profiles, not just the diff.
+type PackageCoverage struct {
+	// Name is the package's directory relative to DiffBaseDir, like "internal/summary"
+	Name    string `json:"name"`
+	Covered uint   `json:"covered"`
+	Total   uint   `json:"total"`
+}
+
+// Coverage returns the package's coverage percentage between 0 and 1
+func (p PackageCoverage) Coverage() float64 {
+	if p.Total == 0 {
+		return 1
+	}
+	return float64(p.Covered) / float64(p.Total)
+}
+
+// Snapshot records total and per-package coverage at a commit
+type Snapshot struct {
+	Commit   string            `json:"commit"`
+	Time     time.Time         `json:"time"`
+	Covered  uint              `json:"covered"`
+	Total    uint              `json:"total"`
+	Packages []PackageCoverage `json:"packages"`
+}
+
+// Coverage returns the snapshot's total coverage percentage between 0 and 1
+func (s Snapshot) Coverage() float64 {
+	return PackageCoverage{Covered: s.Covered, Total: s.Total}.Coverage()
+}
+
+// Package returns the coverage for package 'name' and true, or false if the package is not in the snapshot
+func (s Snapshot) Package(name string) (PackageCoverage, bool) {
+	index := sort.Search(len(s.Packages), func(i int) bool {
+		return s.Packages[i].Name >= name
+	})
+	if index < len(s.Packages) && s.Packages[index].Name == name {
+		return s.Packages[index], true
+	}
+	return PackageCoverage{}, false
+}
+
+// PackageCoverage returns coverage for every package in the coverage profiles, sorted by name
+func (c *Covet) PackageCoverage() []PackageCoverage {
+	covToDiffRel, _ := c.coverageToDiffRel() // ignore error since it's checked during setup
+	packages := c.packageCoverageByDir()
+	coverage := make([]PackageCoverage, 0, len(packages))
+	for dir, pkg := range packages {
+		pkg.Name = path.Join(covToDiffRel, dir)
+		coverage = append(coverage, pkg)
+	}
+	sort.Slice(coverage, func(a, b int) bool {
+		return coverage[a].Name < coverage[b].Name
+	})
+	return coverage
+}
+
+// packageCoverageByDir returns coverage for every package, keyed by directory relative to the coverage base directory
+func (c *Covet) packageCoverageByDir() map[string]PackageCoverage {
+	packages := make(map[string]PackageCoverage)
+	addLines := func(lines map[string][]span.Span, covered bool) {
+		for file, spans := range lines {
+			dir := path.Dir(file)
+			pkg := packages[dir]
+			for _, s := range spans {
+				pkg.Total += s.Len()
+				if covered {
+					pkg.Covered += s.Len()
+				}
+			}
+			packages[dir] = pkg
+		}
+	}
+	addLines(c.coveredLines, true)
+	addLines(c.uncoveredLines, false)
+	return packages
+}
+
+// Snapshot returns the current total and per-package coverage, labeled with 'commit'
+func (c *Covet) Snapshot(commit string) Snapshot {
+	snapshot := Snapshot{
+		Commit:   commit,
+		Packages: c.PackageCoverage(),
+	}
+	for _, pkg := range snapshot.Packages {
+		snapshot.Covered += pkg.Covered
+		snapshot.Total += pkg.Total
+	}
+	return snapshot
+}
+
+// History is a store of coverage Snapshots, saved as a JSON lines file inside a directory
+type History struct {
+	fs  hackpadfs.FS
+	dir string
+}
+
+// OpenHistory returns a History store for the FS path 'dir'. The directory is created on the first Record.
+func OpenHistory(fs hackpadfs.FS, dir string) (*History, error) {
+	if !hackpadfs.ValidPath(dir) {
+		return nil, errors.Errorf("invalid history directory FS path: %s", dir)
+	}
+	return &History{fs: fs, dir: dir}, nil
+}
+
+// Record appends 's' to the history. If 's' has no Time set, then it is set to the current time.
+func (h *History) Record(s Snapshot) error {
+	if s.Commit == "" {
+		return errors.New("snapshot commit must not be empty")
+	}
+	if s.Time.IsZero() {
+		s.Time = time.Now().UTC()
+	}
+	line, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	const (
+		dirPerm  = 0o700
+		filePerm = 0o600
+	)
+	if err := hackpadfs.MkdirAll(h.fs, h.dir, dirPerm); err != nil {
+		return errors.Wrap(err, "failed to create history directory")
+	}
+	f, err := hackpadfs.OpenFile(h.fs, path.Join(h.dir, historyFileName), hackpadfs.FlagWriteOnly|hackpadfs.FlagCreate|hackpadfs.FlagAppend, filePerm)
+	if err != nil {
+		return err
+	}
+	_, err = hackpadfs.WriteFile(f, append(line, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Snapshots returns all recorded Snapshots in the order they were recorded
+func (h *History) Snapshots() ([]Snapshot, error) {
+	contents, err := hackpadfs.ReadFile(h.fs, path.Join(h.dir, historyFileName))
+	if errors.Is(err, hackpadfs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []Snapshot
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(nil, len(contents)+1)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var snapshot Snapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snapshot); err != nil {
+			return nil, errors.Wrapf(err, "malformed history file on line %d", lineNumber)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, scanner.Err()
+}
+
+// Find returns the most recent Snapshot recorded for 'commit' and true, or false if none exist
+func (h *History) Find(commit string) (Snapshot, bool, error) {
+	snapshots, err := h.Snapshots()
+	if err != nil {
+		return Snapshot{}, false, err
+	}
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if snapshots[i].Commit == commit {
+			return snapshots[i], true, nil
+		}
+	}
+	return Snapshot{}, false, nil
+}
@@ -0,0 +1,157 @@
+package covet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hack-pad/hackpadfs"
+	"github.com/johnstarich/go/covet/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseHistoryCovet(t *testing.T) (*Covet, hackpadfs.FS) {
+	t.Helper()
+	fs := testhelpers.FSWithFiles(t, map[string]string{
+		"go.mod":   `module example.com/a`,
+		"main.go":  "package main\n",
+		"b/b.go":   "package b\n",
+		"c/c.go":   "package c\n",
+		"my.patch": ``,
+		"cover.out": `
+mode: set
+example.com/a/main.go:1.1,2.10 1 1
+example.com/a/b/b.go:1.1,1.10 1 1
+example.com/a/b/b.go:2.1,2.10 1 0
+example.com/a/c/c.go:1.1,3.10 1 0
+`,
+	})
+	diff := `
+diff --git a/b/b.go b/b/b.go
+index 0000000..1111111 100644
+--- a/b/b.go
++++ b/b/b.go
+@@ -0,0 +1,2 @@
++added 1
++added 2
+`
+	cov, err := Parse(Options{
+		FS:             fs,
+		Diff:           strings.NewReader(strings.TrimSpace(diff)),
+		DiffBaseDir:    ".",
+		GoCoveragePath: "cover.out",
+	})
+	require.NoError(t, err)
+	return cov, fs
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+	cov, _ := parseHistoryCovet(t)
+	snapshot := cov.Snapshot("abc")
+	assert.Equal(t, Snapshot{
+		Commit:  "abc",
+		Covered: 3,
+		Total:   7,
+		Packages: []PackageCoverage{
+			{Name: ".", Covered: 2, Total: 2},
+			{Name: "b", Covered: 1, Total: 2},
+			{Name: "c", Covered: 0, Total: 3},
+		},
+	}, snapshot)
+	assert.InDelta(t, 3.0/7, snapshot.Coverage(), 0.0001)
+
+	pkg, found := snapshot.Package("b")
+	assert.True(t, found)
+	assert.Equal(t, 0.5, pkg.Coverage())
+	_, found = snapshot.Package("d")
+	assert.False(t, found)
+	assert.Equal(t, 1.0, PackageCoverage{}.Coverage())
+}
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+	fs := testhelpers.FSWithFiles(t, nil)
+	history, err := OpenHistory(fs, "ci/history")
+	require.NoError(t, err)
+
+	snapshots, err := history.Snapshots()
+	assert.NoError(t, err)
+	assert.Empty(t, snapshots)
+
+	someTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := Snapshot{Commit: "abc", Time: someTime, Covered: 1, Total: 2}
+	second := Snapshot{Commit: "def", Time: someTime, Covered: 2, Total: 2}
+	third := Snapshot{Commit: "abc", Covered: 2, Total: 4}
+	require.NoError(t, history.Record(first))
+	require.NoError(t, history.Record(second))
+	require.NoError(t, history.Record(third))
+	assert.EqualError(t, history.Record(Snapshot{}), "snapshot commit must not be empty")
+
+	snapshots, err = history.Snapshots()
+	assert.NoError(t, err)
+	require.Len(t, snapshots, 3)
+	assert.Equal(t, []Snapshot{first, second}, snapshots[:2])
+	assert.False(t, snapshots[2].Time.IsZero())
+
+	found, exists, err := history.Find("abc")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, uint(4), found.Total)
+
+	_, exists, err = history.Find("xyz")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestHistoryErrors(t *testing.T) {
+	t.Parallel()
+	fs := testhelpers.FSWithFiles(t, map[string]string{
+		"history/history.jsonl": "{}\nnot json\n",
+	})
+	_, err := OpenHistory(fs, "/os-path/not/ok")
+	assert.EqualError(t, err, "invalid history directory FS path: /os-path/not/ok")
+
+	history, err := OpenHistory(fs, "history")
+	require.NoError(t, err)
+	_, _, err = history.Find("abc")
+	assert.EqualError(t, err, "malformed history file on line 2: invalid character 'o' in literal null (expecting 'u')")
+}
+
+func TestReportSummaryBaseline(t *testing.T) {
+	t.Parallel()
+	cov, _ := parseHistoryCovet(t)
+	var buf bytes.Buffer
+	require.NoError(t, cov.ReportSummaryMarkdown(&buf, ReportSummaryOptions{
+		Target: 0,
+		Baseline: &Snapshot{
+			Commit:  "0123456789abcdef0123456789abcdef01234567",
+			Covered: 4,
+			Total:   7,
+			Packages: []PackageCoverage{
+				{Name: ".", Covered: 1, Total: 2},
+				{Name: "b", Covered: 2, Total: 2},
+				{Name: "c", Covered: 1, Total: 3},
+			},
+		},
+	}))
+	nbsp := "\u00a0"
+	expect := strings.ReplaceAll(`
+Successfully reached diff coverage target: >0%
+
+Total coverage of packages in the diff:
+|  | Diff | Total | Package |
+| --- |:---:|:---:| --- |
+| 🟠 | ~~`+nbsp+`50.0%~~ | ~~`+nbsp+`50.0%~~ | b |
+
+Total coverage is 42.9% (-14.3% compared to ~~0123456~~).
+Packages with lower coverage:
+| Coverage | Change | Package |
+| --- | --- | --- |
+| ~~`+nbsp+`50.0%~~ | ~~-50.0%~~ | b |
+| ~~`+nbsp+nbsp+`0.0%~~ | ~~-33.3%~~ | c |
+`, "~~", "``")
+	assert.Equal(t, strings.TrimSpace(expect), strings.TrimSpace(buf.String()))
+}
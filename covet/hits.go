@@ -0,0 +1,70 @@
+package covet
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/johnstarich/go/covet/internal/span"
+	"github.com/johnstarich/go/covet/internal/summary"
+)
+
+// WeaklyCoveredLines returns covered lines in 'f' which ran fewer than 'minHits' times.
+// Lines without hit counts are skipped, since only 'count' and 'atomic' mode Go coverage profiles record them. See Line.Hits.
+func WeaklyCoveredLines(f File, minHits uint) []Line {
+	var lines []Line
+	for _, line := range f.Lines {
+		if line.Covered && line.Hits > 0 && line.Hits < minHits {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// lineSpans returns the smallest set of sorted spans containing every line in 'lines'
+func lineSpans(lines []Line) []span.Span {
+	spans := make([]span.Span, 0, len(lines))
+	for _, line := range lines {
+		spans = append(spans, span.Span{Start: line.LineNumber, End: line.LineNumber + 1})
+	}
+	return span.Union(spans)
+}
+
+// weaklyCoveredSummary returns a table of files with weakly covered lines, or an empty string if there are none
+func (c *Covet) weaklyCoveredSummary(minHits uint, format summary.Format) string {
+	var files []summary.WeakFile
+	for _, f := range c.DiffCoverageFiles() {
+		lines := WeaklyCoveredLines(f, minHits)
+		if len(lines) == 0 {
+			continue
+		}
+		weakFile := summary.WeakFile{
+			Name:       f.Name,
+			Lines:      uint(len(lines)),
+			FewestHits: lines[0].Hits,
+		}
+		for _, line := range lines {
+			if line.Hits < weakFile.FewestHits {
+				weakFile.FewestHits = line.Hits
+			}
+		}
+		files = append(files, weakFile)
+	}
+	sort.Slice(files, func(a, b int) bool {
+		if files[a].Lines != files[b].Lines {
+			return files[a].Lines > files[b].Lines
+		}
+		return files[a].Name < files[b].Name
+	})
+	if weakReport := summary.NewWeaklyCovered(files, minHits, format); weakReport != "" {
+		return "\n" + weakReport
+	}
+	return ""
+}
+
+// formatHits returns a line's hit count, like "12 hits"
+func formatHits(hits uint) string {
+	if hits == 1 {
+		return "1 hit"
+	}
+	return fmt.Sprintf("%d hits", hits)
+}
@@ -0,0 +1,176 @@
+package covet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/johnstarich/go/covet/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseHitsCovet(t *testing.T) *Covet {
+	t.Helper()
+	fs := testhelpers.FSWithFiles(t, map[string]string{
+		"go.mod": `module github.com/org/repo`,
+		"main.go": `package main
+
+func main() {
+	println(1)
+	println(2)
+	println(3)
+	println(4)
+}
+`,
+		"unit.out": `
+mode: count
+github.com/org/repo/main.go:4.1,4.11 1 100
+github.com/org/repo/main.go:5.1,5.11 1 1
+github.com/org/repo/main.go:5.12,5.20 1 2
+github.com/org/repo/main.go:6.1,6.11 1 0
+github.com/org/repo/main.go:7.1,7.11 1 0
+`,
+		"integ.out": `
+mode: atomic
+github.com/org/repo/main.go:4.1,4.11 1 20
+github.com/org/repo/main.go:6.1,6.11 1 3
+`,
+		"set.out": `
+mode: set
+github.com/org/repo/main.go:7.1,7.11 1 1
+`,
+	})
+	diff := `
+diff --git a/main.go b/main.go
+index 0000000..1111111 100644
+--- a/main.go
++++ b/main.go
+@@ -0,0 +1,8 @@
++package main
++
++func main() {
++	println(1)
++	println(2)
++	println(3)
++	println(4)
++}
+`
+	cov, err := Parse(Options{
+		FS:          fs,
+		Diff:        strings.NewReader(strings.TrimSpace(diff)),
+		DiffBaseDir: ".",
+		GoCoverageProfiles: []GoCoverageProfile{
+			{Path: "unit.out"},
+			{Path: "integ.out"},
+			{Path: "set.out"},
+		},
+	})
+	require.NoError(t, err)
+	return cov
+}
+
+func TestLineHits(t *testing.T) {
+	t.Parallel()
+	cov := parseHitsCovet(t)
+	files := cov.DiffCoverageFiles()
+	require.Len(t, files, 1)
+	assert.Equal(t, []Line{
+		{Covered: true, LineNumber: 4, Hits: 120},
+		{Covered: true, LineNumber: 5, Hits: 2},
+		{Covered: true, LineNumber: 6, Hits: 3},
+		{Covered: true, LineNumber: 7},
+	}, files[0].Lines)
+
+	assert.Equal(t, []Line{
+		{Covered: true, LineNumber: 5, Hits: 2},
+		{Covered: true, LineNumber: 6, Hits: 3},
+	}, WeaklyCoveredLines(files[0], 5))
+	assert.Empty(t, WeaklyCoveredLines(files[0], 2))
+}
+
+func TestReportSummaryWeaklyCovered(t *testing.T) {
+	t.Parallel()
+	cov := parseHitsCovet(t)
+	var buf bytes.Buffer
+	require.NoError(t, cov.ReportSummaryColorTerminal(&buf, ReportSummaryOptions{Target: 90, MinHits: 5}))
+	assert.Equal(t, strings.TrimSpace(`
+Successfully reached diff coverage target: >90%
+
+Total coverage of packages in the diff:
+┌────────┬────────┬─────────┐
+│ DIFF   │ TOTAL  │ PACKAGE │
+├────────┼────────┼─────────┤
+│ 100.0% │ 100.0% │ .       │
+└────────┴────────┴─────────┘
+
+Weakly covered lines, run fewer than 5 times:
+┌───────┬─────────────┬─────────┐
+│ LINES │ FEWEST HITS │ FILE    │
+├───────┼─────────────┼─────────┤
+│ 2     │ 2           │ main.go │
+└───────┴─────────────┴─────────┘
+`), strings.TrimSpace(buf.String()))
+}
+
+func TestReportFileCoverageShowHits(t *testing.T) {
+	t.Parallel()
+	cov := parseHitsCovet(t)
+	files := cov.DiffCoverageFiles()
+	require.Len(t, files, 1)
+
+	var buf bytes.Buffer
+	require.NoError(t, cov.ReportFileCoverage(&buf, files[0], ReportFileCoverageOptions{ShowHits: true}))
+	assert.Equal(t, strings.TrimSpace(`
+Coverage: 2 to 8
+ 
+ func main() {
++	println(1)  (120 hits)
++	println(2)  (2 hits)
++	println(3)  (3 hits)
++	println(4)
+ }
+`), strings.TrimSpace(buf.String()))
+}
+
+func TestReportJSONHits(t *testing.T) {
+	t.Parallel()
+	cov := parseHitsCovet(t)
+	var buf bytes.Buffer
+	require.NoError(t, cov.ReportJSON(&buf, ReportJSONOptions{Target: 90, ShowHits: true, MinHits: 5}))
+	assert.Contains(t, buf.String(), `
+      "uncoveredLines": [],
+      "weaklyCoveredLines": [
+        {
+          "startLine": 5,
+          "endLine": 6
+        }
+      ],
+      "lineHits": [
+        {
+          "line": 4,
+          "hits": 120
+        },
+        {
+          "line": 5,
+          "hits": 2
+        },
+        {
+          "line": 6,
+          "hits": 3
+        }
+      ]
+`)
+}
+
+func TestReportHTMLHits(t *testing.T) {
+	t.Parallel()
+	cov := parseHitsCovet(t)
+	var buf bytes.Buffer
+	require.NoError(t, cov.ReportHTML(&buf, ReportHTMLOptions{Target: 90, ShowHits: true, MinHits: 5}))
+	html := buf.String()
+	assert.Contains(t, html, `<tr class="separator"><td colspan="4">Lines 4 to 4</td></tr>`)
+	assert.Contains(t, html, `<tr class="covered" id="file-1-L4"><td class="line-number"><a href="#file-1-L4">4</a></td><td class="op">&#43;</td><td class="hits">120×</td>`)
+	assert.Contains(t, html, `<tr class="weak" id="file-1-L5"><td class="line-number"><a href="#file-1-L5">5</a></td><td class="op">&#43;</td><td class="hits">2×</td>`)
+	assert.Contains(t, html, `<tr class="covered" id="file-1-L7"><td class="line-number"><a href="#file-1-L7">7</a></td><td class="op">&#43;</td><td class="hits"></td>`)
+}
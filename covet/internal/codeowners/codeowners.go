@@ -0,0 +1,113 @@
+// Package codeowners parses CODEOWNERS files to find the owners of files in a repository.
+package codeowners
+
+import (
+	"bufio"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/johnstarich/go/covet/internal/ignore"
+	"github.com/pkg/errors"
+)
+
+// DefaultPaths are the locations searched for a CODEOWNERS file, relative to the repository's root directory.
+// Matches GitHub's search order.
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var DefaultPaths = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+// Rules are the ordered owner rules in a CODEOWNERS file
+type Rules struct {
+	rules []rule
+}
+
+type rule struct {
+	pattern string
+	dirOnly bool
+	// matchesContents is true if files inside a matching directory also match, like "/docs" or "docs/".
+	// False for patterns with a wildcard in the last segment, like "docs/*", which only match direct children.
+	matchesContents bool
+	owners          []string
+}
+
+// Parse reads a CODEOWNERS file from 'r'.
+// Each line is a gitignore-style path pattern followed by zero or more owners, like "/internal/ @org/team".
+//
+// See https://docs.github.com/en/repositories/managing-your-repositorys-settings-and-features/customizing-your-repository/about-code-owners
+func Parse(r io.Reader) (Rules, error) {
+	var rules Rules
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i != -1 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		rule := newRule(fields[0], fields[1:])
+		if err := ignore.ValidatePattern(rule.pattern); err != nil {
+			return Rules{}, errors.Wrapf(err, "invalid CODEOWNERS pattern %q on line %d", fields[0], lineNumber)
+		}
+		rules.rules = append(rules.rules, rule)
+	}
+	return rules, scanner.Err()
+}
+
+func newRule(pattern string, owners []string) rule {
+	var r rule
+	if len(owners) > 0 {
+		r.owners = owners
+	}
+	if strings.HasSuffix(pattern, "/") {
+		r.dirOnly = true
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+	r.matchesContents = r.dirOnly || !strings.ContainsAny(path.Base(pattern), "*?[")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if !anchored {
+		pattern = "**/" + pattern // unanchored patterns match at any depth
+	}
+	r.pattern = pattern
+	return r
+}
+
+// Owners returns the owners of the repository-relative file path 'name'.
+// The last matching rule wins. Returns nil if no rule matches or the matching rule has no owners.
+func (r Rules) Owners(name string) []string {
+	name = path.Clean(name)
+	for i := len(r.rules) - 1; i >= 0; i-- {
+		if r.rules[i].matches(name) {
+			return r.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// matches returns true if the rule matches 'name', or if it matches the contents of any of its parent directories
+func (r rule) matches(name string) bool {
+	if !r.dirOnly && r.matchPath(name) {
+		return true
+	}
+	if !r.matchesContents {
+		return false
+	}
+	for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+		if r.matchPath(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r rule) matchPath(name string) bool {
+	matched, _ := ignore.MatchPath(r.pattern, name) // pattern is validated in Parse()
+	return matched
+}
@@ -0,0 +1,62 @@
+package codeowners
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwners(t *testing.T) {
+	t.Parallel()
+	rules, err := Parse(strings.NewReader(`
+# Default owners
+*       @org/everyone
+
+*.js    @org/web # JavaScript
+/build/logs/ @org/build
+docs/   @org/docs
+apps/**/api @org/api
+/cmd    @org/cli
+/cmd/generated.go
+`))
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		name   string
+		expect []string
+	}{
+		{name: "main.go", expect: []string{"@org/everyone"}},
+		{name: "web/app.js", expect: []string{"@org/web"}},
+		{name: "build/logs/out.txt", expect: []string{"@org/build"}},
+		{name: "build/logs/a/b/out.txt", expect: []string{"@org/build"}},
+		{name: "build/logs", expect: []string{"@org/everyone"}},
+		{name: "src/build/logs/out.txt", expect: []string{"@org/everyone"}},
+		{name: "docs/index.md", expect: []string{"@org/docs"}},
+		{name: "a/docs/b/index.md", expect: []string{"@org/docs"}},
+		{name: "docs", expect: []string{"@org/everyone"}},
+		{name: "apps/a/b/api/main.go", expect: []string{"@org/api"}},
+		{name: "cmd/main.go", expect: []string{"@org/cli"}},
+		{name: "internal/cmd/main.go", expect: []string{"@org/everyone"}},
+		{name: "cmd/generated.go", expect: nil},
+	} {
+		assert.Equal(t, tc.expect, rules.Owners(tc.name), tc.name)
+	}
+}
+
+func TestOwnersWildcardLastSegment(t *testing.T) {
+	t.Parallel()
+	rules, err := Parse(strings.NewReader(`
+*       @org/everyone
+docs/*  @org/docs
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"@org/docs"}, rules.Owners("docs/a.go"))
+	assert.Equal(t, []string{"@org/everyone"}, rules.Owners("docs/a/b.go"), "Wildcards in the last segment should only match direct children")
+}
+
+func TestParseInvalidPattern(t *testing.T) {
+	t.Parallel()
+	_, err := Parse(strings.NewReader("* @org/a\n[ @org/b\n"))
+	assert.EqualError(t, err, `invalid CODEOWNERS pattern "[" on line 2: syntax error in pattern`)
+}
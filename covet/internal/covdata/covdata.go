@@ -0,0 +1,442 @@
+// Package covdata reads binary coverage data directories, like those written to GOCOVERDIR by binaries built with 'go build -cover'.
+//
+// The file formats mirror the Go toolchain's internal/coverage packages, which are not importable.
+// Only the subset necessary to generate coverage profiles is decoded.
+package covdata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/hack-pad/hackpadfs"
+	"github.com/pkg/errors"
+	"golang.org/x/tools/cover"
+)
+
+const (
+	metaFilePrefix    = "covmeta."
+	counterFilePrefix = "covcounters."
+)
+
+//nolint:gochecknoglobals // Magic strings are constant byte arrays, which Go does not support as consts.
+var (
+	metaFileMagic    = [4]byte{0x00, 0x63, 0x76, 0x6d}
+	counterFileMagic = [4]byte{0x00, 0x63, 0x77, 0x6d}
+)
+
+const (
+	metaFileVersion    = 1
+	counterFileVersion = 1
+)
+
+type metaFileHeader struct {
+	Magic        [4]byte
+	Version      uint32
+	TotalLength  uint64
+	Entries      uint64
+	MetaFileHash [16]byte
+	StrTabOffset uint32
+	StrTabLength uint32
+	CMode        uint8
+	CGranularity uint8
+	_            [6]byte
+}
+
+type metaPackageHeader struct {
+	Length     uint32
+	PkgName    uint32
+	PkgPath    uint32
+	ModulePath uint32
+	MetaHash   [16]byte
+	_          byte
+	_          [3]byte
+	NumFiles   uint32
+	NumFuncs   uint32
+}
+
+type counterFileHeader struct {
+	Magic     [4]byte
+	Version   uint32
+	MetaHash  [16]byte
+	CFlavor   uint8
+	BigEndian bool
+	_         [6]byte
+}
+
+type counterSegmentHeader struct {
+	FcnEntries uint64
+	StrTabLen  uint32
+	ArgsLen    uint32
+}
+
+type counterFileFooter struct {
+	Magic       [4]byte
+	_           [4]byte
+	NumSegments uint32
+	_           [4]byte
+}
+
+const (
+	counterFlavorRaw     = 1
+	counterFlavorULEB128 = 2
+)
+
+// modes indexed by the meta file's counter mode
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var counterModes = map[uint8]string{
+	1: "set",
+	2: "count",
+	3: "atomic",
+}
+
+// unit is a coverable block of source code, with its execution count
+type unit struct {
+	StartLine, StartCol uint32
+	EndLine, EndCol     uint32
+	NumStmts            uint32
+	Count               uint32
+}
+
+type function struct {
+	SourceFile string
+	Units      []unit
+}
+
+type metaPackage struct {
+	Functions []function
+}
+
+type metaFile struct {
+	Mode     string
+	Packages []metaPackage
+}
+
+// ReadDir reads all coverage meta-data and counter data files inside 'dir' and returns them as coverage profiles.
+// Counters for the same code blocks are summed across counter files.
+func ReadDir(fs hackpadfs.FS, dir string) ([]*cover.Profile, error) {
+	dirEntries, err := hackpadfs.ReadDir(fs, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	metaFiles := make(map[[16]byte]*metaFile)
+	var metaHashes [][16]byte
+	var counterFileNames []string
+	for _, entry := range dirEntries {
+		name := entry.Name()
+		switch {
+		case entry.IsDir():
+		case strings.HasPrefix(name, metaFilePrefix):
+			contents, err := hackpadfs.ReadFile(fs, path.Join(dir, name))
+			if err != nil {
+				return nil, err
+			}
+			hash, meta, err := readMetaFile(contents)
+			if err != nil {
+				return nil, errors.WithMessage(err, name)
+			}
+			if _, exists := metaFiles[hash]; !exists {
+				metaHashes = append(metaHashes, hash)
+			}
+			metaFiles[hash] = meta
+		case strings.HasPrefix(name, counterFilePrefix):
+			counterFileNames = append(counterFileNames, name)
+		}
+	}
+	if len(metaFiles) == 0 {
+		return nil, errors.Errorf("no coverage meta-data files found in directory: %s", dir)
+	}
+
+	for _, name := range counterFileNames {
+		contents, err := hackpadfs.ReadFile(fs, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		if err := readCounterFile(contents, metaFiles); err != nil {
+			return nil, errors.WithMessage(err, name)
+		}
+	}
+
+	var profiles []*cover.Profile
+	profileIndexes := make(map[string]int)
+	for _, hash := range metaHashes {
+		meta := metaFiles[hash]
+		for _, pkg := range meta.Packages {
+			for _, fn := range pkg.Functions {
+				index, exists := profileIndexes[fn.SourceFile]
+				if !exists {
+					index = len(profiles)
+					profileIndexes[fn.SourceFile] = index
+					profiles = append(profiles, &cover.Profile{
+						FileName: fn.SourceFile,
+						Mode:     meta.Mode,
+					})
+				}
+				profile := profiles[index]
+				for _, u := range fn.Units {
+					profile.Blocks = append(profile.Blocks, cover.ProfileBlock{
+						StartLine: int(u.StartLine),
+						StartCol:  int(u.StartCol),
+						EndLine:   int(u.EndLine),
+						EndCol:    int(u.EndCol),
+						NumStmt:   int(u.NumStmts),
+						Count:     int(u.Count),
+					})
+				}
+			}
+		}
+	}
+	for _, profile := range profiles {
+		sort.SliceStable(profile.Blocks, func(a, b int) bool {
+			blockA, blockB := profile.Blocks[a], profile.Blocks[b]
+			if blockA.StartLine != blockB.StartLine {
+				return blockA.StartLine < blockB.StartLine
+			}
+			return blockA.StartCol < blockB.StartCol
+		})
+	}
+	return profiles, nil
+}
+
+func readMetaFile(contents []byte) ([16]byte, *metaFile, error) {
+	r := bytes.NewReader(contents)
+	var header metaFileHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return [16]byte{}, nil, err
+	}
+	if header.Magic != metaFileMagic {
+		return [16]byte{}, nil, errors.New("invalid coverage meta-data file: bad magic string")
+	}
+	if header.Version > metaFileVersion {
+		return [16]byte{}, nil, errors.Errorf("unsupported coverage meta-data file version: %d", header.Version)
+	}
+	offsets := make([]uint64, header.Entries)
+	if err := binary.Read(r, binary.LittleEndian, offsets); err != nil {
+		return [16]byte{}, nil, err
+	}
+	lengths := make([]uint64, header.Entries)
+	if err := binary.Read(r, binary.LittleEndian, lengths); err != nil {
+		return [16]byte{}, nil, err
+	}
+
+	meta := &metaFile{
+		Mode: counterModes[header.CMode],
+	}
+	for i := range offsets {
+		start, end := offsets[i], offsets[i]+lengths[i]
+		if end > uint64(len(contents)) || start > end {
+			return [16]byte{}, nil, errors.Errorf("malformed coverage meta-data package offset: %d", start)
+		}
+		pkg, err := readMetaPackage(contents[start:end])
+		if err != nil {
+			return [16]byte{}, nil, err
+		}
+		meta.Packages = append(meta.Packages, pkg)
+	}
+	return header.MetaFileHash, meta, nil
+}
+
+func readMetaPackage(payload []byte) (metaPackage, error) {
+	r := bytes.NewReader(payload)
+	var header metaPackageHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return metaPackage{}, err
+	}
+	funcOffsets := make([]uint32, header.NumFuncs)
+	if err := binary.Read(r, binary.LittleEndian, funcOffsets); err != nil {
+		return metaPackage{}, err
+	}
+	strs, err := readStringTable(r)
+	if err != nil {
+		return metaPackage{}, err
+	}
+	getString := func(index uint64) (string, error) {
+		if index >= uint64(len(strs)) {
+			return "", errors.Errorf("malformed coverage meta-data string index: %d", index)
+		}
+		return strs[index], nil
+	}
+
+	var pkg metaPackage
+	for _, offset := range funcOffsets {
+		if _, err := r.Seek(int64(offset), io.SeekStart); err != nil {
+			return metaPackage{}, err
+		}
+		var fields [3]uint64 // number of units, function name index, file name index
+		for i := range fields {
+			fields[i], err = binary.ReadUvarint(r)
+			if err != nil {
+				return metaPackage{}, err
+			}
+		}
+		numUnits, fileIndex := fields[0], fields[2]
+		fn := function{
+			Units: make([]unit, 0, numUnits),
+		}
+		fn.SourceFile, err = getString(fileIndex)
+		if err != nil {
+			return metaPackage{}, err
+		}
+		for range numUnits {
+			var unitFields [5]uint64 // start line, start column, end line, end column, number of statements
+			for i := range unitFields {
+				unitFields[i], err = binary.ReadUvarint(r)
+				if err != nil {
+					return metaPackage{}, err
+				}
+			}
+			fn.Units = append(fn.Units, unit{
+				StartLine: uint32(unitFields[0]), //nolint:gosec // Line and column numbers are encoded from uint32 values
+				StartCol:  uint32(unitFields[1]), //nolint:gosec // Line and column numbers are encoded from uint32 values
+				EndLine:   uint32(unitFields[2]), //nolint:gosec // Line and column numbers are encoded from uint32 values
+				EndCol:    uint32(unitFields[3]), //nolint:gosec // Line and column numbers are encoded from uint32 values
+				NumStmts:  uint32(unitFields[4]), //nolint:gosec // Line and column numbers are encoded from uint32 values
+			})
+		}
+		pkg.Functions = append(pkg.Functions, fn)
+	}
+	return pkg, nil
+}
+
+func readStringTable(r io.ByteReader) ([]string, error) {
+	numStrings, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	var strs []string
+	for range numStrings {
+		length, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		var sb strings.Builder
+		for range length {
+			b, err := r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			sb.WriteByte(b)
+		}
+		strs = append(strs, sb.String())
+	}
+	return strs, nil
+}
+
+func readCounterFile(contents []byte, metaFiles map[[16]byte]*metaFile) error {
+	r := bytes.NewReader(contents)
+	var header counterFileHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+	if header.Magic != counterFileMagic {
+		return errors.New("invalid coverage counter data file: bad magic string")
+	}
+	if header.Version > counterFileVersion {
+		return errors.Errorf("unsupported coverage counter data file version: %d", header.Version)
+	}
+	meta, found := metaFiles[header.MetaHash]
+	if !found {
+		return errors.Errorf("coverage meta-data file not found for hash: %x", header.MetaHash)
+	}
+
+	var footer counterFileFooter
+	footerSize := int64(binary.Size(footer))
+	if int64(len(contents)) < footerSize {
+		return errors.New("invalid coverage counter data file: too short")
+	}
+	if err := binary.Read(bytes.NewReader(contents[int64(len(contents))-footerSize:]), binary.LittleEndian, &footer); err != nil {
+		return err
+	}
+	if footer.Magic != counterFileMagic {
+		return errors.New("invalid coverage counter data file: bad footer magic string")
+	}
+
+	readUint32, err := counterReader(r, header)
+	if err != nil {
+		return err
+	}
+	for range footer.NumSegments {
+		segment, err := readCounterSegmentPreamble(r)
+		if err != nil {
+			return err
+		}
+		for range segment.FcnEntries {
+			if err := readCounterFunction(readUint32, meta); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func counterReader(r *bytes.Reader, header counterFileHeader) (func() (uint32, error), error) {
+	switch header.CFlavor {
+	case counterFlavorULEB128:
+		return func() (uint32, error) {
+			value, err := binary.ReadUvarint(r)
+			return uint32(value), err //nolint:gosec // Counters are encoded from uint32 values
+		}, nil
+	case counterFlavorRaw:
+		var byteOrder binary.ByteOrder = binary.LittleEndian
+		if header.BigEndian {
+			byteOrder = binary.BigEndian
+		}
+		return func() (uint32, error) {
+			var value uint32
+			err := binary.Read(r, byteOrder, &value)
+			return value, err
+		}, nil
+	default:
+		return nil, errors.Errorf("unsupported coverage counter flavor: %d", header.CFlavor)
+	}
+}
+
+// readCounterSegmentPreamble reads a segment header and skips over its string table, arguments, and padding.
+func readCounterSegmentPreamble(r *bytes.Reader) (counterSegmentHeader, error) {
+	var segment counterSegmentHeader
+	if err := binary.Read(r, binary.LittleEndian, &segment); err != nil {
+		return counterSegmentHeader{}, err
+	}
+	offset, err := r.Seek(int64(segment.StrTabLen)+int64(segment.ArgsLen), io.SeekCurrent)
+	if err != nil {
+		return counterSegmentHeader{}, err
+	}
+	const alignment = 4
+	if remainder := offset % alignment; remainder != 0 {
+		if _, err := r.Seek(alignment-remainder, io.SeekCurrent); err != nil {
+			return counterSegmentHeader{}, err
+		}
+	}
+	return segment, nil
+}
+
+func readCounterFunction(readUint32 func() (uint32, error), meta *metaFile) error {
+	var fields [3]uint32 // number of counters, package index, function index
+	for i := range fields {
+		var err error
+		fields[i], err = readUint32()
+		if err != nil {
+			return err
+		}
+	}
+	numCounters, pkgIndex, funcIndex := fields[0], fields[1], fields[2]
+	if int(pkgIndex) >= len(meta.Packages) || int(funcIndex) >= len(meta.Packages[pkgIndex].Functions) {
+		return errors.Errorf("coverage counter data does not match meta-data: package %d, function %d", pkgIndex, funcIndex)
+	}
+	units := meta.Packages[pkgIndex].Functions[funcIndex].Units
+	for i := range numCounters {
+		count, err := readUint32()
+		if err != nil {
+			return err
+		}
+		if int(i) < len(units) {
+			units[i].Count += count
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package covdata
+
+import (
+	goos "os"
+	"path/filepath"
+	"testing"
+
+	"github.com/johnstarich/go/covet/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/cover"
+)
+
+func TestReadDir(t *testing.T) {
+	t.Parallel()
+	wd, err := goos.Getwd()
+	require.NoError(t, err)
+	// Generated by building a small program with 'go build -cover', then running it twice with GOCOVERDIR set.
+	fs, coverDir := testhelpers.FromOSToFS(t, filepath.Join(wd, "..", "..", "testdata", "gocoverdir", "covdata"))
+	profiles, err := ReadDir(fs, coverDir)
+	require.NoError(t, err)
+	assert.Equal(t, []*cover.Profile{
+		{
+			FileName: "example.com/app/greet/greet.go",
+			Mode:     "set",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 5, StartCol: 2, EndLine: 5, EndCol: 16, NumStmt: 1, Count: 2},
+				{StartLine: 6, StartCol: 3, EndLine: 7, EndCol: 1, NumStmt: 1, Count: 1},
+				{StartLine: 8, StartCol: 2, EndLine: 8, EndCol: 31, NumStmt: 1, Count: 1},
+				{StartLine: 13, StartCol: 2, EndLine: 14, EndCol: 1, NumStmt: 1, Count: 0},
+			},
+		},
+		{
+			FileName: "example.com/app/main.go",
+			Mode:     "set",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 11, StartCol: 2, EndLine: 11, EndCol: 25, NumStmt: 1, Count: 2},
+				{StartLine: 12, StartCol: 3, EndLine: 13, EndCol: 1, NumStmt: 1, Count: 2},
+			},
+		},
+	}, profiles)
+}
+
+func TestReadDirErrors(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		description string
+		files       map[string]string
+		expectErr   string
+	}{
+		{
+			description: "no meta-data files",
+			files: map[string]string{
+				"covdata/other.txt": "",
+			},
+			expectErr: "no coverage meta-data files found in directory: covdata",
+		},
+		{
+			description: "bad meta-data file",
+			files: map[string]string{
+				"covdata/covmeta.abc": "not a meta-data file, but long enough to read a full header from it",
+			},
+			expectErr: "covmeta.abc: invalid coverage meta-data file: bad magic string",
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			fs := testhelpers.FSWithFiles(t, tc.files)
+			_, err := ReadDir(fs, "covdata")
+			assert.EqualError(t, err, tc.expectErr)
+		})
+	}
+
+	t.Run("missing directory", func(t *testing.T) {
+		t.Parallel()
+		fs := testhelpers.FSWithFiles(t, nil)
+		_, err := ReadDir(fs, "covdata")
+		assert.ErrorIs(t, err, goos.ErrNotExist)
+	})
+}
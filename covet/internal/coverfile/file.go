@@ -56,6 +56,10 @@ func (f File) findContextSpans(contextLines uint) []span.Span {
 type Line struct {
 	Covered    bool
 	LineNumber uint
+	// Labels are the labels of coverage profiles which covered this line, like "unit" or "integration". Unlabelled profiles are not included.
+	Labels []string
+	// Hits is the number of times this line ran, from 'count' or 'atomic' mode Go coverage profiles. Zero if unknown.
+	Hits uint
 }
 
 const noOpPrefix = " "
@@ -79,9 +83,14 @@ type DiffChunk struct {
 
 // DiffChunks return diff-like Chunks from a covet.File and the file contents' Reader.
 func DiffChunks(file File, fileReader io.Reader) ([]DiffChunk, error) {
+	const defaultContextLines = 2
+	return DiffChunksWithContext(file, fileReader, defaultContextLines)
+}
+
+// DiffChunksWithContext is like DiffChunks, but includes 'contextLines' unchanged lines around each line in the diff.
+func DiffChunksWithContext(file File, fileReader io.Reader, contextLines uint) ([]DiffChunk, error) {
 	var chunks []DiffChunk
 	iter := newLineIterator(fileReader)
-	const contextLines = 2
 	var lineNumber uint = 1
 	diffLineIndex := 0
 	for _, s := range file.findContextSpans(contextLines) {
@@ -146,3 +155,44 @@ func (l *lineIterator) NextLines(n uint) ([]string, error) {
 	}
 	return lines, nil
 }
+
+// UncoveredSpans returns the spans of consecutive uncovered lines in 'f', ordered by line number
+func UncoveredSpans(f File) []span.Span {
+	var uncoveredLines []span.Span
+	ok := true
+	var nextLineIndex int
+	for ok {
+		var uncovered span.Span
+		uncovered, ok, nextLineIndex = findFirstUncoveredLines(f.Lines, nextLineIndex)
+		if ok {
+			uncoveredLines = append(uncoveredLines, uncovered)
+		}
+	}
+	return uncoveredLines
+}
+
+func findFirstUncoveredLines(lines []Line, startIndex int) (uncovered span.Span, ok bool, nextLineIndex int) {
+	// find start
+	nextLineIndex = startIndex
+	for _, l := range lines[nextLineIndex:] {
+		nextLineIndex++
+		if !l.Covered {
+			n := l.LineNumber
+			uncovered = span.Span{
+				Start: n,
+				End:   n + 1,
+			}
+			ok = true
+			break
+		}
+	}
+	// find next line number jump or covered line
+	for _, l := range lines[nextLineIndex:] {
+		if l.Covered || l.LineNumber != uncovered.End {
+			break
+		}
+		nextLineIndex++
+		uncovered.End++
+	}
+	return
+}
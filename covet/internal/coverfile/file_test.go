@@ -132,6 +132,13 @@ func TestDiffChunks(t *testing.T) {
 			},
 		},
 	}, chunks)
+
+	chunks, err = DiffChunksWithContext(file, strings.NewReader(diff), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []DiffChunk{
+		{FirstLine: 5, LastLine: 5, Lines: []string{"+5:"}},
+		{FirstLine: 8, LastLine: 8, Lines: []string{"-8: last line"}},
+	}, chunks)
 }
 
 func TestLineIterator(t *testing.T) {
@@ -167,3 +174,21 @@ line 3
 		}, lines)
 	})
 }
+
+func TestUncoveredSpans(t *testing.T) {
+	t.Parallel()
+	spans := UncoveredSpans(File{
+		Lines: []Line{
+			{Covered: false, LineNumber: 1},
+			{Covered: true, LineNumber: 3},
+			{Covered: false, LineNumber: 4},
+			{Covered: false, LineNumber: 5},
+			{Covered: false, LineNumber: 7},
+		},
+	})
+	assert.Equal(t, []span.Span{
+		{Start: 1, End: 2},
+		{Start: 4, End: 6},
+		{Start: 7, End: 8},
+	}, spans)
+}
@@ -0,0 +1,18 @@
+package coverfile
+
+// Function represents a Go function or method in a Covet report. Includes its name and how many of its lines in the diff are covered.
+//
+// NOTE: This struct is in package 'coverfile' to expose in the main 'covet' package while breaking import cycles.
+// Avoid exporting any methods or unnecessary data fields.
+type Function struct {
+	// File is the name of the function's File
+	File string
+	// Name is the function's name, like "Handle"
+	Name string
+	// Receiver is the method's receiver type, like "*Server". Empty for functions.
+	Receiver string
+	// StartLine is the line number of the function's declaration
+	StartLine uint
+	Covered   uint
+	Uncovered uint
+}
@@ -0,0 +1,215 @@
+// Package coverprofile reads coverage files from any supported test runner into covered and uncovered line spans.
+package coverprofile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/johnstarich/go/covet/internal/span"
+	"github.com/pkg/errors"
+)
+
+// Format is a coverage file format
+type Format int
+
+// Supported coverage file formats
+const (
+	// FormatAuto detects the format from the coverage file's contents
+	FormatAuto Format = iota
+	// FormatGo is a Go coverage profile, written by 'go test -coverprofile'
+	FormatGo
+	// FormatLCOV is an LCOV tracefile, written by tools like Istanbul, c8, and genhtml
+	FormatLCOV
+	// FormatCobertura is a Cobertura XML report, written by tools like coverage.py and Jest
+	FormatCobertura
+)
+
+//nolint:gochecknoglobals // Read-only lookup table
+var formatNames = map[Format]string{
+	FormatAuto:      "auto",
+	FormatGo:        "go",
+	FormatLCOV:      "lcov",
+	FormatCobertura: "cobertura",
+}
+
+// ParseFormat returns the Format for the given name. e.g. "auto", "go", "lcov", or "cobertura"
+func ParseFormat(name string) (Format, error) {
+	for format, formatName := range formatNames {
+		if name == formatName {
+			return format, nil
+		}
+	}
+	return 0, errors.Errorf("unsupported coverage format %q, must be one of: auto, go, lcov, cobertura", name)
+}
+
+func (f Format) String() string {
+	if name, ok := formatNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// DetectFormat returns the Format of a coverage file from its 'contents'. Returns FormatAuto if no format matched.
+func DetectFormat(contents []byte) Format {
+	contents = bytes.TrimPrefix(contents, []byte("\xef\xbb\xbf")) // byte order mark
+	contents = bytes.TrimSpace(contents)
+	switch {
+	case bytes.HasPrefix(contents, []byte("mode:")):
+		return FormatGo
+	case bytes.HasPrefix(contents, []byte("<")) && bytes.Contains(contents, []byte("<coverage")):
+		return FormatCobertura
+	case bytes.HasPrefix(contents, []byte("TN:")), bytes.HasPrefix(contents, []byte("SF:")):
+		return FormatLCOV
+	default:
+		return FormatAuto
+	}
+}
+
+// Profile is the parsed contents of a coverage file
+type Profile struct {
+	// Sources are the directories Files may be relative to, in priority order. Only Cobertura reports set Sources.
+	Sources []string
+	// Files are sorted by name
+	Files []File
+}
+
+// File is a source file's line coverage
+type File struct {
+	// Name is the file's path as written in the coverage file. May be relative or an absolute OS path.
+	Name      string
+	Covered   []span.Span
+	Uncovered []span.Span
+}
+
+// Parse reads a coverage file in 'format' from 'r'. FormatGo and FormatAuto are not supported.
+func Parse(format Format, r io.Reader) (Profile, error) {
+	switch format {
+	case FormatLCOV:
+		return ParseLCOV(r)
+	case FormatCobertura:
+		return ParseCobertura(r)
+	default:
+		return Profile{}, errors.Errorf("unsupported coverage format: %s", format)
+	}
+}
+
+// fileLines collects line hits for each file
+type fileLines map[string]*File
+
+func (f fileLines) add(name string, line uint, hits int64) {
+	file, exists := f[name]
+	if !exists {
+		file = &File{Name: name}
+		f[name] = file
+	}
+	lineSpan := span.Span{Start: line, End: line + 1}
+	if hits > 0 {
+		file.Covered = append(file.Covered, lineSpan)
+	} else {
+		file.Uncovered = append(file.Uncovered, lineSpan)
+	}
+}
+
+func (f fileLines) files() []File {
+	files := make([]File, 0, len(f))
+	for _, file := range f {
+		file.Covered = span.Union(file.Covered)
+		file.Uncovered = span.Subtract(span.Union(file.Uncovered), file.Covered)
+		files = append(files, *file)
+	}
+	sort.Slice(files, func(a, b int) bool {
+		return files[a].Name < files[b].Name
+	})
+	return files
+}
+
+// ParseLCOV reads an LCOV tracefile from 'r'. Uses each record's source file (SF) and line hits (DA).
+//
+// See https://manpages.debian.org/unstable/lcov/geninfo.1.en.html#TRACEFILE_FORMAT
+func ParseLCOV(r io.Reader) (Profile, error) {
+	lines := make(fileLines)
+	scanner := bufio.NewScanner(r)
+	var currentFile string
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		key, value, _ := strings.Cut(line, ":")
+		switch key {
+		case "SF":
+			currentFile = value
+		case "end_of_record":
+			currentFile = ""
+		case "DA":
+			if currentFile == "" {
+				return Profile{}, errors.Errorf("malformed LCOV file on line %d: DA record without a source file", lineNumber)
+			}
+			fields := strings.Split(value, ",")
+			const minFields = 2
+			if len(fields) < minFields {
+				return Profile{}, errors.Errorf("malformed LCOV file on line %d: expected 'DA:<line>,<hits>', got %q", lineNumber, line)
+			}
+			sourceLine, err := strconv.ParseUint(fields[0], 10, 32)
+			if err != nil {
+				return Profile{}, errors.Wrapf(err, "malformed LCOV file on line %d", lineNumber)
+			}
+			hits, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return Profile{}, errors.Wrapf(err, "malformed LCOV file on line %d", lineNumber)
+			}
+			lines.add(currentFile, uint(sourceLine), hits)
+		}
+	}
+	return Profile{Files: lines.files()}, scanner.Err()
+}
+
+type coberturaReport struct {
+	Sources  []string           `xml:"sources>source"`
+	Packages []coberturaPackage `xml:"packages>package"`
+}
+
+type coberturaPackage struct {
+	Classes []coberturaClass `xml:"classes>class"`
+}
+
+type coberturaClass struct {
+	FileName string          `xml:"filename,attr"`
+	Lines    []coberturaLine `xml:"lines>line"`
+}
+
+type coberturaLine struct {
+	Number uint  `xml:"number,attr"`
+	Hits   int64 `xml:"hits,attr"`
+}
+
+// ParseCobertura reads a Cobertura XML report from 'r'. Uses each class's file name and line hits.
+//
+// See https://github.com/cobertura/cobertura/blob/master/cobertura/src/site/htdocs/xml/coverage-04.dtd
+func ParseCobertura(r io.Reader) (Profile, error) {
+	var report coberturaReport
+	if err := xml.NewDecoder(r).Decode(&report); err != nil {
+		return Profile{}, errors.Wrap(err, "malformed Cobertura XML file")
+	}
+	lines := make(fileLines)
+	for _, pkg := range report.Packages {
+		for _, class := range pkg.Classes {
+			for _, line := range class.Lines {
+				lines.add(class.FileName, line.Number, line.Hits)
+			}
+		}
+	}
+	var sources []string
+	for _, source := range report.Sources {
+		if source = strings.TrimSpace(source); source != "" {
+			sources = append(sources, source)
+		}
+	}
+	return Profile{
+		Sources: sources,
+		Files:   lines.files(),
+	}, nil
+}
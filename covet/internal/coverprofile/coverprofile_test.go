@@ -0,0 +1,147 @@
+package coverprofile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/johnstarich/go/covet/internal/span"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+	for format, name := range formatNames {
+		parsed, err := ParseFormat(name)
+		assert.NoError(t, err)
+		assert.Equal(t, format, parsed)
+		assert.Equal(t, name, format.String())
+	}
+	_, err := ParseFormat("xml")
+	assert.EqualError(t, err, `unsupported coverage format "xml", must be one of: auto, go, lcov, cobertura`)
+	assert.Equal(t, "Format(-1)", Format(-1).String())
+}
+
+func TestDetectFormat(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		contents string
+		expect   Format
+	}{
+		{contents: "mode: set\n", expect: FormatGo},
+		{contents: "TN:\nSF:a.ts\n", expect: FormatLCOV},
+		{contents: "\xef\xbb\xbfSF:a.ts\n", expect: FormatLCOV},
+		{contents: `<?xml version="1.0" ?><coverage></coverage>`, expect: FormatCobertura},
+		{contents: "  \n<coverage>", expect: FormatCobertura},
+		{contents: "<html>", expect: FormatAuto},
+		{contents: "", expect: FormatAuto},
+	} {
+		assert.Equal(t, tc.expect, DetectFormat([]byte(tc.contents)), tc.contents)
+	}
+}
+
+func TestParseLCOV(t *testing.T) {
+	t.Parallel()
+	profile, err := Parse(FormatLCOV, strings.NewReader(`
+TN:
+SF:src/b.ts
+FN:1,main
+DA:1,1
+DA:2,0
+DA:3,0
+DA:5,4
+LF:4
+LH:2
+end_of_record
+SF:/home/runner/src/a.ts
+DA:1,0,abc123
+end_of_record
+SF:src/b.ts
+DA:2,1
+end_of_record
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, Profile{
+		Files: []File{
+			{
+				Name:      "/home/runner/src/a.ts",
+				Uncovered: []span.Span{{Start: 1, End: 2}},
+			},
+			{
+				Name:      "src/b.ts",
+				Covered:   []span.Span{{Start: 1, End: 3}, {Start: 5, End: 6}},
+				Uncovered: []span.Span{{Start: 3, End: 4}},
+			},
+		},
+	}, profile)
+}
+
+func TestParseLCOVErrors(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		contents  string
+		expectErr string
+	}{
+		{
+			contents:  "DA:1,1",
+			expectErr: "malformed LCOV file on line 1: DA record without a source file",
+		},
+		{
+			contents:  "SF:a.ts\nDA:1",
+			expectErr: `malformed LCOV file on line 2: expected 'DA:<line>,<hits>', got "DA:1"`,
+		},
+		{
+			contents:  "SF:a.ts\nDA:x,1",
+			expectErr: `malformed LCOV file on line 2: strconv.ParseUint: parsing "x": invalid syntax`,
+		},
+		{
+			contents:  "SF:a.ts\nDA:1,x",
+			expectErr: `malformed LCOV file on line 2: strconv.ParseInt: parsing "x": invalid syntax`,
+		},
+	} {
+		_, err := ParseLCOV(strings.NewReader(tc.contents))
+		assert.EqualError(t, err, tc.expectErr)
+	}
+}
+
+func TestParseCobertura(t *testing.T) {
+	t.Parallel()
+	profile, err := Parse(FormatCobertura, strings.NewReader(`<?xml version="1.0" ?>
+<coverage version="7.2.7" line-rate="0.5">
+	<sources>
+		<source>/home/runner/work/repo</source>
+		<source> </source>
+	</sources>
+	<packages>
+		<package name="app">
+			<classes>
+				<class name="main.py" filename="app/main.py">
+					<methods/>
+					<lines>
+						<line number="1" hits="1"/>
+						<line number="2" hits="0"/>
+						<line number="4" hits="3"/>
+					</lines>
+				</class>
+			</classes>
+		</package>
+	</packages>
+</coverage>
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, Profile{
+		Sources: []string{"/home/runner/work/repo"},
+		Files: []File{
+			{
+				Name:      "app/main.py",
+				Covered:   []span.Span{{Start: 1, End: 2}, {Start: 4, End: 5}},
+				Uncovered: []span.Span{{Start: 2, End: 3}},
+			},
+		},
+	}, profile)
+
+	_, err = ParseCobertura(strings.NewReader(`<coverage>`))
+	assert.EqualError(t, err, "malformed Cobertura XML file: XML syntax error on line 1: unexpected EOF")
+
+	_, err = Parse(FormatGo, strings.NewReader(""))
+	assert.EqualError(t, err, "unsupported coverage format: go")
+}
@@ -31,6 +31,15 @@ func New(f float64) Status {
 	}
 }
 
+// NewWithMinimum categorizes the given percentage like New, but returns an error status if 'f' is below 'minimum'.
+// Both 'f' and 'minimum' are between 0 and 1.
+func NewWithMinimum(f, minimum float64) Status {
+	if f < minimum {
+		return coverageError
+	}
+	return New(f)
+}
+
 // WorkflowCommand returns a GitHub Actions workflow command for this coverage status.
 // These are specifically the log "level" commands.
 func (s Status) WorkflowCommand() string {
@@ -46,6 +55,38 @@ func (s Status) WorkflowCommand() string {
 	}
 }
 
+// SARIFLevel returns a SARIF result level for this coverage status
+func (s Status) SARIFLevel() string {
+	switch s {
+	case coverageExcellent, coverageGood:
+		return "note"
+	case coverageOK, coverageWarning:
+		return "warning"
+	case coverageError:
+		return "error"
+	default:
+		return "error"
+	}
+}
+
+// Name returns this status's level name, like "excellent" or "error"
+func (s Status) Name() string {
+	switch s {
+	case coverageExcellent:
+		return "excellent"
+	case coverageGood:
+		return "good"
+	case coverageOK:
+		return "ok"
+	case coverageWarning:
+		return "warning"
+	case coverageError:
+		return "error"
+	default:
+		return "error"
+	}
+}
+
 func boldGreen() *color.Color { return color.New(color.Bold, color.FgGreen) }
 func green() *color.Color     { return color.New(color.FgGreen) }
 func yellow() *color.Color    { return color.New(color.FgYellow) }
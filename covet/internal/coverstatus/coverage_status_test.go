@@ -31,6 +31,24 @@ func TestNewCoverageStatus(t *testing.T) {
 	}
 }
 
+func TestNewCoverageStatusWithMinimum(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		f, minimum float64
+		status     Status
+	}{
+		{0.5, 0, coverageWarning},
+		{0.5, 0.5, coverageWarning},
+		{0.85, 0.9, coverageError},
+		{1.0, 0.9, coverageExcellent},
+	} {
+		t.Run(fmt.Sprint(tc.f, tc.minimum, tc.status), func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.status, NewWithMinimum(tc.f, tc.minimum))
+		})
+	}
+}
+
 func TestCoverageStatusWorkflowCommand(t *testing.T) {
 	t.Parallel()
 	for _, tc := range []struct {
@@ -51,6 +69,46 @@ func TestCoverageStatusWorkflowCommand(t *testing.T) {
 	}
 }
 
+func TestCoverageStatusSARIFLevel(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		status Status
+		level  string
+	}{
+		{coverageExcellent, "note"},
+		{coverageGood, "note"},
+		{coverageOK, "warning"},
+		{coverageWarning, "warning"},
+		{coverageError, "error"},
+		{Status(-1), "error"},
+	} {
+		t.Run(fmt.Sprint(tc.status, tc.level), func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.level, tc.status.SARIFLevel())
+		})
+	}
+}
+
+func TestCoverageStatusName(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		status Status
+		name   string
+	}{
+		{coverageExcellent, "excellent"},
+		{coverageGood, "good"},
+		{coverageOK, "ok"},
+		{coverageWarning, "warning"},
+		{coverageError, "error"},
+		{Status(-1), "error"},
+	} {
+		t.Run(fmt.Sprint(tc.status, tc.name), func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.name, tc.status.Name())
+		})
+	}
+}
+
 func TestCoverageStatusEmoji(t *testing.T) {
 	t.Parallel()
 	for _, tc := range []struct {
@@ -26,6 +26,16 @@ func CommonBase(a, b string) string {
 			break
 		}
 	}
+	atSeparator := func(s string) bool {
+		return i == len(s) || s[i:i+1] == separator
+	}
+	if !atSeparator(a) || !atSeparator(b) {
+		// stopped inside a path element, so back up to the last full element
+		i = strings.LastIndex(a[:i], separator)
+		if i == -1 {
+			i = 0
+		}
+	}
 	return path.Clean(a[:i])
 }
 
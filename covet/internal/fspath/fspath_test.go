@@ -42,6 +42,16 @@ func TestCommonBase(t *testing.T) {
 			b:      "a/b/d",
 			expect: "a/b",
 		},
+		{
+			a:      "a/foo",
+			b:      "a/fob",
+			expect: "a",
+		},
+		{
+			a:      "a/b",
+			b:      "a/b/c",
+			expect: "a/b",
+		},
 	} {
 		t.Run(fmt.Sprintf("%s --> %s", noSlashes(tc.a), noSlashes(tc.b)), func(t *testing.T) {
 			t.Parallel()
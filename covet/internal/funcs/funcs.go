@@ -0,0 +1,57 @@
+// Package funcs finds Go function and method declarations in source files.
+package funcs
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+
+	"github.com/johnstarich/go/covet/internal/span"
+)
+
+// Func is a function or method declaration
+type Func struct {
+	// Name is the function's name, like "Handle"
+	Name string
+	// Receiver is the method's receiver type, like "*Server". Empty for functions.
+	Receiver string
+	// Lines is the span of lines covered by the declaration, from the 'func' keyword to the closing brace
+	Lines span.Span
+}
+
+// Find returns all top-level function and method declarations in Go source code 'src', ordered by line number
+func Find(src []byte) ([]Func, error) {
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	var funcs []Func
+	for _, decl := range file.Decls {
+		funcDecl, isFunc := decl.(*ast.FuncDecl)
+		if !isFunc {
+			continue
+		}
+		fn := Func{
+			Name: funcDecl.Name.Name,
+			Lines: span.Span{
+				Start: uint(fileSet.Position(funcDecl.Pos()).Line),
+				End:   uint(fileSet.Position(funcDecl.End()).Line) + 1,
+			},
+		}
+		if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
+			fn.Receiver = types.ExprString(funcDecl.Recv.List[0].Type)
+		}
+		funcs = append(funcs, fn)
+	}
+	return funcs, nil
+}
+
+// FullName returns a function's name qualified by its receiver, like "(*Server).Handle" for methods or "Handle" for functions
+func FullName(receiver, name string) string {
+	if receiver == "" {
+		return name
+	}
+	return "(" + receiver + ")." + name
+}
@@ -0,0 +1,49 @@
+package funcs
+
+import (
+	"testing"
+
+	"github.com/johnstarich/go/covet/internal/span"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+	const src = `package main
+
+import "fmt"
+
+var x = func() int { return 1 }()
+
+// Handle handles
+// things
+func (s *Server) Handle() {
+	fmt.Println("handled")
+}
+
+func (l List[T]) Len() int { return 0 }
+
+func main() {
+	func() {}()
+}
+`
+	funcs, err := Find([]byte(src))
+	assert.NoError(t, err)
+	assert.Equal(t, []Func{
+		{Name: "Handle", Receiver: "*Server", Lines: span.Span{Start: 9, End: 12}},
+		{Name: "Len", Receiver: "List[T]", Lines: span.Span{Start: 13, End: 14}},
+		{Name: "main", Lines: span.Span{Start: 15, End: 18}},
+	}, funcs)
+}
+
+func TestFindInvalidSource(t *testing.T) {
+	t.Parallel()
+	_, err := Find([]byte(`not go`))
+	assert.Error(t, err)
+}
+
+func TestFullName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "Handle", FullName("", "Handle"))
+	assert.Equal(t, "(*Server).Handle", FullName("*Server", "Handle"))
+}
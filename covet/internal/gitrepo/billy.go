@@ -0,0 +1,143 @@
+package gitrepo
+
+import (
+	"os"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/hack-pad/hackpadfs"
+)
+
+// readOnlyFS adapts a hackpadfs.FS into a read-only billy.Filesystem for use with go-git
+type readOnlyFS struct {
+	fs   hackpadfs.FS
+	root string
+}
+
+var _ billy.Filesystem = readOnlyFS{}
+
+func newReadOnlyFS(fs hackpadfs.FS, root string) billy.Filesystem {
+	return readOnlyFS{fs: fs, root: root}
+}
+
+func (r readOnlyFS) fsPath(filename string) string {
+	filename = strings.TrimPrefix(path.Clean("/"+filename), "/")
+	return path.Join(r.root, filename)
+}
+
+func (r readOnlyFS) Create(string) (billy.File, error) {
+	return nil, billy.ErrReadOnly
+}
+
+func (r readOnlyFS) Open(filename string) (billy.File, error) {
+	f, err := r.fs.Open(r.fsPath(filename))
+	if err != nil {
+		return nil, err
+	}
+	return readOnlyFile{File: f, name: filename}, nil
+}
+
+func (r readOnlyFS) OpenFile(filename string, flag int, _ os.FileMode) (billy.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_APPEND|os.O_TRUNC) != 0 {
+		return nil, billy.ErrReadOnly
+	}
+	return r.Open(filename)
+}
+
+func (r readOnlyFS) Stat(filename string) (os.FileInfo, error) {
+	return hackpadfs.Stat(r.fs, r.fsPath(filename))
+}
+
+func (r readOnlyFS) Rename(string, string) error {
+	return billy.ErrReadOnly
+}
+
+func (r readOnlyFS) Remove(string) error {
+	return billy.ErrReadOnly
+}
+
+func (r readOnlyFS) Join(elem ...string) string {
+	return path.Join(elem...)
+}
+
+func (r readOnlyFS) TempFile(string, string) (billy.File, error) {
+	return nil, billy.ErrReadOnly
+}
+
+func (r readOnlyFS) ReadDir(dir string) ([]os.FileInfo, error) {
+	dirEntries, err := hackpadfs.ReadDir(r.fs, r.fsPath(dir))
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]os.FileInfo, 0, len(dirEntries))
+	for _, entry := range dirEntries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+func (r readOnlyFS) MkdirAll(string, os.FileMode) error {
+	return billy.ErrReadOnly
+}
+
+func (r readOnlyFS) Lstat(filename string) (os.FileInfo, error) {
+	return hackpadfs.LstatOrStat(r.fs, r.fsPath(filename))
+}
+
+func (r readOnlyFS) Symlink(string, string) error {
+	return billy.ErrReadOnly
+}
+
+func (r readOnlyFS) Readlink(string) (string, error) {
+	return "", billy.ErrNotSupported
+}
+
+func (r readOnlyFS) Chroot(p string) (billy.Filesystem, error) {
+	return newReadOnlyFS(r.fs, r.fsPath(p)), nil
+}
+
+func (r readOnlyFS) Root() string {
+	return r.root
+}
+
+func (r readOnlyFS) Capabilities() billy.Capability {
+	return billy.ReadCapability | billy.SeekCapability
+}
+
+type readOnlyFile struct {
+	hackpadfs.File
+	name string
+}
+
+func (f readOnlyFile) Name() string {
+	return f.name
+}
+
+func (f readOnlyFile) Write([]byte) (int, error) {
+	return 0, billy.ErrReadOnly
+}
+
+func (f readOnlyFile) ReadAt(p []byte, off int64) (int, error) {
+	return hackpadfs.ReadAtFile(f.File, p, off)
+}
+
+func (f readOnlyFile) Seek(offset int64, whence int) (int64, error) {
+	return hackpadfs.SeekFile(f.File, offset, whence)
+}
+
+func (f readOnlyFile) Lock() error {
+	return nil
+}
+
+func (f readOnlyFile) Unlock() error {
+	return nil
+}
+
+func (f readOnlyFile) Truncate(int64) error {
+	return billy.ErrReadOnly
+}
@@ -0,0 +1,381 @@
+// Package gitrepo computes diffs directly from git repositories.
+package gitrepo
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	fdiff "github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/hack-pad/hackpadfs"
+	"github.com/johnstarich/go/covet/internal/fspath"
+	"github.com/pkg/errors"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// Options contains options to compute a diff
+type Options struct {
+	// BaseRef is the revision to compare against, like "origin/main". Required.
+	BaseRef string
+	// HeadRef is the revision with new changes, like "HEAD".
+	// Defaults to the working tree, including uncommitted changes to tracked files.
+	HeadRef string
+	// MergeBase compares HeadRef against the merge base of BaseRef and HeadRef, like 'git diff BaseRef...HeadRef'.
+	MergeBase bool
+}
+
+// Diff returns a unified diff for the git repository containing 'dir' inside 'fs'.
+// Like 'git diff --relative', paths are relative to 'dir' and files outside 'dir' are skipped.
+// Renamed files are detected, so only their changed lines appear as additions.
+func Diff(ctx context.Context, fs hackpadfs.FS, dir string, options Options) (string, error) {
+	if options.BaseRef == "" {
+		return "", errors.New("base ref must not be empty")
+	}
+	repo, worktreeDir, err := openRepo(fs, dir)
+	if err != nil {
+		return "", err
+	}
+	relDir, err := fspath.Rel(worktreeDir, dir)
+	if err != nil {
+		return "", err
+	}
+
+	baseCommit, err := resolveCommit(repo, options.BaseRef)
+	if err != nil {
+		return "", err
+	}
+	headRef := options.HeadRef
+	if headRef == "" {
+		headRef = string(plumbing.HEAD)
+	}
+	headCommit, err := resolveCommit(repo, headRef)
+	if err != nil {
+		return "", err
+	}
+	if options.MergeBase {
+		baseCommit, err = mergeBase(baseCommit, headCommit)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	changes, err := diffCommits(ctx, baseCommit, headCommit)
+	if err != nil {
+		return "", err
+	}
+	if options.HeadRef != "" {
+		patch, err := changes.PatchContext(ctx)
+		if err != nil {
+			return "", err
+		}
+		return encodePatch(relativePatch(patch, relDir))
+	}
+	return diffWorktree(repo, fs, worktreeDir, relDir, baseCommit, changes)
+}
+
+// ResolveCommit returns the full commit hash for 'rev' in the git repository containing 'dir' inside 'fs', like "HEAD" or "origin/main"
+func ResolveCommit(fs hackpadfs.FS, dir, rev string) (string, error) {
+	repo, _, err := openRepo(fs, dir)
+	if err != nil {
+		return "", err
+	}
+	commit, err := resolveCommit(repo, rev)
+	if err != nil {
+		return "", err
+	}
+	return commit.Hash.String(), nil
+}
+
+func resolveCommit(repo *git.Repository, ref string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve git revision %q", ref)
+	}
+	return repo.CommitObject(*hash)
+}
+
+func mergeBase(a, b *object.Commit) (*object.Commit, error) {
+	bases, err := a.MergeBase(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(bases) == 0 {
+		return nil, errors.Errorf("no merge base found for commits %s and %s", a.Hash, b.Hash)
+	}
+	return bases[0], nil
+}
+
+func diffCommits(ctx context.Context, base, head *object.Commit) (object.Changes, error) {
+	baseTree, err := base.Tree()
+	if err != nil {
+		return nil, err
+	}
+	headTree, err := head.Tree()
+	if err != nil {
+		return nil, err
+	}
+	return object.DiffTreeWithOptions(ctx, baseTree, headTree, object.DefaultDiffTreeOptions)
+}
+
+// diffWorktree generates a patch from the base commit to the current working tree.
+// Includes committed 'changes' since the base commit and any uncommitted changes to tracked files.
+// Only files inside 'relDir', relative to the working tree's root at 'worktreeDir', are included.
+func diffWorktree(repo *git.Repository, fs hackpadfs.FS, worktreeDir, relDir string, base *object.Commit, changes object.Changes) (string, error) {
+	baseTree, err := base.Tree()
+	if err != nil {
+		return "", err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return "", err
+	}
+
+	var files []*fileChange
+	filesByNewPath := make(map[string]*fileChange)
+	addFile := func(f *fileChange) {
+		files = append(files, f)
+		if f.To != nil {
+			filesByNewPath[f.To.Name] = f
+		}
+	}
+	for _, change := range changes {
+		f := &fileChange{}
+		if change.From.Name != "" {
+			f.From, err = treeFile(baseTree, change.From.Name)
+			if err != nil {
+				return "", err
+			}
+		}
+		if change.To.Name != "" {
+			f.To = &fileContents{Name: change.To.Name}
+		}
+		addFile(f)
+	}
+
+	for name, fileStatus := range status {
+		if fileStatus.Worktree == git.Untracked {
+			continue
+		}
+		if _, exists := filesByNewPath[name]; exists {
+			continue
+		}
+		f := &fileChange{
+			To: &fileContents{Name: name},
+		}
+		f.From, err = treeFile(baseTree, name)
+		if err != nil {
+			return "", err
+		}
+		addFile(f)
+	}
+
+	// Read new file contents from the working tree. Any missing files were deleted.
+	for _, f := range files {
+		if f.To == nil {
+			continue
+		}
+		contents, err := hackpadfs.ReadFile(fs, path.Join(worktreeDir, f.To.Name))
+		switch {
+		case errors.Is(err, hackpadfs.ErrNotExist):
+			f.To = nil
+		case err != nil:
+			return "", err
+		default:
+			f.To.Contents = string(contents)
+		}
+	}
+	files = detectRenames(files)
+
+	var patch filePatches
+	for _, f := range files {
+		if f.From != nil && f.To != nil && f.From.Name == f.To.Name && f.From.Contents == f.To.Contents {
+			continue
+		}
+		patch = append(patch, f)
+	}
+	sort.Slice(patch, func(a, b int) bool {
+		return patch[a].sortName() < patch[b].sortName()
+	})
+	return encodePatch(relativePatch(patch, relDir))
+}
+
+func encodePatch(patch fdiff.Patch) (string, error) {
+	var buf bytes.Buffer
+	err := fdiff.NewUnifiedEncoder(&buf, fdiff.DefaultContextLines).Encode(patch)
+	return buf.String(), err
+}
+
+// treeFile returns the file contents at 'name' in 'tree', or nil if it does not exist
+func treeFile(tree *object.Tree, name string) (*fileContents, error) {
+	file, err := tree.File(name)
+	if errors.Is(err, object.ErrFileNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	contents, err := file.Contents()
+	return &fileContents{Name: name, Contents: contents}, err
+}
+
+// renameSimilarityThreshold is the minimum fraction of unchanged lines to consider a deleted and added file a rename. Matches git's default.
+const renameSimilarityThreshold = 0.5
+
+// detectRenames pairs deleted files with added files when their contents are similar enough
+func detectRenames(files []*fileChange) []*fileChange {
+	var deleted, added []*fileChange
+	var result []*fileChange
+	for _, f := range files {
+		switch {
+		case f.To == nil:
+			deleted = append(deleted, f)
+		case f.From == nil:
+			added = append(added, f)
+		default:
+			result = append(result, f)
+		}
+	}
+	for _, a := range added {
+		bestIndex, bestSimilarity := -1, 0.0
+		for i, d := range deleted {
+			if d == nil {
+				continue
+			}
+			if s := similarity(d.From.Contents, a.To.Contents); s >= renameSimilarityThreshold && s > bestSimilarity {
+				bestIndex, bestSimilarity = i, s
+			}
+		}
+		if bestIndex != -1 {
+			a.From = deleted[bestIndex].From
+			deleted[bestIndex] = nil
+		}
+		result = append(result, a)
+	}
+	for _, d := range deleted {
+		if d != nil {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// similarity returns the fraction of lines unchanged between 'a' and 'b', between 0 and 1
+func similarity(a, b string) float64 {
+	var unchanged, total int
+	for _, d := range diff.Do(a, b) {
+		lines := strings.Count(d.Text, "\n")
+		if d.Type == diffmatchpatch.DiffEqual {
+			unchanged += lines
+		}
+		total += lines
+	}
+	if total == 0 {
+		return 1
+	}
+	return float64(unchanged) / float64(total)
+}
+
+type fileContents struct {
+	Name     string
+	Contents string
+}
+
+func (f *fileContents) Hash() plumbing.Hash {
+	return plumbing.ComputeHash(plumbing.BlobObject, []byte(f.Contents))
+}
+
+func (f *fileContents) Mode() filemode.FileMode {
+	return filemode.Regular
+}
+
+func (f *fileContents) Path() string {
+	return f.Name
+}
+
+// fileChange implements go-git's diff.FilePatch. A nil From is a new file, a nil To is a deleted file.
+type fileChange struct {
+	From, To *fileContents
+}
+
+func (f *fileChange) sortName() string {
+	if f.To != nil {
+		return f.To.Name
+	}
+	return f.From.Name
+}
+
+func (f *fileChange) IsBinary() bool {
+	return (f.From != nil && strings.ContainsRune(f.From.Contents, 0)) ||
+		(f.To != nil && strings.ContainsRune(f.To.Contents, 0))
+}
+
+func (f *fileChange) Files() (from, to fdiff.File) {
+	// avoid typed nil interfaces
+	if f.From != nil {
+		from = f.From
+	}
+	if f.To != nil {
+		to = f.To
+	}
+	return
+}
+
+func (f *fileChange) Chunks() []fdiff.Chunk {
+	if f.IsBinary() {
+		return nil
+	}
+	var fromContents, toContents string
+	if f.From != nil {
+		fromContents = f.From.Contents
+	}
+	if f.To != nil {
+		toContents = f.To.Contents
+	}
+	var chunks []fdiff.Chunk
+	for _, d := range diff.Do(fromContents, toContents) {
+		op := fdiff.Equal
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			op = fdiff.Add
+		case diffmatchpatch.DiffDelete:
+			op = fdiff.Delete
+		case diffmatchpatch.DiffEqual:
+		}
+		chunks = append(chunks, chunk{content: d.Text, op: op})
+	}
+	return chunks
+}
+
+type chunk struct {
+	content string
+	op      fdiff.Operation
+}
+
+func (c chunk) Content() string       { return c.content }
+func (c chunk) Type() fdiff.Operation { return c.op }
+
+// filePatches implements go-git's diff.Patch
+type filePatches []*fileChange
+
+func (p filePatches) FilePatches() []fdiff.FilePatch {
+	patches := make([]fdiff.FilePatch, 0, len(p))
+	for _, f := range p {
+		patches = append(patches, f)
+	}
+	return patches
+}
+
+func (p filePatches) Message() string {
+	return ""
+}
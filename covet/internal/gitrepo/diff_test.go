@@ -0,0 +1,399 @@
+package gitrepo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/johnstarich/go/covet/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRepo struct {
+	t        *testing.T
+	dir      string
+	repo     *git.Repository
+	worktree *git.Worktree
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	return &testRepo{t: t, dir: dir, repo: repo, worktree: worktree}
+}
+
+func (r *testRepo) WriteFile(name, contents string) {
+	r.t.Helper()
+	osPath := filepath.Join(r.dir, filepath.FromSlash(name))
+	require.NoError(r.t, os.MkdirAll(filepath.Dir(osPath), 0o700))
+	require.NoError(r.t, os.WriteFile(osPath, []byte(contents), 0o600))
+}
+
+func (r *testRepo) RemoveFile(name string) {
+	r.t.Helper()
+	require.NoError(r.t, os.Remove(filepath.Join(r.dir, filepath.FromSlash(name))))
+}
+
+func (r *testRepo) Add(name string) {
+	r.t.Helper()
+	_, err := r.worktree.Add(name)
+	require.NoError(r.t, err)
+}
+
+func (r *testRepo) Commit(message string) string {
+	r.t.Helper()
+	hash, err := r.worktree.Commit(message, &git.CommitOptions{
+		All: true,
+		Author: &object.Signature{
+			Name:  "Tester",
+			Email: "tester@example.com",
+			When:  time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	})
+	require.NoError(r.t, err)
+	return hash.String()
+}
+
+func (r *testRepo) Checkout(hash string, branch string) {
+	r.t.Helper()
+	require.NoError(r.t, r.worktree.Checkout(&git.CheckoutOptions{
+		Hash:   plumbing.NewHash(hash),
+		Branch: plumbing.NewBranchReferenceName(branch),
+		Create: true,
+	}))
+}
+
+// AddWorktree creates a linked working tree in a new directory, like 'git worktree add --detach', and copies 'files' into it
+func (r *testRepo) AddWorktree(name string, files ...string) string {
+	r.t.Helper()
+	dir := r.t.TempDir()
+	gitDir := filepath.Join(r.dir, ".git", "worktrees", name)
+	require.NoError(r.t, os.MkdirAll(gitDir, 0o700))
+	head, err := r.repo.Head()
+	require.NoError(r.t, err)
+	index, err := os.ReadFile(filepath.Join(r.dir, ".git", "index"))
+	require.NoError(r.t, err)
+	for name, contents := range map[string]string{
+		"HEAD":      head.Hash().String() + "\n",
+		"commondir": "../..\n",
+		"gitdir":    filepath.Join(dir, ".git") + "\n",
+		"index":     string(index),
+	} {
+		require.NoError(r.t, os.WriteFile(filepath.Join(gitDir, name), []byte(contents), 0o600))
+	}
+	require.NoError(r.t, os.WriteFile(filepath.Join(dir, ".git"), []byte("gitdir: "+gitDir+"\n"), 0o600))
+	for _, file := range files {
+		contents, err := os.ReadFile(filepath.Join(r.dir, filepath.FromSlash(file)))
+		require.NoError(r.t, err)
+		osPath := filepath.Join(dir, filepath.FromSlash(file))
+		require.NoError(r.t, os.MkdirAll(filepath.Dir(osPath), 0o700))
+		require.NoError(r.t, os.WriteFile(osPath, contents, 0o600))
+	}
+	return dir
+}
+
+func (r *testRepo) Diff(options Options) (string, error) {
+	r.t.Helper()
+	fs, fsPath := testhelpers.FromOSToFS(r.t, r.dir)
+	return Diff(context.Background(), fs, fsPath, options)
+}
+
+const mainGo = `package main
+
+func main() {
+	println(1)
+	println(2)
+	println(3)
+	println(4)
+}
+`
+
+func TestDiffCommits(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	repo.WriteFile("main.go", mainGo)
+	repo.Add("main.go")
+	base := repo.Commit("initial commit")
+
+	repo.WriteFile("main.go", mainGo+"\nfunc other() {}\n")
+	head := repo.Commit("add other")
+
+	diff, err := repo.Diff(Options{BaseRef: base, HeadRef: head})
+	require.NoError(t, err)
+	assert.Equal(t, `diff --git a/main.go b/main.go
+index d7763c320b4fd55f45ac40fdd3884e1859942a74..cdebeb9e7ff22bad2938462a74a69bff872e7e62 100644
+--- a/main.go
++++ b/main.go
+@@ -6,3 +6,5 @@ 	println(2)
+ 	println(3)
+ 	println(4)
+ }
++
++func other() {}
+`, diff)
+}
+
+func TestDiffCommitsRename(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	repo.WriteFile("main.go", mainGo)
+	repo.Add("main.go")
+	base := repo.Commit("initial commit")
+
+	repo.RemoveFile("main.go")
+	repo.WriteFile("cmd/main.go", mainGo+"\nfunc other() {}\n")
+	repo.Add("cmd/main.go")
+	head := repo.Commit("move main")
+
+	diff, err := repo.Diff(Options{BaseRef: base, HeadRef: head})
+	require.NoError(t, err)
+	assert.Equal(t, `diff --git a/main.go b/cmd/main.go
+rename from main.go
+rename to cmd/main.go
+index d7763c320b4fd55f45ac40fdd3884e1859942a74..cdebeb9e7ff22bad2938462a74a69bff872e7e62 100644
+--- a/main.go
++++ b/cmd/main.go
+@@ -6,3 +6,5 @@ 	println(2)
+ 	println(3)
+ 	println(4)
+ }
++
++func other() {}
+`, diff)
+}
+
+func TestDiffMergeBase(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	repo.WriteFile("main.go", mainGo)
+	repo.Add("main.go")
+	root := repo.Commit("initial commit")
+
+	repo.WriteFile("main.go", mainGo+"\nfunc other() {}\n")
+	head := repo.Commit("add other")
+
+	repo.Checkout(root, "main")
+	repo.WriteFile("README.md", "# Hello\n")
+	repo.Add("README.md")
+	base := repo.Commit("add readme")
+
+	t.Run("without merge base", func(t *testing.T) {
+		t.Parallel()
+		diff, err := repo.Diff(Options{BaseRef: base, HeadRef: head})
+		require.NoError(t, err)
+		assert.Contains(t, diff, "+func other() {}")
+		assert.Contains(t, diff, "-# Hello")
+	})
+
+	t.Run("with merge base", func(t *testing.T) {
+		t.Parallel()
+		diff, err := repo.Diff(Options{BaseRef: base, HeadRef: head, MergeBase: true})
+		require.NoError(t, err)
+		assert.Contains(t, diff, "+func other() {}")
+		assert.NotContains(t, diff, "README.md")
+	})
+}
+
+func TestDiffWorktree(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	repo.WriteFile("main.go", mainGo)
+	repo.WriteFile("deleted.go", "package main\n")
+	repo.WriteFile("renamed.go", mainGo)
+	repo.Add(".")
+	base := repo.Commit("initial commit")
+
+	repo.WriteFile("main.go", mainGo+"\nfunc committed() {}\n")
+	repo.Commit("committed change")
+
+	repo.WriteFile("main.go", mainGo+"\nfunc committed() {}\n\nfunc uncommitted() {}\n")
+	repo.RemoveFile("deleted.go")
+	repo.RemoveFile("renamed.go")
+	repo.WriteFile("pkg/renamed.go", mainGo+"\nfunc renamed() {}\n")
+	repo.Add("pkg/renamed.go")
+	repo.WriteFile("untracked.go", "package main\n")
+
+	diff, err := repo.Diff(Options{BaseRef: base})
+	require.NoError(t, err)
+	assert.Equal(t, `diff --git a/deleted.go b/deleted.go
+deleted file mode 100644
+index 06ab7d0f9a35a7d1070711496d6ca1cb892a258f..0000000000000000000000000000000000000000
+--- a/deleted.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package main
+diff --git a/main.go b/main.go
+index d7763c320b4fd55f45ac40fdd3884e1859942a74..e93baf26061345cc843f170eb28868651bfca24b 100644
+--- a/main.go
++++ b/main.go
+@@ -6,3 +6,7 @@ 	println(2)
+ 	println(3)
+ 	println(4)
+ }
++
++func committed() {}
++
++func uncommitted() {}
+diff --git a/renamed.go b/pkg/renamed.go
+rename from renamed.go
+rename to pkg/renamed.go
+index d7763c320b4fd55f45ac40fdd3884e1859942a74..1aacd9967364405cb52ead9607240afa1e484daf 100644
+--- a/renamed.go
++++ b/pkg/renamed.go
+@@ -6,3 +6,5 @@ 	println(2)
+ 	println(3)
+ 	println(4)
+ }
++
++func renamed() {}
+`, diff)
+}
+
+func TestDiffLinkedWorktree(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	repo.WriteFile("main.go", mainGo)
+	repo.Add("main.go")
+	base := repo.Commit("initial commit")
+	repo.WriteFile("main.go", mainGo+"\nfunc committed() {}\n")
+	repo.Commit("committed change")
+
+	worktreeDir := repo.AddWorktree("feature", "main.go")
+	require.NoError(t, os.WriteFile(filepath.Join(worktreeDir, "main.go"), []byte(mainGo+"\nfunc committed() {}\n\nfunc uncommitted() {}\n"), 0o600))
+	repo.WriteFile("main.go", mainGo+"\nfunc committed() {}\n\nfunc otherWorktree() {}\n")
+
+	fs, fsPath := testhelpers.FromOSToFS(t, worktreeDir)
+	diff, err := Diff(context.Background(), fs, fsPath, Options{BaseRef: base})
+	require.NoError(t, err)
+	assert.Equal(t, `diff --git a/main.go b/main.go
+index d7763c320b4fd55f45ac40fdd3884e1859942a74..e93baf26061345cc843f170eb28868651bfca24b 100644
+--- a/main.go
++++ b/main.go
+@@ -6,3 +6,7 @@ 	println(2)
+ 	println(3)
+ 	println(4)
+ }
++
++func committed() {}
++
++func uncommitted() {}
+`, diff)
+}
+
+func TestDiffSubdirectory(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	repo.WriteFile("main.go", mainGo)
+	repo.WriteFile("pkg/pkg.go", "package pkg\n")
+	repo.Add(".")
+	base := repo.Commit("initial commit")
+	repo.WriteFile("main.go", mainGo+"\nfunc other() {}\n")
+	repo.WriteFile("pkg/pkg.go", "package pkg\n\nfunc committed() {}\n")
+	head := repo.Commit("committed change")
+	repo.WriteFile("pkg/pkg.go", "package pkg\n\nfunc committed() {}\n\nfunc uncommitted() {}\n")
+
+	fs, fsPath := testhelpers.FromOSToFS(t, filepath.Join(repo.dir, "pkg"))
+	t.Run("commits", func(t *testing.T) {
+		t.Parallel()
+		diff, err := Diff(context.Background(), fs, fsPath, Options{BaseRef: base, HeadRef: head})
+		require.NoError(t, err)
+		assert.Equal(t, `diff --git a/pkg.go b/pkg.go
+index c1caffeb1fbeb31d432cbd6b3a8e3bcf5991e401..9beaf7cbea79edf5473b1dc4244a629567cc2df5 100644
+--- a/pkg.go
++++ b/pkg.go
+@@ -1 +1,3 @@
+ package pkg
++
++func committed() {}
+`, diff)
+	})
+
+	t.Run("working tree", func(t *testing.T) {
+		t.Parallel()
+		diff, err := Diff(context.Background(), fs, fsPath, Options{BaseRef: base})
+		require.NoError(t, err)
+		assert.Contains(t, diff, "+++ b/pkg.go\n")
+		assert.Contains(t, diff, "+func uncommitted() {}\n")
+		assert.NotContains(t, diff, "main.go")
+	})
+
+	resolved, err := ResolveCommit(fs, fsPath, "HEAD")
+	assert.NoError(t, err)
+	assert.Equal(t, head, resolved)
+}
+
+func TestDiffErrors(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	repo.WriteFile("main.go", mainGo)
+	repo.Add("main.go")
+	base := repo.Commit("initial commit")
+
+	_, err := repo.Diff(Options{})
+	assert.EqualError(t, err, "base ref must not be empty")
+
+	_, err = repo.Diff(Options{BaseRef: "does-not-exist"})
+	assert.EqualError(t, err, `failed to resolve git revision "does-not-exist": reference not found`)
+
+	_, err = repo.Diff(Options{BaseRef: base, HeadRef: "does-not-exist"})
+	assert.EqualError(t, err, `failed to resolve git revision "does-not-exist": reference not found`)
+
+	fs, fsPath := testhelpers.FromOSToFS(t, t.TempDir())
+	_, err = Diff(context.Background(), fs, fsPath, Options{BaseRef: base})
+	assert.ErrorContains(t, err, "failed to open git repository")
+}
+
+func TestResolveCommit(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	repo.WriteFile("main.go", mainGo)
+	repo.Add("main.go")
+	hash := repo.Commit("initial commit")
+	fs, fsPath := testhelpers.FromOSToFS(t, repo.dir)
+
+	resolved, err := ResolveCommit(fs, fsPath, "HEAD")
+	assert.NoError(t, err)
+	assert.Equal(t, hash, resolved)
+
+	_, err = ResolveCommit(fs, fsPath, "does-not-exist")
+	assert.EqualError(t, err, `failed to resolve git revision "does-not-exist": reference not found`)
+
+	emptyFS, emptyPath := testhelpers.FromOSToFS(t, t.TempDir())
+	_, err = ResolveCommit(emptyFS, emptyPath, "HEAD")
+	assert.ErrorContains(t, err, "failed to open git repository")
+}
+
+func TestState(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	repo.WriteFile("main.go", mainGo)
+	repo.Add("main.go")
+	repo.Commit("initial commit")
+	fs, fsPath := testhelpers.FromOSToFS(t, repo.dir)
+
+	state, err := State(fs, fsPath)
+	require.NoError(t, err)
+	unchanged, err := State(fs, fsPath)
+	require.NoError(t, err)
+	assert.Equal(t, state, unchanged)
+
+	repo.WriteFile("main.go", mainGo+"\nfunc other() {}\n")
+	repo.Commit("add other")
+	committed, err := State(fs, fsPath)
+	require.NoError(t, err)
+	assert.NotEqual(t, state, committed)
+
+	emptyFS, emptyPath := testhelpers.FromOSToFS(t, t.TempDir())
+	_, err = State(emptyFS, emptyPath)
+	assert.ErrorContains(t, err, "failed to open git repository")
+}
@@ -0,0 +1,176 @@
+package gitrepo
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	fdiff "github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/go-git/go-git/v5/storage/filesystem/dotgit"
+	"github.com/hack-pad/hackpadfs"
+	"github.com/pkg/errors"
+)
+
+// osPathFS is implemented by file systems which can convert absolute OS paths, like those in '.git' files, into FS paths
+type osPathFS interface {
+	FromOSPath(osPath string) (string, error)
+}
+
+// openRepo opens the git repository containing 'dir' inside 'fs', like 'git' run from 'dir'.
+// Parent directories are searched for a '.git' directory or a '.git' file pointing to one, as used by worktrees and submodules.
+// Returns the repository and the FS path to its working tree's root.
+func openRepo(fs hackpadfs.FS, dir string) (*git.Repository, string, error) {
+	repo, worktreeDir, err := openRepoDir(fs, dir)
+	return repo, worktreeDir, errors.Wrapf(err, "failed to open git repository %q", dir)
+}
+
+func openRepoDir(fs hackpadfs.FS, dir string) (*git.Repository, string, error) {
+	worktreeDir, gitDir, err := findGitDir(fs, dir)
+	if err != nil {
+		return nil, "", err
+	}
+	dotGitFS := newReadOnlyFS(fs, gitDir)
+	commonDir, err := hackpadfs.ReadFile(fs, path.Join(gitDir, "commondir"))
+	switch {
+	case err == nil:
+		commonDirPath, err := resolveGitPath(fs, gitDir, string(commonDir))
+		if err != nil {
+			return nil, "", err
+		}
+		dotGitFS = dotgit.NewRepositoryFilesystem(dotGitFS, newReadOnlyFS(fs, commonDirPath))
+	case !errors.Is(err, hackpadfs.ErrNotExist):
+		return nil, "", err
+	}
+	repo, err := git.Open(filesystem.NewStorage(dotGitFS, cache.NewObjectLRUDefault()), newReadOnlyFS(fs, worktreeDir))
+	return repo, worktreeDir, err
+}
+
+// State identifies the HEAD commit and staging area of the git repository containing 'dir' inside 'fs'.
+// The state changes when commits are made, branches are checked out, or files are staged, but not when files in the working tree are edited.
+func State(fs hackpadfs.FS, dir string) (string, error) {
+	repo, _, err := openRepo(fs, dir)
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	_, gitDir, err := findGitDir(fs, dir)
+	if err != nil {
+		return "", err
+	}
+	index, err := hackpadfs.Stat(fs, path.Join(gitDir, "index"))
+	if errors.Is(err, hackpadfs.ErrNotExist) {
+		return fmt.Sprintf("HEAD %s\n", head.Hash()), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("HEAD %s\nindex %d %d\n", head.Hash(), index.Size(), index.ModTime().UnixNano()), nil
+}
+
+// findGitDir searches 'dir' and its parents for '.git', then returns the FS paths to the working tree's root and its git directory
+func findGitDir(fs hackpadfs.FS, dir string) (worktreeDir, gitDir string, err error) {
+	for {
+		dotGit := path.Join(dir, git.GitDirName)
+		info, err := hackpadfs.Stat(fs, dotGit)
+		switch {
+		case err == nil && info.IsDir():
+			return dir, dotGit, nil
+		case err == nil:
+			gitDir, err := readGitDirFile(fs, dotGit)
+			return dir, gitDir, err
+		case !errors.Is(err, hackpadfs.ErrNotExist):
+			return "", "", err
+		}
+		parent := path.Dir(dir)
+		if parent == dir {
+			return "", "", git.ErrRepositoryNotExists
+		}
+		dir = parent
+	}
+}
+
+// readGitDirFile returns the FS path to the git directory in a '.git' file, like "gitdir: ../.git/modules/sub"
+func readGitDirFile(fs hackpadfs.FS, dotGitFile string) (string, error) {
+	contents, err := hackpadfs.ReadFile(fs, dotGitFile)
+	if err != nil {
+		return "", err
+	}
+	const prefix = "gitdir: "
+	line := strings.SplitN(string(contents), "\n", 2)[0]
+	if !strings.HasPrefix(line, prefix) {
+		return "", errors.Errorf("%s file has no %q prefix", dotGitFile, prefix)
+	}
+	return resolveGitPath(fs, path.Dir(dotGitFile), strings.TrimPrefix(line, prefix))
+}
+
+// resolveGitPath returns the FS path for 'p' from a git metadata file in 'baseDir'. Relative paths start from 'baseDir'.
+func resolveGitPath(fs hackpadfs.FS, baseDir, p string) (string, error) {
+	p = strings.TrimSpace(p)
+	if !filepath.IsAbs(p) {
+		return path.Join(baseDir, filepath.ToSlash(p)), nil
+	}
+	osFS, ok := fs.(osPathFS)
+	if !ok {
+		return "", errors.Errorf("unsupported absolute git directory path %q", p)
+	}
+	return osFS.FromOSPath(p)
+}
+
+// relativePatch returns the files in 'patch' inside 'dir', with paths relative to 'dir', like 'git diff --relative'.
+// 'dir' is relative to the working tree's root.
+func relativePatch(patch fdiff.Patch, dir string) fdiff.Patch {
+	if dir == "." {
+		return patch
+	}
+	return relativeFilePatches{Patch: patch, prefix: dir + "/"}
+}
+
+type relativeFilePatches struct {
+	fdiff.Patch
+	prefix string
+}
+
+func (p relativeFilePatches) FilePatches() []fdiff.FilePatch {
+	var patches []fdiff.FilePatch
+	for _, f := range p.Patch.FilePatches() {
+		from, to := f.Files()
+		if (from == nil || !strings.HasPrefix(from.Path(), p.prefix)) && (to == nil || !strings.HasPrefix(to.Path(), p.prefix)) {
+			continue
+		}
+		patches = append(patches, relativeFilePatch{FilePatch: f, prefix: p.prefix})
+	}
+	return patches
+}
+
+type relativeFilePatch struct {
+	fdiff.FilePatch
+	prefix string
+}
+
+func (f relativeFilePatch) Files() (from, to fdiff.File) {
+	from, to = f.FilePatch.Files()
+	if from != nil {
+		from = relativeFile{File: from, prefix: f.prefix}
+	}
+	if to != nil {
+		to = relativeFile{File: to, prefix: f.prefix}
+	}
+	return
+}
+
+// relativeFile trims 'prefix' from the path of a file. Files outside 'prefix', like the source of a rename into it, keep their full path.
+type relativeFile struct {
+	fdiff.File
+	prefix string
+}
+
+func (f relativeFile) Path() string {
+	return strings.TrimPrefix(f.File.Path(), f.prefix)
+}
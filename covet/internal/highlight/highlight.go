@@ -0,0 +1,110 @@
+// Package highlight renders source code as escaped HTML, with syntax highlighting for Go.
+package highlight
+
+import (
+	"go/scanner"
+	"go/token"
+	"html/template"
+	"path"
+	"strings"
+)
+
+// Token classes used as HTML class names. Style them with CSS.
+const (
+	ClassComment = "comment"
+	ClassKeyword = "keyword"
+	ClassLiteral = "literal"
+	ClassString  = "string"
+)
+
+// Lines returns escaped HTML for each line of the source file 'name'.
+// Go files are syntax-highlighted with GoLines, other languages are plain text.
+func Lines(name string, src []byte) []template.HTML {
+	if path.Ext(name) == ".go" {
+		return GoLines(src)
+	}
+	var html strings.Builder
+	writeClass(&html, "", string(src))
+	return splitLines(html.String())
+}
+
+// GoLines returns escaped and syntax-highlighted HTML for each line of Go source code 'src'.
+// Tokens spanning multiple lines, like raw strings and block comments, are split so each line is valid HTML on its own.
+func GoLines(src []byte) []template.HTML {
+	fileSet := token.NewFileSet()
+	file := fileSet.AddFile("", fileSet.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(file, src, nil, scanner.ScanComments) // ignore errors, display invalid code as-is
+
+	var html strings.Builder
+	lastOffset := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.SEMICOLON && lit == "\n" {
+			continue // automatically inserted semicolon
+		}
+		offset := file.Offset(pos)
+		text := lit
+		if text == "" {
+			text = tok.String()
+		}
+		if offset < lastOffset || offset+len(text) > len(src) {
+			continue
+		}
+		writeClass(&html, "", string(src[lastOffset:offset]))
+		writeClass(&html, tokenClass(tok), string(src[offset:offset+len(text)]))
+		lastOffset = offset + len(text)
+	}
+	writeClass(&html, "", string(src[lastOffset:]))
+	return splitLines(html.String())
+}
+
+// splitLines splits escaped HTML into lines
+func splitLines(html string) []template.HTML {
+	lines := strings.Split(strings.TrimSuffix(html, "\n"), "\n")
+	htmlLines := make([]template.HTML, len(lines))
+	for i, line := range lines {
+		htmlLines[i] = template.HTML(line) //nolint:gosec // Contents are escaped in writeClass
+	}
+	return htmlLines
+}
+
+func tokenClass(tok token.Token) string {
+	switch {
+	case tok == token.COMMENT:
+		return ClassComment
+	case tok == token.STRING || tok == token.CHAR:
+		return ClassString
+	case tok.IsKeyword():
+		return ClassKeyword
+	case tok.IsLiteral() && tok != token.IDENT:
+		return ClassLiteral
+	default:
+		return ""
+	}
+}
+
+// writeClass writes escaped 'text' wrapped in a span with the given class. Each line is wrapped separately.
+func writeClass(html *strings.Builder, class, text string) {
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			html.WriteRune('\n')
+		}
+		if line == "" {
+			continue
+		}
+		escaped := template.HTMLEscapeString(line)
+		if class == "" {
+			html.WriteString(escaped)
+			continue
+		}
+		html.WriteString(`<span class="`)
+		html.WriteString(class)
+		html.WriteString(`">`)
+		html.WriteString(escaped)
+		html.WriteString(`</span>`)
+	}
+}
@@ -0,0 +1,98 @@
+package highlight
+
+import (
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoLines(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		description string
+		src         string
+		expect      []template.HTML
+	}{
+		{
+			description: "empty",
+			src:         "",
+			expect:      []template.HTML{""},
+		},
+		{
+			description: "keywords, literals, and comments",
+			src: `package main
+
+// main runs
+func main() {
+	x := 1 + 'a'
+	println("hi", x) // <done>
+}
+`,
+			expect: []template.HTML{
+				`<span class="keyword">package</span> main`,
+				``,
+				`<span class="comment">// main runs</span>`,
+				`<span class="keyword">func</span> main() {`,
+				`	x := <span class="literal">1</span> + <span class="string">&#39;a&#39;</span>`,
+				`	println(<span class="string">&#34;hi&#34;</span>, x) <span class="comment">// &lt;done&gt;</span>`,
+				`}`,
+			},
+		},
+		{
+			description: "multi-line tokens",
+			src:         "var s = `a\nb`\n/* c\nd */\n",
+			expect: []template.HTML{
+				"<span class=\"keyword\">var</span> s = <span class=\"string\">`a</span>",
+				"<span class=\"string\">b`</span>",
+				`<span class="comment">/* c</span>`,
+				`<span class="comment">d */</span>`,
+			},
+		},
+		{
+			description: "invalid code",
+			src:         "not go ` <code>",
+			expect: []template.HTML{
+				"not <span class=\"keyword\">go</span> <span class=\"string\">` &lt;code&gt;</span>",
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expect, GoLines([]byte(tc.src)))
+		})
+	}
+}
+
+func TestLines(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		description string
+		name        string
+		src         string
+		expect      []template.HTML
+	}{
+		{
+			description: "go file",
+			name:        "main.go",
+			src:         "package main\n",
+			expect:      []template.HTML{`<span class="keyword">package</span> main`},
+		},
+		{
+			description: "other language",
+			name:        "app.js",
+			src:         "if (a < b) {\n  return 'go'\n}\n",
+			expect: []template.HTML{
+				`if (a &lt; b) {`,
+				`  return &#39;go&#39;`,
+				`}`,
+			},
+		},
+	} {
+		tc := tc // enable parallel sub-tests
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expect, Lines(tc.name, []byte(tc.src)))
+		})
+	}
+}
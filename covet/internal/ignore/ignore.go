@@ -0,0 +1,145 @@
+// Package ignore finds files and lines to exclude from diff coverage.
+package ignore
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"strings"
+
+	"github.com/johnstarich/go/covet/internal/span"
+)
+
+// Marker is the comment text which excludes lines from diff coverage, like "// covet:ignore".
+// Any text after the marker and a space is ignored, so it may include a reason.
+const Marker = "covet:ignore"
+
+// ValidatePattern returns an error if 'pattern' is malformed
+func ValidatePattern(pattern string) error {
+	for _, component := range strings.Split(pattern, "/") {
+		if _, err := path.Match(component, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MatchPattern reports whether 'name' matches the slash-separated path 'pattern'.
+// Patterns use path.Match syntax for each path component, plus '**' to match zero or more directories.
+// Patterns without a slash match the base name of 'name' in any directory, like "*.pb.go".
+func MatchPattern(pattern, name string) (bool, error) {
+	if err := ValidatePattern(pattern); err != nil {
+		return false, err
+	}
+	if !strings.Contains(pattern, "/") {
+		return path.Match(pattern, path.Base(name))
+	}
+	return MatchPath(pattern, name)
+}
+
+// MatchPath reports whether 'name' matches the slash-separated path 'pattern', comparing each path component.
+// Unlike MatchPattern, patterns without a slash only match names without a slash.
+func MatchPath(pattern, name string) (bool, error) {
+	if err := ValidatePattern(pattern); err != nil {
+		return false, err
+	}
+	return matchComponents(strings.Split(pattern, "/"), strings.Split(name, "/")), nil
+}
+
+func matchComponents(patterns, names []string) bool {
+	for len(patterns) > 0 {
+		if patterns[0] == "**" {
+			for i := 0; i <= len(names); i++ {
+				if matchComponents(patterns[1:], names[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(names) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(patterns[0], names[0]); !matched { // pattern is validated in MatchPath()
+			return false
+		}
+		patterns, names = patterns[1:], names[1:]
+	}
+	return len(names) == 0
+}
+
+// IsGenerated reports whether Go source 'src' has the standard generated code header, like "// Code generated by protoc-gen-go. DO NOT EDIT."
+//
+// See https://go.dev/s/generatedcode
+func IsGenerated(src []byte) bool {
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, parser.PackageClauseOnly|parser.ParseComments)
+	if err != nil {
+		return false
+	}
+	return ast.IsGenerated(file)
+}
+
+// Lines returns the spans of lines in Go source 'src' excluded by a Marker comment.
+//
+// A marker at the end of a line excludes that line. A marker on its own line excludes the next line.
+// If the excluded line begins a multi-line statement or declaration, like an 'if' block or a function, the whole statement is excluded.
+// Invalid Go source only excludes lines containing a marker.
+func Lines(src []byte) []span.Span {
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, "", src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return markedLines(src)
+	}
+
+	lastLines := make(map[int]int) // maps a node's first line to the last line of the largest node starting there
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node.(type) {
+		case nil, *ast.File, *ast.Comment, *ast.CommentGroup:
+		default:
+			start, end := fileSet.Position(node.Pos()).Line, fileSet.Position(node.End()).Line
+			if end > lastLines[start] {
+				lastLines[start] = end
+			}
+		}
+		return true
+	})
+
+	var spans []span.Span
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			if !isMarker(comment.Text) {
+				continue
+			}
+			position := fileSet.Position(comment.Pos())
+			line := position.Line
+			lineStart := position.Offset - (position.Column - 1)
+			if len(bytes.TrimSpace(src[lineStart:position.Offset])) == 0 {
+				line = fileSet.Position(group.End()).Line + 1 // marker on its own line applies to the next line
+			}
+			lastLine := line
+			if end, ok := lastLines[line]; ok {
+				lastLine = end
+			}
+			spans = append(spans, span.Span{Start: uint(line), End: uint(lastLine) + 1})
+		}
+	}
+	return span.Union(spans)
+}
+
+func markedLines(src []byte) []span.Span {
+	var spans []span.Span
+	for i, line := range bytes.Split(src, []byte("\n")) {
+		if index := bytes.Index(line, []byte("//")); index != -1 && isMarker(string(line[index:])) {
+			lineNumber := uint(i) + 1
+			spans = append(spans, span.Span{Start: lineNumber, End: lineNumber + 1})
+		}
+	}
+	return spans
+}
+
+func isMarker(comment string) bool {
+	text := strings.TrimPrefix(comment, "//")
+	text = strings.TrimSpace(text)
+	return text == Marker || strings.HasPrefix(text, Marker+" ")
+}
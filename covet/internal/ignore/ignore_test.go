@@ -0,0 +1,143 @@
+package ignore
+
+import (
+	"testing"
+
+	"github.com/johnstarich/go/covet/internal/span"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPattern(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		pattern   string
+		name      string
+		expect    bool
+		expectErr string
+	}{
+		{pattern: "*.pb.go", name: "api/v1/service.pb.go", expect: true},
+		{pattern: "*.pb.go", name: "api/v1/service.go", expect: false},
+		{pattern: "vendor/**", name: "vendor/github.com/org/repo/main.go", expect: true},
+		{pattern: "vendor/**", name: "internal/vendor/main.go", expect: false},
+		{pattern: "**/zz_generated*.go", name: "zz_generated.deepcopy.go", expect: true},
+		{pattern: "**/zz_generated*.go", name: "pkg/apis/zz_generated.deepcopy.go", expect: true},
+		{pattern: "pkg/**/mocks/*.go", name: "pkg/a/b/mocks/mock.go", expect: true},
+		{pattern: "pkg/*/mocks/*.go", name: "pkg/a/b/mocks/mock.go", expect: false},
+		{pattern: "pkg/[/*.go", name: "pkg/a.go", expectErr: "syntax error in pattern"},
+		{pattern: "pkg/a.go/[", name: "pkg/a.go", expectErr: "syntax error in pattern"},
+	} {
+		t.Run(tc.pattern+" "+tc.name, func(t *testing.T) {
+			t.Parallel()
+			matched, err := MatchPattern(tc.pattern, tc.name)
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				assert.EqualError(t, ValidatePattern(tc.pattern), tc.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, ValidatePattern(tc.pattern))
+			assert.Equal(t, tc.expect, matched)
+		})
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	t.Parallel()
+	matched, err := MatchPath("*.go", "main.go")
+	assert.NoError(t, err)
+	assert.True(t, matched)
+
+	matched, err = MatchPath("*.go", "cmd/main.go")
+	assert.NoError(t, err)
+	assert.False(t, matched)
+
+	_, err = MatchPath("[", "main.go")
+	assert.EqualError(t, err, "syntax error in pattern")
+}
+
+func TestIsGenerated(t *testing.T) {
+	t.Parallel()
+	assert.True(t, IsGenerated([]byte("// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage api\n")))
+	assert.False(t, IsGenerated([]byte("// Package api is not generated.\npackage api\n")))
+	assert.False(t, IsGenerated([]byte("package api\n\n// Code generated by hand. DO NOT EDIT.\n")))
+	assert.False(t, IsGenerated([]byte("not go")))
+}
+
+func TestLines(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		description string
+		src         string
+		expect      []span.Span
+	}{
+		{
+			description: "no markers",
+			src: `package main
+
+func main() {}
+`,
+		},
+		{
+			description: "trailing marker",
+			src: `package main
+
+func main() {
+	println(1) // covet:ignore
+	println(2)
+}
+`,
+			expect: []span.Span{{Start: 4, End: 5}},
+		},
+		{
+			description: "trailing marker on block",
+			src: `package main
+
+func main() {
+	if err := run(); err != nil { // covet:ignore unreachable
+		panic(err)
+	}
+	println(2)
+}
+`,
+			expect: []span.Span{{Start: 4, End: 7}},
+		},
+		{
+			description: "marker on previous line",
+			src: `package main
+
+// covet:ignore
+// Some docs
+func main() {
+	println(1)
+}
+
+func run() {}
+`,
+			expect: []span.Span{{Start: 5, End: 8}},
+		},
+		{
+			description: "not a marker",
+			src: `package main
+
+func main() {
+	println(1) // covet:ignored
+	println(2) // see covet:ignore
+}
+`,
+		},
+		{
+			description: "invalid Go",
+			src: `package main
+
+func main() {
+	println(1) // covet:ignore
+`,
+			expect: []span.Span{{Start: 4, End: 5}},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expect, Lines([]byte(tc.src)))
+		})
+	}
+}
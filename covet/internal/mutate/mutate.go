@@ -0,0 +1,156 @@
+// Package mutate finds simple mutations of Go source code, like flipping comparisons or negating conditions.
+package mutate
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+
+	"github.com/johnstarich/go/covet/internal/span"
+)
+
+// Mutation is a replacement of source bytes [Start, End) with Replacement
+type Mutation struct {
+	// Line is the line number of the mutated code
+	Line uint
+	// Description explains the mutation, like "flip '<' to '>='"
+	Description string
+	Start, End  int
+	Replacement string
+}
+
+//nolint:gochecknoglobals // Read-only lookup table
+var flippedOperators = map[token.Token]token.Token{
+	token.EQL:  token.NEQ,
+	token.NEQ:  token.EQL,
+	token.LSS:  token.GEQ,
+	token.GEQ:  token.LSS,
+	token.GTR:  token.LEQ,
+	token.LEQ:  token.GTR,
+	token.LAND: token.LOR,
+	token.LOR:  token.LAND,
+}
+
+// Find returns mutations of Go source code 'src' which start on any line in 'lines', ordered by position.
+// Mutations flip comparison and logical operators, negate 'if' and 'for' conditions, and replace returned literals.
+func Find(src []byte, lines []span.Span) ([]Mutation, error) {
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	tokenFile := fileSet.File(file.Pos())
+	offset := func(pos token.Pos) int { return tokenFile.Offset(pos) }
+	inLines := func(pos token.Pos) (uint, bool) {
+		line := uint(tokenFile.Line(pos))
+		for _, s := range lines {
+			if s.Start <= line && line < s.End {
+				return line, true
+			}
+		}
+		return 0, false
+	}
+
+	var mutations []Mutation
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.BinaryExpr:
+			flipped, ok := flippedOperators[node.Op]
+			if line, inDiff := inLines(node.OpPos); ok && inDiff {
+				mutations = append(mutations, Mutation{
+					Line:        line,
+					Description: "flip '" + node.Op.String() + "' to '" + flipped.String() + "'",
+					Start:       offset(node.OpPos),
+					End:         offset(node.OpPos) + len(node.Op.String()),
+					Replacement: flipped.String(),
+				})
+			}
+		case *ast.IfStmt:
+			mutations = appendNegatedCondition(mutations, src, node.Cond, "if", inLines, offset)
+		case *ast.ForStmt:
+			mutations = appendNegatedCondition(mutations, src, node.Cond, "for", inLines, offset)
+		case *ast.ReturnStmt:
+			for _, result := range node.Results {
+				if line, inDiff := inLines(result.Pos()); inDiff {
+					if replacement, description, ok := replaceLiteral(result); ok {
+						mutations = append(mutations, Mutation{
+							Line:        line,
+							Description: description,
+							Start:       offset(result.Pos()),
+							End:         offset(result.End()),
+							Replacement: replacement,
+						})
+					}
+				}
+			}
+		}
+		return true
+	})
+	sort.SliceStable(mutations, func(a, b int) bool {
+		return mutations[a].Start < mutations[b].Start
+	})
+	return mutations, nil
+}
+
+func appendNegatedCondition(
+	mutations []Mutation,
+	src []byte,
+	cond ast.Expr,
+	statement string,
+	inLines func(token.Pos) (uint, bool),
+	offset func(token.Pos) int,
+) []Mutation {
+	if cond == nil {
+		return mutations
+	}
+	line, inDiff := inLines(cond.Pos())
+	if !inDiff {
+		return mutations
+	}
+	start, end := offset(cond.Pos()), offset(cond.End())
+	return append(mutations, Mutation{
+		Line:        line,
+		Description: "negate '" + statement + "' condition",
+		Start:       start,
+		End:         end,
+		Replacement: "!(" + string(src[start:end]) + ")",
+	})
+}
+
+// replaceLiteral returns a replacement for a returned literal, like 'false' for 'true' or '0' for '42'
+func replaceLiteral(expr ast.Expr) (replacement, description string, ok bool) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		switch expr.Name {
+		case "true":
+			return "false", "return 'false' instead of 'true'", true
+		case "false":
+			return "true", "return 'true' instead of 'false'", true
+		}
+	case *ast.BasicLit:
+		switch expr.Kind {
+		case token.INT:
+			replacement = "0"
+			if value, err := strconv.ParseInt(expr.Value, 0, 64); err == nil && value == 0 {
+				replacement = "1"
+			}
+			return replacement, "return '" + replacement + "' instead of '" + expr.Value + "'", true
+		case token.STRING:
+			if value, err := strconv.Unquote(expr.Value); err == nil && value != "" {
+				return `""`, `return '""' instead of '` + expr.Value + "'", true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// Apply returns a copy of 'src' with mutation 'm' applied
+func Apply(src []byte, m Mutation) []byte {
+	mutated := make([]byte, 0, len(src)-(m.End-m.Start)+len(m.Replacement))
+	mutated = append(mutated, src[:m.Start]...)
+	mutated = append(mutated, m.Replacement...)
+	mutated = append(mutated, src[m.End:]...)
+	return mutated
+}
@@ -0,0 +1,108 @@
+package mutate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/johnstarich/go/covet/internal/span"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+	src := []byte(`package main
+
+func check(a, b int) bool {
+	if a < b && b != 0 {
+		return true
+	}
+	for i := 0; i <= a; i++ {
+	}
+	return false
+}
+
+func name() (string, int) {
+	return "name", 42
+}
+
+func zero() int {
+	return 0
+}
+`)
+	for _, tc := range []struct {
+		description string
+		lines       []span.Span
+		expect      []string
+	}{
+		{
+			description: "no lines",
+			lines:       nil,
+			expect:      nil,
+		},
+		{
+			description: "conditions",
+			lines:       []span.Span{{Start: 4, End: 5}},
+			expect: []string{
+				"negate 'if' condition: if !(a < b && b != 0) {",
+				"flip '<' to '>=': if a >= b && b != 0 {",
+				"flip '&&' to '||': if a < b || b != 0 {",
+				"flip '!=' to '==': if a < b && b == 0 {",
+			},
+		},
+		{
+			description: "loops and returned bools",
+			lines:       []span.Span{{Start: 5, End: 10}},
+			expect: []string{
+				"return 'false' instead of 'true': return false",
+				"negate 'for' condition: for i := 0; !(i <= a); i++ {",
+				"flip '<=' to '>': for i := 0; i > a; i++ {",
+				"return 'true' instead of 'false': return true",
+			},
+		},
+		{
+			description: "returned literals",
+			lines:       []span.Span{{Start: 13, End: 14}, {Start: 17, End: 18}},
+			expect: []string{
+				`return '""' instead of '"name"': return "", 42`,
+				`return '0' instead of '42': return "name", 0`,
+				"return '1' instead of '0': return 1",
+			},
+		},
+	} {
+		tc := tc // enable parallel sub-tests
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			mutations, err := Find(src, tc.lines)
+			require.NoError(t, err)
+			var results []string
+			for _, m := range mutations {
+				mutated := Apply(src, m)
+				results = append(results, m.Description+": "+lineOf(mutated, m.Line))
+			}
+			assert.Equal(t, tc.expect, results)
+		})
+	}
+}
+
+func TestFindInvalidGo(t *testing.T) {
+	t.Parallel()
+	_, err := Find([]byte(`not go`), nil)
+	assert.Error(t, err)
+}
+
+func lineOf(src []byte, lineNumber uint) string {
+	var line uint = 1
+	start := 0
+	for i, b := range src {
+		if b != '\n' {
+			continue
+		}
+		if line == lineNumber {
+			return strings.TrimLeft(string(src[start:i]), "\t")
+		}
+		line++
+		start = i + 1
+	}
+	return ""
+}
@@ -3,6 +3,7 @@ package span
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/johnstarich/go/covet/internal/minmax"
 )
@@ -47,3 +48,51 @@ func (s Span) Merge(other Span) (Span, bool) {
 		End:   minmax.Max(s.End, other.End),
 	}, true
 }
+
+// Union returns the smallest set of sorted, non-overlapping Spans containing every index in 'spans'.
+// Adjacent spans are combined.
+func Union(spans []Span) []Span {
+	if len(spans) == 0 {
+		return nil
+	}
+	sorted := append([]Span(nil), spans...)
+	sort.Slice(sorted, func(a, b int) bool {
+		return sorted[a].Start < sorted[b].Start
+	})
+	union := []Span{sorted[0]}
+	for _, s := range sorted[1:] {
+		last := &union[len(union)-1]
+		if s.Start <= last.End {
+			last.End = minmax.Max(last.End, s.End)
+		} else {
+			union = append(union, s)
+		}
+	}
+	return union
+}
+
+// Subtract returns the parts of 'spans' which do not intersect with any Span in 'remove'.
+func Subtract(spans, remove []Span) []Span {
+	var result []Span
+	for _, s := range spans {
+		remaining := []Span{s}
+		for _, r := range remove {
+			var next []Span
+			for _, rem := range remaining {
+				if _, intersects := rem.Intersection(r); !intersects {
+					next = append(next, rem)
+					continue
+				}
+				if rem.Start < r.Start {
+					next = append(next, Span{Start: rem.Start, End: r.Start})
+				}
+				if r.End < rem.End {
+					next = append(next, Span{Start: r.End, End: rem.End})
+				}
+			}
+			remaining = next
+		}
+		result = append(result, remaining...)
+	}
+	return result
+}
@@ -108,3 +108,93 @@ func TestMerge(t *testing.T) {
 		})
 	}
 }
+
+func TestUnion(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		description string
+		spans       []Span
+		expect      []Span
+	}{
+		{
+			description: "no spans",
+			spans:       nil,
+			expect:      nil,
+		},
+		{
+			description: "duplicate spans",
+			spans: []Span{
+				{Start: 1, End: 2},
+				{Start: 1, End: 2},
+			},
+			expect: []Span{
+				{Start: 1, End: 2},
+			},
+		},
+		{
+			description: "unsorted overlapping and adjacent spans",
+			spans: []Span{
+				{Start: 5, End: 8},
+				{Start: 1, End: 3},
+				{Start: 3, End: 4},
+				{Start: 6, End: 7},
+				{Start: 10, End: 11},
+			},
+			expect: []Span{
+				{Start: 1, End: 4},
+				{Start: 5, End: 8},
+				{Start: 10, End: 11},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expect, Union(tc.spans))
+		})
+	}
+}
+
+func TestSubtract(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		description   string
+		spans, remove []Span
+		expect        []Span
+	}{
+		{
+			description: "nothing to remove",
+			spans:       []Span{{Start: 1, End: 3}},
+			expect:      []Span{{Start: 1, End: 3}},
+		},
+		{
+			description: "remove all",
+			spans:       []Span{{Start: 1, End: 3}},
+			remove:      []Span{{Start: 0, End: 5}},
+			expect:      nil,
+		},
+		{
+			description: "remove middle",
+			spans:       []Span{{Start: 1, End: 10}},
+			remove:      []Span{{Start: 3, End: 5}, {Start: 6, End: 7}},
+			expect: []Span{
+				{Start: 1, End: 3},
+				{Start: 5, End: 6},
+				{Start: 7, End: 10},
+			},
+		},
+		{
+			description: "remove edges",
+			spans:       []Span{{Start: 1, End: 10}, {Start: 12, End: 14}},
+			remove:      []Span{{Start: 0, End: 2}, {Start: 9, End: 13}},
+			expect: []Span{
+				{Start: 2, End: 9},
+				{Start: 13, End: 14},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expect, Subtract(tc.spans, tc.remove))
+		})
+	}
+}
@@ -17,8 +17,37 @@ type Format int
 const (
 	FormatColorTerminal Format = iota
 	FormatMarkdown
+	FormatJSON
+	FormatSARIF
+	FormatHTML
 )
 
+//nolint:gochecknoglobals // Read-only lookup table
+var formatNames = map[Format]string{
+	FormatColorTerminal: "terminal",
+	FormatMarkdown:      "markdown",
+	FormatJSON:          "json",
+	FormatSARIF:         "sarif",
+	FormatHTML:          "html",
+}
+
+// ParseFormat returns the Format for the given name. e.g. "terminal", "markdown", "json", "sarif", or "html"
+func ParseFormat(name string) (Format, error) {
+	for format, formatName := range formatNames {
+		if name == formatName {
+			return format, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported format %q, must be one of: terminal, markdown, json, sarif, html", name)
+}
+
+func (f Format) String() string {
+	if name, ok := formatNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
 // Colorize returns 's' and optionally wraps with color 'c' according to the format's rules
 func (f Format) Colorize(c *color.Color, s string) string {
 	if f == FormatColorTerminal {
@@ -9,6 +9,7 @@ import (
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/johnstarich/go/covet/internal/coverfile"
 	"github.com/johnstarich/go/covet/internal/coverstatus"
+	"github.com/johnstarich/go/covet/internal/funcs"
 )
 
 // New generates a new summary report in the given format
@@ -47,6 +48,373 @@ func New(uncoveredFiles []coverfile.File, targetCoverage uint, format Format) st
 	return sb.String()
 }
 
+// NewFunctions generates a table of functions with uncovered lines in the given format.
+// Returns an empty string if there are no functions.
+func NewFunctions(functions []coverfile.Function, format Format) string {
+	if len(functions) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Functions with uncovered lines:\n")
+	tbl := table.NewWriter()
+	const coverageColumnIndex = 2
+	tbl.SetColumnConfigs([]table.ColumnConfig{
+		{Number: coverageColumnIndex, Align: text.AlignCenter},
+	})
+	tbl.SuppressEmptyColumns()
+	bold := boldColor()
+	tbl.AppendHeader(table.Row{
+		"",
+		format.Colorize(bold, "Lines"),
+		format.Colorize(bold, "Coverage"),
+		format.Colorize(bold, "Function"),
+		format.Colorize(bold, "File"),
+	})
+	for _, f := range functions {
+		percent := FunctionCoverage(f)
+		status := coverstatus.New(percent)
+		tbl.AppendRow(table.Row{
+			format.StatusIcon(status),
+			format.ColorizeStatus(status, format.Monospace(formatFraction(f.Covered, f.Uncovered+f.Covered))),
+			format.ColorizeStatus(status, format.Monospace(FormatPercent(percent)+" "+formatGraph(percent, format))),
+			format.Monospace(funcs.FullName(f.Receiver, f.Name)),
+			f.File,
+		})
+	}
+	sb.WriteString(format.FormatTable(tbl))
+	sb.WriteRune('\n')
+	return sb.String()
+}
+
+// Group is a named set of files and their combined diff coverage, like a package or a code owner's files
+type Group struct {
+	Name      string
+	Covered   uint
+	Uncovered uint
+}
+
+// NewGroups generates a table of diff coverage for each group in the given format.
+// The 'title' introduces the table, and 'column' names the group column, like "Owner".
+// Returns an empty string if there are no groups.
+func NewGroups(title, column string, groups []Group, format Format) string {
+	if len(groups) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(title)
+	sb.WriteString("\n")
+	tbl := table.NewWriter()
+	const coverageColumnIndex = 2
+	tbl.SetColumnConfigs([]table.ColumnConfig{
+		{Number: coverageColumnIndex, Align: text.AlignCenter},
+	})
+	tbl.SuppressEmptyColumns()
+	bold := boldColor()
+	tbl.AppendHeader(table.Row{
+		"",
+		format.Colorize(bold, "Lines"),
+		format.Colorize(bold, "Coverage"),
+		format.Colorize(bold, column),
+	})
+	for _, g := range groups {
+		percent := float64(g.Covered) / float64(g.Covered+g.Uncovered)
+		status := coverstatus.New(percent)
+		tbl.AppendRow(table.Row{
+			format.StatusIcon(status),
+			format.ColorizeStatus(status, format.Monospace(formatFraction(g.Covered, g.Uncovered+g.Covered))),
+			format.ColorizeStatus(status, format.Monospace(FormatPercent(percent)+" "+formatGraph(percent, format))),
+			g.Name,
+		})
+	}
+	sb.WriteString(format.FormatTable(tbl))
+	sb.WriteRune('\n')
+	return sb.String()
+}
+
+// Package is a package's diff coverage and its total coverage, including lines outside the diff
+type Package struct {
+	Name      string
+	Covered   uint
+	Uncovered uint
+	// Total is the package's total coverage. Nil if the coverage profiles have no lines in this package.
+	Total *float64
+}
+
+// NewPackages generates a table comparing diff coverage to total coverage for each package in the given format.
+// Returns an empty string if there are no packages.
+func NewPackages(packages []Package, format Format) string {
+	if len(packages) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Total coverage of packages in the diff:\n")
+	tbl := table.NewWriter()
+	const (
+		diffColumnIndex  = 2
+		totalColumnIndex = 3
+	)
+	tbl.SetColumnConfigs([]table.ColumnConfig{
+		{Number: diffColumnIndex, Align: text.AlignCenter},
+		{Number: totalColumnIndex, Align: text.AlignCenter},
+	})
+	tbl.SuppressEmptyColumns()
+	bold := boldColor()
+	tbl.AppendHeader(table.Row{
+		"",
+		format.Colorize(bold, "Diff"),
+		format.Colorize(bold, "Total"),
+		format.Colorize(bold, "Package"),
+	})
+	for _, p := range packages {
+		percent := float64(p.Covered) / float64(p.Covered+p.Uncovered)
+		status := coverstatus.New(percent)
+		total := format.Monospace("n/a")
+		if p.Total != nil {
+			total = format.ColorizeStatus(coverstatus.New(*p.Total), format.Monospace(FormatPercent(*p.Total)))
+		}
+		tbl.AppendRow(table.Row{
+			format.StatusIcon(status),
+			format.ColorizeStatus(status, format.Monospace(FormatPercent(percent))),
+			total,
+			p.Name,
+		})
+	}
+	sb.WriteString(format.FormatTable(tbl))
+	sb.WriteRune('\n')
+	return sb.String()
+}
+
+// Label is the diff coverage from coverage profiles with the same label, like "unit" or "integration"
+type Label struct {
+	Name        string
+	Covered     uint
+	Uncovered   uint
+	OnlyCovered uint
+}
+
+// NewLabels generates a table of diff coverage for each coverage profile label in the given format.
+// The "Only" column counts lines covered by that label and no others.
+// Returns an empty string if there are no labels.
+func NewLabels(labels []Label, format Format) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Diff coverage by label:\n")
+	tbl := table.NewWriter()
+	const coverageColumnIndex = 2
+	tbl.SetColumnConfigs([]table.ColumnConfig{
+		{Number: coverageColumnIndex, Align: text.AlignCenter},
+	})
+	tbl.SuppressEmptyColumns()
+	bold := boldColor()
+	tbl.AppendHeader(table.Row{
+		"",
+		format.Colorize(bold, "Lines"),
+		format.Colorize(bold, "Coverage"),
+		format.Colorize(bold, "Only"),
+		format.Colorize(bold, "Label"),
+	})
+	for _, l := range labels {
+		percent := float64(l.Covered) / float64(l.Covered+l.Uncovered)
+		status := coverstatus.New(percent)
+		tbl.AppendRow(table.Row{
+			format.StatusIcon(status),
+			format.ColorizeStatus(status, format.Monospace(formatFraction(l.Covered, l.Uncovered+l.Covered))),
+			format.ColorizeStatus(status, format.Monospace(FormatPercent(percent)+" "+formatGraph(percent, format))),
+			format.Monospace(fmt.Sprintf("%d", l.OnlyCovered)),
+			l.Name,
+		})
+	}
+	sb.WriteString(format.FormatTable(tbl))
+	sb.WriteRune('\n')
+	return sb.String()
+}
+
+// WeakFile is a file with covered lines which ran fewer times than a minimum
+type WeakFile struct {
+	Name string
+	// Lines is the number of weakly covered lines
+	Lines uint
+	// FewestHits is the lowest hit count of any weakly covered line
+	FewestHits uint
+}
+
+// NewWeaklyCovered generates a table of files with lines which ran fewer than 'minHits' times in the given format.
+// Returns an empty string if there are no files.
+func NewWeaklyCovered(files []WeakFile, minHits uint, format Format) string {
+	if len(files) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Weakly covered lines, run fewer than %d times:\n", minHits)
+	tbl := table.NewWriter()
+	bold := boldColor()
+	tbl.AppendHeader(table.Row{
+		format.Colorize(bold, "Lines"),
+		format.Colorize(bold, "Fewest Hits"),
+		format.Colorize(bold, "File"),
+	})
+	for _, f := range files {
+		tbl.AppendRow(table.Row{
+			format.Monospace(fmt.Sprintf("%d", f.Lines)),
+			format.Monospace(fmt.Sprintf("%d", f.FewestHits)),
+			f.Name,
+		})
+	}
+	sb.WriteString(format.FormatTable(tbl))
+	sb.WriteRune('\n')
+	return sb.String()
+}
+
+// Mutant is a mutant which survived mutation testing
+type Mutant struct {
+	File        string
+	Line        uint
+	Description string
+}
+
+// NewMutants generates a mutation testing report in the given format, with a table of 'survived' mutants out of 'total'
+func NewMutants(total uint, survived []Mutant, format Format) string {
+	if total == 0 {
+		return "Mutation testing found no mutants in covered lines.\n"
+	}
+	var sb strings.Builder
+	killed := total - uint(len(survived))
+	percent := float64(killed) / float64(total)
+	fmt.Fprintf(&sb, "Mutation testing killed %d of %d mutants (%s).\n",
+		killed,
+		total,
+		format.ColorizeStatus(coverstatus.New(percent), strings.TrimSpace(FormatPercent(percent))),
+	)
+	if len(survived) == 0 {
+		return sb.String()
+	}
+
+	sb.WriteString("Survived mutants, tests still pass with these changes:\n")
+	tbl := table.NewWriter()
+	bold := boldColor()
+	tbl.AppendHeader(table.Row{
+		format.Colorize(bold, "Line"),
+		format.Colorize(bold, "Mutation"),
+	})
+	for _, m := range survived {
+		tbl.AppendRow(table.Row{
+			fmt.Sprintf("%s:%d", m.File, m.Line),
+			m.Description,
+		})
+	}
+	sb.WriteString(format.FormatTable(tbl))
+	sb.WriteRune('\n')
+	return sb.String()
+}
+
+// PackageChange is a package's current coverage and its coverage at a baseline commit. Both are percentages between 0 and 1.
+type PackageChange struct {
+	Name     string
+	Coverage float64
+	Baseline float64
+}
+
+// NewBaseline generates a comparison of total coverage against the baseline 'commit' in the given format.
+// Also includes a table of 'packages' with lower coverage than the baseline.
+func NewBaseline(commit string, total PackageChange, packages []PackageChange, format Format) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Total coverage is %s (%s compared to %s).\n",
+		strings.TrimSpace(FormatPercent(total.Coverage)),
+		formatChange(total.Coverage-total.Baseline),
+		format.Monospace(commit),
+	)
+	if len(packages) == 0 {
+		return sb.String()
+	}
+
+	sb.WriteString("Packages with lower coverage:\n")
+	tbl := table.NewWriter()
+	bold := boldColor()
+	tbl.AppendHeader(table.Row{
+		format.Colorize(bold, "Coverage"),
+		format.Colorize(bold, "Change"),
+		format.Colorize(bold, "Package"),
+	})
+	for _, pkg := range packages {
+		status := coverstatus.New(pkg.Coverage)
+		tbl.AppendRow(table.Row{
+			format.ColorizeStatus(status, format.Monospace(FormatPercent(pkg.Coverage))),
+			format.ColorizeStatus(status, format.Monospace(formatChange(pkg.Coverage-pkg.Baseline))),
+			pkg.Name,
+		})
+	}
+	sb.WriteString(format.FormatTable(tbl))
+	sb.WriteRune('\n')
+	return sb.String()
+}
+
+// formatChange formats a difference between percentages as a signed percent, like "-2.5%"
+func formatChange(f float64) string {
+	const maxPercentInt = 100
+	return fmt.Sprintf("%+.1f%%", maxPercentInt*f)
+}
+
+// TestSuggestion points at where to add tests for a function
+type TestSuggestion struct {
+	Function    coverfile.Function
+	TestFile    string
+	NewTestFile bool
+	Tests       []string
+}
+
+// NewTestSuggestions generates a list of where to add tests for each function, like "add a case to TestHandle in server_test.go".
+// Returns an empty string if there are no suggestions.
+func NewTestSuggestions(suggestions []TestSuggestion, format Format) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Suggested tests:\n")
+	for _, s := range suggestions {
+		fmt.Fprintf(&sb, "- %s in %s: ", format.Monospace(funcs.FullName(s.Function.Receiver, s.Function.Name)), s.Function.File)
+		switch {
+		case len(s.Tests) > 0:
+			tests := make([]string, 0, len(s.Tests))
+			for _, test := range s.Tests {
+				tests = append(tests, format.Monospace(test))
+			}
+			fmt.Fprintf(&sb, "add a case to %s in %s", joinOr(tests), s.TestFile)
+		case s.NewTestFile:
+			fmt.Fprintf(&sb, "create %s", s.TestFile)
+		default:
+			fmt.Fprintf(&sb, "add a test to %s", s.TestFile)
+		}
+		sb.WriteRune('\n')
+	}
+	return sb.String()
+}
+
+// joinOr joins items in a list like "a", "a or b", or "a, b, or c"
+func joinOr(items []string) string {
+	const pair = 2
+	switch len(items) {
+	case 1:
+		return items[0]
+	case pair:
+		return items[0] + " or " + items[1]
+	default:
+		return strings.Join(items[:len(items)-1], ", ") + ", or " + items[len(items)-1]
+	}
+}
+
+// FunctionCoverage returns a Function's coverage percentage between 0 and 1
+func FunctionCoverage(f coverfile.Function) float64 {
+	return float64(f.Covered) / float64(f.Covered+f.Uncovered)
+}
+
 // FileCoverage returns a File's coverage percentage between 0 and 1
 func FileCoverage(f coverfile.File) float64 {
 	return float64(f.Covered) / float64(f.Covered+f.Uncovered)
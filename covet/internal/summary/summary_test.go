@@ -0,0 +1,28 @@
+package summary
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPackages(t *testing.T) {
+	t.Parallel()
+	total := 0.75
+	report := NewPackages([]Package{
+		{Name: "api", Covered: 1, Uncovered: 1, Total: &total},
+		{Name: "web", Covered: 1, Uncovered: 0},
+	}, FormatColorTerminal)
+	assert.Equal(t, strings.TrimSpace(`
+Total coverage of packages in the diff:
+┌────────┬────────┬─────────┐
+│ DIFF   │ TOTAL  │ PACKAGE │
+├────────┼────────┼─────────┤
+│  50.0% │  75.0% │ api     │
+│ 100.0% │   n/a  │ web     │
+└────────┴────────┴─────────┘
+`), strings.TrimSpace(report))
+
+	assert.Empty(t, NewPackages(nil, FormatColorTerminal))
+}
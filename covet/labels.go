@@ -0,0 +1,120 @@
+package covet
+
+import "sort"
+
+// LabelCoverage is the diff coverage from all coverage profiles with the same label, like "unit" or "integration"
+type LabelCoverage struct {
+	Label string
+	// Covered is the number of lines in the diff covered by this label's profiles
+	Covered uint
+	// Uncovered is the number of lines in the diff with coverage information which this label's profiles did not cover
+	Uncovered uint
+	// OnlyCovered is the number of lines in the diff covered by this label's profiles and no others
+	OnlyCovered uint
+}
+
+// Coverage returns the label's diff coverage percentage between 0 and 1
+func (l LabelCoverage) Coverage() float64 {
+	return float64(l.Covered) / float64(l.Covered+l.Uncovered)
+}
+
+// Labels returns the sorted labels of all coverage profiles. Unlabelled profiles are not included.
+func (c *Covet) Labels() []string {
+	var labels []string
+	for label := range c.labelCoveredLines {
+		if label != "" {
+			labels = append(labels, label)
+		}
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// DiffCoverageByLabel returns the diff coverage of each label's coverage profiles, sorted by label.
+// Returns nil if no profiles are labelled.
+func (c *Covet) DiffCoverageByLabel() []LabelCoverage {
+	labels := c.Labels()
+	if len(labels) == 0 {
+		return nil
+	}
+	labelCoverage := make([]LabelCoverage, len(labels))
+	for i, label := range labels {
+		labelCoverage[i].Label = label
+	}
+	for _, f := range c.DiffCoverageFiles() {
+		for _, line := range f.Lines {
+			hits := c.lineHits(f.Name, line.LineNumber)
+			for i := range labelCoverage {
+				l := &labelCoverage[i]
+				switch {
+				case !containsLabel(hits, l.Label):
+					l.Uncovered++
+				case len(hits) == 1:
+					l.Covered++
+					l.OnlyCovered++
+				default:
+					l.Covered++
+				}
+			}
+		}
+	}
+	return labelCoverage
+}
+
+// indexLabelHits records the labels which covered each line, so per-line lookups don't rescan every label's spans.
+// Skipped when no profiles are labelled, since no line has labels to report.
+func (c *Covet) indexLabelHits() {
+	if len(c.Labels()) == 0 {
+		return
+	}
+	labels := make([]string, 0, len(c.labelCoveredLines))
+	for label := range c.labelCoveredLines {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	c.lineLabelHits = make(map[string]map[uint][]string)
+	for _, label := range labels {
+		for file, covered := range c.labelCoveredLines[label] {
+			fileHits := c.lineLabelHits[file]
+			if fileHits == nil {
+				fileHits = make(map[uint][]string)
+				c.lineLabelHits[file] = fileHits
+			}
+			for _, s := range covered {
+				for line := s.Start; line < s.End; line++ {
+					fileHits[line] = append(fileHits[line], label)
+				}
+			}
+		}
+	}
+}
+
+// lineHits returns the sorted labels of profiles which covered a line in 'coverageFile', including an empty label for unlabelled profiles.
+// Returns nil if no profiles are labelled.
+func (c *Covet) lineHits(coverageFile string, lineNumber uint) []string {
+	return c.lineLabelHits[coverageFile][lineNumber]
+}
+
+// lineLabels returns the sorted labels of labelled profiles which covered a line in 'coverageFile'
+func (c *Covet) lineLabels(coverageFile string, lineNumber uint) []string {
+	labels := c.lineHits(coverageFile, lineNumber)
+	if len(labels) > 0 && labels[0] == "" {
+		labels = labels[1:]
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	return labels
+}
+
+// coveredOnlyBy returns true if a line in 'coverageFile' was covered by profiles with 'label' and no others
+func (c *Covet) coveredOnlyBy(coverageFile string, lineNumber uint, label string) bool {
+	hits := c.lineHits(coverageFile, lineNumber)
+	return len(hits) == 1 && hits[0] == label
+}
+
+func containsLabel(labels []string, label string) bool {
+	i := sort.SearchStrings(labels, label)
+	return i < len(labels) && labels[i] == label
+}
@@ -0,0 +1,156 @@
+package covet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/johnstarich/go/covet/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseLabelsCovet(t *testing.T) *Covet {
+	t.Helper()
+	fs := testhelpers.FSWithFiles(t, map[string]string{
+		"go.mod": `module github.com/org/repo`,
+		"main.go": `package main
+
+func main() {
+	println(1)
+	println(2)
+	println(3)
+	println(4)
+}
+`,
+		"unit.out": `
+mode: set
+github.com/org/repo/main.go:4.1,4.11 1 1
+github.com/org/repo/main.go:5.1,5.11 1 1
+github.com/org/repo/main.go:6.1,6.11 1 0
+github.com/org/repo/main.go:7.1,7.11 1 0
+`,
+		"integ.out": `
+mode: set
+github.com/org/repo/main.go:4.1,4.11 1 0
+github.com/org/repo/main.go:5.1,5.11 1 1
+github.com/org/repo/main.go:6.1,6.11 1 1
+github.com/org/repo/main.go:7.1,7.11 1 0
+`,
+	})
+	diff := `
+diff --git a/main.go b/main.go
+index 0000000..1111111 100644
+--- a/main.go
++++ b/main.go
+@@ -0,0 +1,8 @@
++package main
++
++func main() {
++	println(1)
++	println(2)
++	println(3)
++	println(4)
++}
+`
+	cov, err := Parse(Options{
+		FS:          fs,
+		Diff:        strings.NewReader(strings.TrimSpace(diff)),
+		DiffBaseDir: ".",
+		GoCoverageProfiles: []GoCoverageProfile{
+			{Path: "unit.out", Label: "unit"},
+			{Path: "integ.out", Label: "integration"},
+		},
+	})
+	require.NoError(t, err)
+	return cov
+}
+
+func TestDiffCoverageByLabel(t *testing.T) {
+	t.Parallel()
+	cov := parseLabelsCovet(t)
+	assert.Equal(t, []string{"integration", "unit"}, cov.Labels())
+	assert.Equal(t, []LabelCoverage{
+		{Label: "integration", Covered: 2, Uncovered: 2, OnlyCovered: 1},
+		{Label: "unit", Covered: 2, Uncovered: 2, OnlyCovered: 1},
+	}, cov.DiffCoverageByLabel())
+	assert.Equal(t, []File{{
+		Name:      "main.go",
+		Covered:   3,
+		Uncovered: 1,
+		Lines: []Line{
+			{Covered: true, LineNumber: 4, Labels: []string{"unit"}},
+			{Covered: true, LineNumber: 5, Labels: []string{"integration", "unit"}},
+			{Covered: true, LineNumber: 6, Labels: []string{"integration"}},
+			{Covered: false, LineNumber: 7},
+		},
+	}}, cov.DiffCoverageFiles())
+}
+
+func TestDiffCoverageByLabelUnlabelled(t *testing.T) {
+	t.Parallel()
+	cov := parseFunctionsCovet(t)
+	assert.Empty(t, cov.Labels())
+	assert.Nil(t, cov.DiffCoverageByLabel())
+}
+
+func TestReportFileCoverageOnlyLabel(t *testing.T) {
+	t.Parallel()
+	cov := parseLabelsCovet(t)
+	files := cov.DiffCoverageFiles()
+	require.Len(t, files, 1)
+
+	var buf bytes.Buffer
+	require.NoError(t, cov.ReportFileCoverage(&buf, files[0], ReportFileCoverageOptions{OnlyLabel: "integration"}))
+	assert.Equal(t, strings.TrimSpace(`
+Coverage: 2 to 8
+ 
+ func main() {
++	println(1)  [unit]
++	println(2)  [integration, unit]
+~	println(3)  [integration]
+-	println(4)
+ }
+`), strings.TrimSpace(buf.String()))
+}
+
+func TestReportSummaryLabels(t *testing.T) {
+	t.Parallel()
+	cov := parseLabelsCovet(t)
+	var buf bytes.Buffer
+	require.NoError(t, cov.ReportSummaryColorTerminal(&buf, ReportSummaryOptions{Target: 90}))
+	assert.Contains(t, buf.String(), `
+Diff coverage by label:
+┌───────┬──────────────┬──────┬─────────────┐
+│ LINES │ COVERAGE     │ ONLY │ LABEL       │
+├───────┼──────────────┼──────┼─────────────┤
+│  2/4  │  50.0% ██▌   │ 1    │ integration │
+│  2/4  │  50.0% ██▌   │ 1    │ unit        │
+└───────┴──────────────┴──────┴─────────────┘
+`)
+}
+
+func TestReportJSONLabels(t *testing.T) {
+	t.Parallel()
+	cov := parseLabelsCovet(t)
+	var buf bytes.Buffer
+	require.NoError(t, cov.ReportJSON(&buf, ReportJSONOptions{Target: 90}))
+	assert.Contains(t, buf.String(), `
+  "labels": [
+    {
+      "label": "integration",
+      "diffCoverage": 0.5,
+      "covered": 2,
+      "uncovered": 2,
+      "onlyCovered": 1
+    },
+    {
+      "label": "unit",
+      "diffCoverage": 0.5,
+      "covered": 2,
+      "uncovered": 2,
+      "onlyCovered": 1
+    }
+  ]
+`)
+}
@@ -0,0 +1,117 @@
+package covet
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/hack-pad/hackpadfs"
+	"github.com/johnstarich/go/covet/internal/mutate"
+	"github.com/johnstarich/go/covet/internal/span"
+	"github.com/johnstarich/go/covet/internal/summary"
+	"github.com/pkg/errors"
+)
+
+// Mutant is a small change to a covered line in the diff, like flipping '<' to '>='.
+// If tests still pass with a mutant applied, the mutant survived and the tests may not catch bugs in that line.
+type Mutant struct {
+	// File is the mutated file's path relative to DiffBaseDir
+	File string
+	// Line is the mutated line number
+	Line uint
+	// Description explains the change, like "flip '<' to '>='"
+	Description string
+
+	// path is the FS path to File
+	path     string
+	mutation mutate.Mutation
+}
+
+// Path returns the FS path to the mutated file
+func (m Mutant) Path() string {
+	return m.path
+}
+
+// MutantResult is the outcome of running tests with a Mutant applied
+type MutantResult struct {
+	Mutant
+	// Killed is true if tests failed with the mutant applied
+	Killed bool
+}
+
+// Mutants returns mutants for covered lines in the diff, ordered by file and position.
+// Only Go files are mutated, excluding tests. Uncovered lines are skipped since no test can catch their mutants.
+func (c *Covet) Mutants() ([]Mutant, error) {
+	var mutants []Mutant
+	for _, f := range c.sortedDiffCoverageFiles() {
+		if !strings.HasSuffix(f.Name, ".go") || strings.HasSuffix(f.Name, testFileSuffix) {
+			continue
+		}
+		var coveredLines []span.Span
+		for _, line := range f.Lines {
+			if line.Covered {
+				coveredLines = append(coveredLines, span.Span{Start: line.LineNumber, End: line.LineNumber + 1})
+			}
+		}
+		filePath := path.Join(c.coverageBaseDir, f.Name)
+		src, err := hackpadfs.ReadFile(c.options.FS, filePath)
+		if err != nil {
+			return nil, err
+		}
+		mutations, err := mutate.Find(src, span.Union(coveredLines))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse Go file %q", f.Name)
+		}
+		for _, m := range mutations {
+			mutants = append(mutants, Mutant{
+				File:        c.DiffFilePath(f),
+				Line:        m.Line,
+				Description: m.Description,
+				path:        filePath,
+				mutation:    m,
+			})
+		}
+	}
+	return mutants, nil
+}
+
+// MutatedSource returns the contents of the mutant's file with the mutant applied
+func (c *Covet) MutatedSource(m Mutant) ([]byte, error) {
+	src, err := hackpadfs.ReadFile(c.options.FS, m.path)
+	if err != nil {
+		return nil, err
+	}
+	if m.mutation.End > len(src) {
+		return nil, errors.Errorf("file %q changed since finding mutants", m.File)
+	}
+	return mutate.Apply(src, m.mutation), nil
+}
+
+// survivedMutants returns the mutants in 'results' which were not killed, sorted by file and line
+func survivedMutants(results []MutantResult) []Mutant {
+	var survived []Mutant
+	for _, result := range results {
+		if !result.Killed {
+			survived = append(survived, result.Mutant)
+		}
+	}
+	sort.SliceStable(survived, func(a, b int) bool {
+		if survived[a].File != survived[b].File {
+			return survived[a].File < survived[b].File
+		}
+		return survived[a].Line < survived[b].Line
+	})
+	return survived
+}
+
+func mutantsSummary(results []MutantResult, format summary.Format) string {
+	var survived []summary.Mutant
+	for _, m := range survivedMutants(results) {
+		survived = append(survived, summary.Mutant{
+			File:        m.File,
+			Line:        m.Line,
+			Description: m.Description,
+		})
+	}
+	return "\n" + summary.NewMutants(uint(len(results)), survived, format)
+}
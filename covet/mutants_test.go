@@ -0,0 +1,174 @@
+package covet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/johnstarich/go/covet/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseMutantsCovet(t *testing.T) *Covet {
+	t.Helper()
+	fs := testhelpers.FSWithFiles(t, map[string]string{
+		"go.mod": `module github.com/org/repo`,
+		"max.go": `package repo
+
+func Max(a, b int) int {
+	if a < b {
+		return b
+	}
+	return a
+}
+
+func IsEmpty(s string) bool {
+	return s == ""
+}
+`,
+		"max_test.go": `package repo
+
+func helper() bool {
+	return true
+}
+`,
+		"cover.out": `
+mode: set
+github.com/org/repo/max.go:3.24,4.11 1 1
+github.com/org/repo/max.go:4.11,6.3 1 1
+github.com/org/repo/max.go:7.2,7.10 1 1
+github.com/org/repo/max.go:10.29,11.13 1 0
+github.com/org/repo/max_test.go:3.20,4.13 1 1
+`,
+	})
+	diff := `
+diff --git a/max.go b/max.go
+index 0000000..1111111 100644
+--- a/max.go
++++ b/max.go
+@@ -0,0 +1,12 @@
++package repo
++
++func Max(a, b int) int {
++	if a < b {
++		return b
++	}
++	return a
++}
++
++func IsEmpty(s string) bool {
++	return s == ""
++}
+diff --git a/max_test.go b/max_test.go
+index 0000000..1111111 100644
+--- a/max_test.go
++++ b/max_test.go
+@@ -0,0 +1,5 @@
++package repo
++
++func helper() bool {
++	return true
++}
+`
+	cov, err := Parse(Options{
+		FS:                 fs,
+		Diff:               strings.NewReader(strings.TrimSpace(diff)),
+		DiffBaseDir:        ".",
+		GoCoverageProfiles: []GoCoverageProfile{{Path: "cover.out"}},
+	})
+	require.NoError(t, err)
+	return cov
+}
+
+func TestMutants(t *testing.T) {
+	t.Parallel()
+	cov := parseMutantsCovet(t)
+	mutants, err := cov.Mutants()
+	require.NoError(t, err)
+
+	var descriptions []string
+	for _, m := range mutants {
+		assert.Equal(t, "max.go", m.File)
+		assert.Equal(t, "max.go", m.Path())
+		descriptions = append(descriptions, m.Description)
+	}
+	assert.Equal(t, []string{
+		"negate 'if' condition",
+		"flip '<' to '>='",
+	}, descriptions)
+
+	src, err := cov.MutatedSource(mutants[1])
+	require.NoError(t, err)
+	assert.Contains(t, string(src), "\tif a >= b {\n")
+}
+
+func mutantResults(t *testing.T, cov *Covet) []MutantResult {
+	t.Helper()
+	mutants, err := cov.Mutants()
+	require.NoError(t, err)
+	require.Len(t, mutants, 2)
+	return []MutantResult{
+		{Mutant: mutants[0], Killed: true},
+		{Mutant: mutants[1], Killed: false},
+	}
+}
+
+func TestReportSummaryMutants(t *testing.T) {
+	t.Parallel()
+	cov := parseMutantsCovet(t)
+	var buf bytes.Buffer
+	require.NoError(t, cov.ReportSummaryColorTerminal(&buf, ReportSummaryOptions{Target: 50, Mutants: mutantResults(t, cov)}))
+	assert.Equal(t, strings.TrimSpace(`
+Successfully reached diff coverage target: >50%
+
+Total coverage of packages in the diff:
+┌────────┬────────┬─────────┐
+│ DIFF   │ TOTAL  │ PACKAGE │
+├────────┼────────┼─────────┤
+│  77.8% │  77.8% │ .       │
+└────────┴────────┴─────────┘
+
+Mutation testing killed 1 of 2 mutants (50.0%).
+Survived mutants, tests still pass with these changes:
+┌──────────┬──────────────────┐
+│ LINE     │ MUTATION         │
+├──────────┼──────────────────┤
+│ max.go:4 │ flip '<' to '>=' │
+└──────────┴──────────────────┘
+`), strings.TrimSpace(buf.String()))
+
+	buf.Reset()
+	require.NoError(t, cov.ReportSummaryColorTerminal(&buf, ReportSummaryOptions{Target: 50, Mutants: []MutantResult{}}))
+	assert.Contains(t, buf.String(), "Mutation testing found no mutants in covered lines.")
+}
+
+func TestReportJSONMutants(t *testing.T) {
+	t.Parallel()
+	cov := parseMutantsCovet(t)
+	var buf bytes.Buffer
+	require.NoError(t, cov.ReportJSON(&buf, ReportJSONOptions{Target: 50, Mutants: mutantResults(t, cov)}))
+	assert.Contains(t, buf.String(), `
+  "mutants": {
+    "total": 2,
+    "killed": 1,
+    "survived": [
+      {
+        "file": "max.go",
+        "line": 4,
+        "mutation": "flip '\u003c' to '\u003e='"
+      }
+    ]
+  }
+`)
+}
+
+func TestReportSARIFMutants(t *testing.T) {
+	t.Parallel()
+	cov := parseMutantsCovet(t)
+	var buf bytes.Buffer
+	require.NoError(t, cov.ReportSARIF(&buf, ReportSARIFOptions{Mutants: mutantResults(t, cov)}))
+	sarif := buf.String()
+	assert.Contains(t, sarif, `"ruleId": "survived-mutant"`)
+	assert.Contains(t, sarif, `"level": "warning"`)
+}
@@ -0,0 +1,295 @@
+package covet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/johnstarich/go/covet/internal/coverfile"
+	"github.com/johnstarich/go/covet/internal/coverstatus"
+	"github.com/johnstarich/go/covet/internal/span"
+	"github.com/johnstarich/go/covet/internal/summary"
+)
+
+// ReportJSONOptions contains JSON report options
+type ReportJSONOptions struct {
+	Target uint
+	// ShowHits includes each covered line's hit count. Requires 'count' or 'atomic' mode Go coverage profiles.
+	ShowHits bool
+	// MinHits includes ranges of covered lines which ran fewer than MinHits times. Zero disables weakly covered lines.
+	MinHits uint
+	// Mutants includes mutation testing results. See Covet.Mutants.
+	Mutants []MutantResult
+}
+
+type jsonReport struct {
+	DiffCoverage float64    `json:"diffCoverage"`
+	Target       uint       `json:"target"`
+	Covered      uint       `json:"covered"`
+	Uncovered    uint       `json:"uncovered"`
+	Files        []jsonFile `json:"files"`
+	// Labels is omitted unless coverage profiles are labelled
+	Labels []jsonLabel `json:"labels,omitempty"`
+	// Mutants is omitted unless ReportJSONOptions.Mutants is set
+	Mutants *jsonMutants `json:"mutants,omitempty"`
+}
+
+type jsonMutants struct {
+	Total    uint         `json:"total"`
+	Killed   uint         `json:"killed"`
+	Survived []jsonMutant `json:"survived"`
+}
+
+type jsonMutant struct {
+	File     string `json:"file"`
+	Line     uint   `json:"line"`
+	Mutation string `json:"mutation"`
+}
+
+type jsonLabel struct {
+	Label        string  `json:"label"`
+	DiffCoverage float64 `json:"diffCoverage"`
+	Covered      uint    `json:"covered"`
+	Uncovered    uint    `json:"uncovered"`
+	OnlyCovered  uint    `json:"onlyCovered"`
+}
+
+type jsonFile struct {
+	// Name is the file's path relative to the diff's base directory, like SARIF locations and mutant files
+	Name           string     `json:"name"`
+	DiffCoverage   float64    `json:"diffCoverage"`
+	Covered        uint       `json:"covered"`
+	Uncovered      uint       `json:"uncovered"`
+	UncoveredLines []jsonSpan `json:"uncoveredLines"`
+	// WeaklyCoveredLines is omitted unless ReportJSONOptions.MinHits is set
+	WeaklyCoveredLines []jsonSpan `json:"weaklyCoveredLines,omitempty"`
+	// LineHits is omitted unless ReportJSONOptions.ShowHits is set
+	LineHits []jsonLineHits `json:"lineHits,omitempty"`
+}
+
+type jsonLineHits struct {
+	Line uint `json:"line"`
+	Hits uint `json:"hits"`
+}
+
+// jsonSpan is a range of line numbers. Both StartLine and EndLine are inclusive.
+type jsonSpan struct {
+	StartLine uint `json:"startLine"`
+	EndLine   uint `json:"endLine"`
+}
+
+// ReportJSON writes a machine-readable JSON report to 'w'.
+// Includes the total diff coverage, plus each file's covered and uncovered line counts and uncovered line ranges.
+// If coverage profiles are labelled, also includes each label's diff coverage.
+func (c *Covet) ReportJSON(w io.Writer, options ReportJSONOptions) error {
+	report := jsonReport{
+		DiffCoverage: 1,
+		Target:       options.Target,
+		Files:        []jsonFile{},
+	}
+	for _, f := range c.sortedDiffCoverageFiles() {
+		file := jsonFile{
+			Name:           c.DiffFilePath(f),
+			DiffCoverage:   summary.FileCoverage(f),
+			Covered:        f.Covered,
+			Uncovered:      f.Uncovered,
+			UncoveredLines: []jsonSpan{},
+		}
+		for _, s := range coverfile.UncoveredSpans(f) {
+			file.UncoveredLines = append(file.UncoveredLines, newJSONSpan(s))
+		}
+		if options.MinHits > 0 {
+			for _, s := range lineSpans(WeaklyCoveredLines(f, options.MinHits)) {
+				file.WeaklyCoveredLines = append(file.WeaklyCoveredLines, newJSONSpan(s))
+			}
+		}
+		if options.ShowHits {
+			for _, line := range f.Lines {
+				if line.Hits > 0 {
+					file.LineHits = append(file.LineHits, jsonLineHits{Line: line.LineNumber, Hits: line.Hits})
+				}
+			}
+		}
+		report.Covered += f.Covered
+		report.Uncovered += f.Uncovered
+		report.Files = append(report.Files, file)
+	}
+	if len(report.Files) > 0 {
+		report.DiffCoverage = c.DiffCovered()
+	}
+	for _, l := range c.DiffCoverageByLabel() {
+		report.Labels = append(report.Labels, jsonLabel{
+			Label:        l.Label,
+			DiffCoverage: l.Coverage(),
+			Covered:      l.Covered,
+			Uncovered:    l.Uncovered,
+			OnlyCovered:  l.OnlyCovered,
+		})
+	}
+	if options.Mutants != nil {
+		survived := survivedMutants(options.Mutants)
+		report.Mutants = &jsonMutants{
+			Total:    uint(len(options.Mutants)),
+			Killed:   uint(len(options.Mutants) - len(survived)),
+			Survived: []jsonMutant{},
+		}
+		for _, m := range survived {
+			report.Mutants.Survived = append(report.Mutants.Survived, jsonMutant{
+				File:     m.File,
+				Line:     m.Line,
+				Mutation: m.Description,
+			})
+		}
+	}
+	return writeJSON(w, report)
+}
+
+func newJSONSpan(s span.Span) jsonSpan {
+	return jsonSpan{StartLine: s.Start, EndLine: s.End - 1}
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func (c *Covet) sortedDiffCoverageFiles() []File {
+	files := c.DiffCoverageFiles()
+	sort.Slice(files, func(a, b int) bool {
+		return files[a].Name < files[b].Name
+	})
+	return files
+}
+
+// ReportSARIFOptions contains SARIF report options
+type ReportSARIFOptions struct {
+	// Mutants adds a result for each survived mutant. See Covet.Mutants.
+	Mutants []MutantResult
+}
+
+const (
+	sarifSchema        = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion       = "2.1.0"
+	sarifToolURI       = "https://github.com/JohnStarich/go/tree/master/covet"
+	sarifUncoveredRule = "uncovered-diff"
+	sarifMutantRule    = "survived-mutant"
+)
+
+type sarifReport struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine uint `json:"startLine"`
+	EndLine   uint `json:"endLine"`
+}
+
+// ReportSARIF writes a SARIF report to 'w', with one result for each range of uncovered lines in the diff.
+// Upload to code scanning tools to display uncovered lines inline.
+func (c *Covet) ReportSARIF(w io.Writer, options ReportSARIFOptions) error {
+	results := []sarifResult{}
+	for _, f := range c.sortedDiffCoverageFiles() {
+		percent := summary.FileCoverage(f)
+		status := coverstatus.New(percent)
+		for _, s := range coverfile.UncoveredSpans(f) {
+			lines := newJSONSpan(s)
+			results = append(results, sarifResult{
+				RuleID: sarifUncoveredRule,
+				Level:  status.SARIFLevel(),
+				Message: sarifMessage{
+					Text: fmt.Sprintf("Lines %d-%d are not covered by tests. File diff coverage is %s.", lines.StartLine, lines.EndLine, strings.TrimSpace(summary.FormatPercent(percent))),
+				},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: c.DiffFilePath(f)},
+						Region:           sarifRegion(lines),
+					},
+				}},
+			})
+		}
+	}
+	rules := []sarifRule{{
+		ID:               sarifUncoveredRule,
+		ShortDescription: sarifMessage{Text: "New lines in the diff are not covered by tests."},
+	}}
+	if options.Mutants != nil {
+		rules = append(rules, sarifRule{
+			ID:               sarifMutantRule,
+			ShortDescription: sarifMessage{Text: "Tests still pass after changing a covered line in the diff."},
+		})
+		for _, m := range survivedMutants(options.Mutants) {
+			results = append(results, sarifResult{
+				RuleID:  sarifMutantRule,
+				Level:   "warning",
+				Message: sarifMessage{Text: fmt.Sprintf("Tests still pass after a mutation: %s.", m.Description)},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: m.File},
+						Region:           sarifRegion{StartLine: m.Line, EndLine: m.Line},
+					},
+				}},
+			})
+		}
+	}
+	return writeJSON(w, sarifReport{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "covet",
+				InformationURI: sarifToolURI,
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	})
+}
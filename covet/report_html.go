@@ -0,0 +1,230 @@
+package covet
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"path"
+
+	"github.com/hack-pad/hackpadfs"
+	"github.com/johnstarich/go/covet/internal/coverfile"
+	"github.com/johnstarich/go/covet/internal/coverstatus"
+	"github.com/johnstarich/go/covet/internal/highlight"
+	"github.com/johnstarich/go/covet/internal/summary"
+)
+
+//go:embed report.html
+var reportHTMLTemplate string
+
+// ReportHTMLOptions contains HTML report options
+type ReportHTMLOptions struct {
+	Target uint
+	// ContextLines is the number of unchanged lines to show around each line in the diff
+	ContextLines uint
+	// ShowHits adds a column with each covered line's hit count. Requires 'count' or 'atomic' mode Go coverage profiles.
+	ShowHits bool
+	// MinHits highlights covered lines which ran fewer than MinHits times as weakly covered. Zero disables highlighting.
+	MinHits uint
+}
+
+type htmlReport struct {
+	DiffCoverage string
+	Status       string
+	Target       uint
+	Covered      uint
+	Total        uint
+	ShowHits     bool
+	Files        []htmlFile
+}
+
+type htmlFile struct {
+	ID       string
+	Name     string
+	Coverage string
+	Status   string
+	Covered  uint
+	Total    uint
+	Chunks   []htmlChunk
+}
+
+type htmlChunk struct {
+	FileID string
+	HTMLChunk
+}
+
+// HTMLChunk is part of a file's escaped source with each line's coverage marked, for HTML views
+type HTMLChunk struct {
+	FirstLine, LastLine uint
+	Lines               []HTMLLine
+}
+
+// HTMLLine is a line in an HTMLChunk
+type HTMLLine struct {
+	Number uint
+	// Op is "+" for covered lines, "-" for uncovered lines, or " " for context
+	Op string
+	// Class is one of "covered", "weak", "uncovered", or "context"
+	Class string
+	// Hits is the number of times a covered line ran, like "3×". Empty if unknown.
+	Hits string
+	// Code is the escaped source line. Go files are syntax-highlighted.
+	Code template.HTML
+}
+
+// ReportHTML writes a self-contained HTML report to 'w', suitable for publishing as a CI artifact.
+// Includes an index of files sorted by most uncovered lines, then each file's syntax-highlighted source with covered and uncovered lines marked.
+func (c *Covet) ReportHTML(w io.Writer, options ReportHTMLOptions) error {
+	tmpl, err := template.New("report").Parse(reportHTMLTemplate)
+	if err != nil {
+		return err
+	}
+
+	files := c.DiffCoverageFiles()
+	sortByPriority(files)
+	totalCoverage := 1.0
+	if len(files) > 0 {
+		totalCoverage = c.DiffCovered()
+	}
+	report := htmlReport{
+		DiffCoverage: summary.FormatPercent(totalCoverage),
+		Status:       coverstatus.New(totalCoverage).Name(),
+		Target:       options.Target,
+		ShowHits:     options.ShowHits,
+	}
+	for i, f := range files {
+		file, err := c.htmlFile(fmt.Sprintf("file-%d", i+1), f, options)
+		if err != nil {
+			return err
+		}
+		report.Covered += file.Covered
+		report.Total += file.Total
+		report.Files = append(report.Files, file)
+	}
+	return tmpl.Execute(w, report)
+}
+
+func (c *Covet) htmlFile(id string, f File, options ReportHTMLOptions) (htmlFile, error) {
+	percent := summary.FileCoverage(f)
+	file := htmlFile{
+		ID:       id,
+		Name:     f.Name,
+		Coverage: summary.FormatPercent(percent),
+		Status:   coverstatus.New(percent).Name(),
+		Covered:  f.Covered,
+		Total:    f.Covered + f.Uncovered,
+	}
+	chunks, err := c.HTMLChunks(f, options.ContextLines, options.MinHits)
+	if err != nil {
+		return htmlFile{}, err
+	}
+	for _, chunk := range chunks {
+		file.Chunks = append(file.Chunks, htmlChunk{FileID: id, HTMLChunk: chunk})
+	}
+	return file, nil
+}
+
+// HTMLChunks returns the lines in the diff of 'f' with 'contextLines' unchanged lines around them, for HTML views.
+// Covered lines which ran fewer than 'minHits' times are marked weak. Zero disables weak lines.
+func (c *Covet) HTMLChunks(f File, contextLines, minHits uint) ([]HTMLChunk, error) {
+	contents, err := hackpadfs.ReadFile(c.options.FS, path.Join(c.coverageBaseDir, f.Name))
+	if err != nil {
+		return nil, err
+	}
+	highlightedLines := highlight.Lines(f.Name, contents)
+	chunks, err := coverfile.DiffChunksWithContext(f, bytes.NewReader(contents), contextLines)
+	if err != nil {
+		return nil, err
+	}
+	lineHits := make(map[uint]uint)
+	for _, line := range f.Lines {
+		if line.Hits > 0 {
+			lineHits[line.LineNumber] = line.Hits
+		}
+	}
+	htmlChunks := make([]HTMLChunk, 0, len(chunks))
+	for _, chunk := range chunks {
+		htmlChunk := HTMLChunk{
+			FirstLine: chunk.FirstLine,
+			LastLine:  chunk.LastLine,
+		}
+		for i, line := range chunk.Lines {
+			lineNumber := chunk.FirstLine + uint(i)
+			op := line[:1]
+			class := "context"
+			hits := lineHits[lineNumber]
+			switch {
+			case op == "+" && hits > 0 && hits < minHits:
+				class = "weak"
+			case op == "+":
+				class = "covered"
+			case op == "-":
+				class = "uncovered"
+			}
+			htmlChunk.Lines = append(htmlChunk.Lines, HTMLLine{
+				Number: lineNumber,
+				Op:     op,
+				Class:  class,
+				Hits:   formatHTMLHits(hits),
+				Code:   lineHTML(highlightedLines, lineNumber),
+			})
+		}
+		htmlChunks = append(htmlChunks, htmlChunk)
+	}
+	return htmlChunks, nil
+}
+
+// HTMLFullChunk returns every line of the file in 'f' in one chunk, for HTML views.
+// Lines in the diff have a "+" Op, and any line with coverage information is marked covered or uncovered.
+func (c *Covet) HTMLFullChunk(f File) (HTMLChunk, error) {
+	contents, err := hackpadfs.ReadFile(c.options.FS, path.Join(c.coverageBaseDir, f.Name))
+	if err != nil {
+		return HTMLChunk{}, err
+	}
+	highlightedLines := highlight.Lines(f.Name, contents)
+	lineCoverage := make(map[uint]bool)
+	for _, line := range c.FileCoverage(f).Lines {
+		lineCoverage[line.LineNumber] = line.Covered
+	}
+	addedLines := make(map[uint]bool)
+	for _, line := range f.Lines {
+		addedLines[line.LineNumber] = true
+	}
+	chunk := HTMLChunk{FirstLine: 1, LastLine: uint(len(highlightedLines))}
+	for i := range highlightedLines {
+		lineNumber := uint(i) + 1
+		class := "context"
+		if covered, ok := lineCoverage[lineNumber]; ok && covered {
+			class = "covered"
+		} else if ok {
+			class = "uncovered"
+		}
+		op := " "
+		if addedLines[lineNumber] {
+			op = "+"
+		}
+		chunk.Lines = append(chunk.Lines, HTMLLine{
+			Number: lineNumber,
+			Op:     op,
+			Class:  class,
+			Code:   highlightedLines[i],
+		})
+	}
+	return chunk, nil
+}
+
+// lineHTML returns the line at 'lineNumber' in 'lines', or an empty line if it's out of range
+func lineHTML(lines []template.HTML, lineNumber uint) template.HTML {
+	if index := int(lineNumber) - 1; index >= 0 && index < len(lines) {
+		return lines[index]
+	}
+	return ""
+}
+
+func formatHTMLHits(hits uint) string {
+	if hits == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d×", hits)
+}
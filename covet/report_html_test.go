@@ -0,0 +1,95 @@
+package covet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportHTML(t *testing.T) {
+	t.Parallel()
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		covet := parseTestCovet(t, "", map[string]string{
+			"mymodule/go.mod":    `module mymodule`,
+			"mymodule/cover.out": `mode: set`,
+		})
+		var buf bytes.Buffer
+		require.NoError(t, covet.ReportHTML(&buf, ReportHTMLOptions{Target: 90}))
+		assert.Contains(t, buf.String(), `<h1>Diff coverage <span class="status-excellent">100.0%</span></h1>`)
+		assert.Contains(t, buf.String(), "No coverage information intersects with diff.")
+	})
+
+	t.Run("partially covered", func(t *testing.T) {
+		t.Parallel()
+		diff := `
+diff --git a/mymodule/main.go b/mymodule/main.go
+index 0000000..1111111 100644
+--- a/mymodule/main.go
++++ b/mymodule/main.go
+@@ -1,4 +1,6 @@
+ package main
+ 
+ func main() {
++	println("<1>")
++	println(2)
+ }
+diff --git a/mymodule/other.go b/mymodule/other.go
+index 0000000..1111111 100644
+--- a/mymodule/other.go
++++ b/mymodule/other.go
+@@ -0,0 +1,1 @@
++package main
+`
+		covet := parseTestCovet(t, diff, map[string]string{
+			"mymodule/go.mod": `module mymodule`,
+			"mymodule/main.go": `
+package main
+
+func main() {
+	println("<1>")
+	println(2)
+}
+`,
+			"mymodule/other.go": `package main`,
+			"mymodule/cover.out": `
+mode: set
+mymodule/main.go:4.1,4.16 1 1
+mymodule/main.go:5.1,5.12 1 0
+mymodule/other.go:1.1,1.12 1 1
+`,
+		})
+		var buf bytes.Buffer
+		require.NoError(t, covet.ReportHTML(&buf, ReportHTMLOptions{Target: 90, ContextLines: 1}))
+		html := buf.String()
+		assert.Contains(t, html, `<h1>Diff coverage <span class="status-warning"> 66.7%</span></h1>`)
+		assert.Contains(t, html, `<p>2 of 3 new lines are covered by tests. Target is 90%.</p>`)
+		// index is sorted by most uncovered lines
+		assert.Contains(t, html, `<tr>
+<td><a href="#file-1">main.go</a></td>
+<td class="number">1/2</td>
+<td class="number status-warning"> 50.0%</td>
+</tr>
+<tr>
+<td><a href="#file-2">other.go</a></td>`)
+		assert.Contains(t, html, `<tr class="separator"><td colspan="3">Lines 3 to 6</td></tr>
+<tr class="context" id="file-1-L3"><td class="line-number"><a href="#file-1-L3">3</a></td><td class="op"> </td><td><span class="keyword">func</span> main() {</td></tr>
+<tr class="covered" id="file-1-L4"><td class="line-number"><a href="#file-1-L4">4</a></td><td class="op">&#43;</td><td>	println(<span class="string">&#34;&lt;1&gt;&#34;</span>)</td></tr>
+<tr class="uncovered" id="file-1-L5"><td class="line-number"><a href="#file-1-L5">5</a></td><td class="op">-</td><td>	println(<span class="literal">2</span>)</td></tr>
+<tr class="context" id="file-1-L6"><td class="line-number"><a href="#file-1-L6">6</a></td><td class="op"> </td><td>}</td></tr>
+</table>`)
+	})
+
+	t.Run("missing source file", func(t *testing.T) {
+		t.Parallel()
+		covet := parseTestCovet(t, testReportDiff, map[string]string{
+			"mymodule/go.mod":    `module mymodule`,
+			"mymodule/cover.out": testReportCoverage,
+		})
+		var buf bytes.Buffer
+		err := covet.ReportHTML(&buf, ReportHTMLOptions{})
+		assert.ErrorContains(t, err, "file does not exist")
+	})
+}
@@ -0,0 +1,165 @@
+package covet
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/johnstarich/go/covet/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseTestCovet(t *testing.T, diff string, files map[string]string) *Covet {
+	t.Helper()
+	fs := testhelpers.FSWithFiles(t, files)
+	covet, err := Parse(Options{
+		FS:             fs,
+		Diff:           strings.NewReader(strings.TrimSpace(diff)),
+		DiffBaseDir:    ".",
+		GoCoveragePath: "mymodule/cover.out",
+	})
+	require.NoError(t, err)
+	return covet
+}
+
+const (
+	testReportDiff = `
+diff --git a/mymodule/main.go b/mymodule/main.go
+index 0000000..1111111 100644
+--- a/mymodule/main.go
++++ b/mymodule/main.go
+@@ -0,0 +1,4 @@
++added 1
++added 2
++added 3
++added 4
+`
+	testReportCoverage = `
+mode: atomic
+mymodule/main.go:1.1,1.7 1 1
+mymodule/main.go:2.1,3.7 1 0
+mymodule/main.go:4.1,4.7 1 1
+`
+)
+
+func TestReportJSON(t *testing.T) {
+	t.Parallel()
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		covet := parseTestCovet(t, "", map[string]string{
+			"mymodule/go.mod":    `module mymodule`,
+			"mymodule/cover.out": `mode: set`,
+		})
+		var buf bytes.Buffer
+		assert.NoError(t, covet.ReportJSON(&buf, ReportJSONOptions{Target: 90}))
+		assert.JSONEq(t, `{
+			"diffCoverage": 1,
+			"target": 90,
+			"covered": 0,
+			"uncovered": 0,
+			"files": []
+		}`, buf.String())
+	})
+
+	t.Run("partially covered", func(t *testing.T) {
+		t.Parallel()
+		covet := parseTestCovet(t, testReportDiff, map[string]string{
+			"mymodule/go.mod":    `module mymodule`,
+			"mymodule/cover.out": testReportCoverage,
+		})
+		var buf bytes.Buffer
+		assert.NoError(t, covet.ReportJSON(&buf, ReportJSONOptions{Target: 90}))
+		assert.JSONEq(t, `{
+			"diffCoverage": 0.5,
+			"target": 90,
+			"covered": 2,
+			"uncovered": 2,
+			"files": [
+				{
+					"name": "mymodule/main.go",
+					"diffCoverage": 0.5,
+					"covered": 2,
+					"uncovered": 2,
+					"uncoveredLines": [
+						{"startLine": 2, "endLine": 3}
+					]
+				}
+			]
+		}`, buf.String())
+	})
+}
+
+func TestReportSARIF(t *testing.T) {
+	t.Parallel()
+	covet := parseTestCovet(t, testReportDiff, map[string]string{
+		"mymodule/go.mod":    `module mymodule`,
+		"mymodule/cover.out": testReportCoverage,
+	})
+	var buf bytes.Buffer
+	assert.NoError(t, covet.ReportSARIF(&buf, ReportSARIFOptions{}))
+	assert.JSONEq(t, `{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": [
+			{
+				"tool": {
+					"driver": {
+						"name": "covet",
+						"informationUri": "https://github.com/JohnStarich/go/tree/master/covet",
+						"rules": [
+							{
+								"id": "uncovered-diff",
+								"shortDescription": {"text": "New lines in the diff are not covered by tests."}
+							}
+						]
+					}
+				},
+				"results": [
+					{
+						"ruleId": "uncovered-diff",
+						"level": "warning",
+						"message": {"text": "Lines 2-3 are not covered by tests. File diff coverage is 50.0%."},
+						"locations": [
+							{
+								"physicalLocation": {
+									"artifactLocation": {"uri": "mymodule/main.go"},
+									"region": {"startLine": 2, "endLine": 3}
+								}
+							}
+						]
+					}
+				]
+			}
+		]
+	}`, buf.String())
+}
+
+func TestReportJSONMatchesSARIFPaths(t *testing.T) {
+	t.Parallel()
+	covet := parseTestCovet(t, testReportDiff, map[string]string{
+		"mymodule/go.mod":    `module mymodule`,
+		"mymodule/cover.out": testReportCoverage,
+	})
+	var jsonBuf, sarifBuf bytes.Buffer
+	require.NoError(t, covet.ReportJSON(&jsonBuf, ReportJSONOptions{}))
+	require.NoError(t, covet.ReportSARIF(&sarifBuf, ReportSARIFOptions{}))
+
+	var jsonOutput jsonReport
+	require.NoError(t, json.Unmarshal(jsonBuf.Bytes(), &jsonOutput))
+	var jsonPaths []string
+	for _, f := range jsonOutput.Files {
+		jsonPaths = append(jsonPaths, f.Name)
+	}
+	var sarifOutput sarifReport
+	require.NoError(t, json.Unmarshal(sarifBuf.Bytes(), &sarifOutput))
+	var sarifPaths []string
+	for _, result := range sarifOutput.Runs[0].Results {
+		for _, location := range result.Locations {
+			sarifPaths = append(sarifPaths, location.PhysicalLocation.ArtifactLocation.URI)
+		}
+	}
+	assert.Equal(t, []string{"mymodule/main.go"}, jsonPaths)
+	assert.Equal(t, jsonPaths, sarifPaths)
+}
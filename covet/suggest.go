@@ -0,0 +1,173 @@
+package covet
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/hack-pad/hackpadfs"
+	"github.com/johnstarich/go/covet/internal/funcs"
+	"github.com/pkg/errors"
+)
+
+// TestSuggestion points at where to add tests for a Function, like "add a case to TestHandle in server_test.go"
+type TestSuggestion struct {
+	Function Function
+	// TestFile is the path to the suggested _test.go file, relative to the diff like Function.File
+	TestFile string
+	// NewTestFile is true if TestFile does not exist yet
+	NewTestFile bool
+	// Tests are the closest existing test functions in TestFile, like "TestHandle". Empty if none are related.
+	Tests []string
+}
+
+const (
+	testFileSuffix   = "_test.go"
+	maxSuggestTests  = 3
+	testMatchNone    = 0
+	testMatchPartial = 1
+	testMatchName    = 2
+	testMatchFull    = 3
+)
+
+// SuggestTests returns a TestSuggestion for each function in 'functions' outside of test files.
+// Test files are read from Options.FS in each function's directory. The function's matching _test.go file is preferred, like server_test.go for server.go.
+func (c *Covet) SuggestTests(functions []Function) ([]TestSuggestion, error) {
+	testsByDir := make(map[string]map[string][]string)
+	var suggestions []TestSuggestion
+	for _, function := range functions {
+		if strings.HasSuffix(function.File, testFileSuffix) {
+			continue
+		}
+		dir := path.Dir(function.File)
+		tests, cached := testsByDir[dir]
+		if !cached {
+			var err error
+			tests, err = c.findTests(dir)
+			if err != nil {
+				return nil, err
+			}
+			testsByDir[dir] = tests
+		}
+		suggestions = append(suggestions, suggestTest(function, tests))
+	}
+	return suggestions, nil
+}
+
+// findTests returns test function names in each _test.go file in 'dir', keyed by file path
+func (c *Covet) findTests(dir string) (map[string][]string, error) {
+	entries, err := hackpadfs.ReadDir(c.options.FS, path.Join(c.coverageBaseDir, dir))
+	if err != nil {
+		if errors.Is(err, hackpadfs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	tests := make(map[string][]string)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), testFileSuffix) {
+			continue
+		}
+		name := path.Join(dir, entry.Name())
+		contents, err := hackpadfs.ReadFile(c.options.FS, path.Join(c.coverageBaseDir, name))
+		if err != nil {
+			return nil, err
+		}
+		decls, err := funcs.Find(contents)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse Go file %q", name)
+		}
+		var fileTests []string
+		for _, decl := range decls {
+			if decl.Receiver == "" && strings.HasPrefix(decl.Name, "Test") && decl.Name != "TestMain" {
+				fileTests = append(fileTests, decl.Name)
+			}
+		}
+		tests[name] = fileTests
+	}
+	return tests, nil
+}
+
+// suggestTest picks the test file with the closest test names to 'function'. Ties prefer the function's matching _test.go file.
+func suggestTest(function Function, testsByFile map[string][]string) TestSuggestion {
+	matchingTestFile := strings.TrimSuffix(function.File, ".go") + testFileSuffix
+	_, matchingExists := testsByFile[matchingTestFile]
+	suggestion := TestSuggestion{
+		Function:    function,
+		TestFile:    matchingTestFile,
+		NewTestFile: !matchingExists,
+	}
+
+	testFiles := make([]string, 0, len(testsByFile))
+	for name := range testsByFile {
+		testFiles = append(testFiles, name)
+	}
+	sort.Slice(testFiles, func(a, b int) bool {
+		if (testFiles[a] == matchingTestFile) != (testFiles[b] == matchingTestFile) {
+			return testFiles[a] == matchingTestFile
+		}
+		return testFiles[a] < testFiles[b]
+	})
+	bestScore := testMatchNone
+	for _, testFile := range testFiles {
+		score, tests := closestTests(function, testsByFile[testFile])
+		if score > bestScore {
+			bestScore = score
+			suggestion.TestFile = testFile
+			suggestion.NewTestFile = false
+			suggestion.Tests = tests
+		}
+	}
+	return suggestion
+}
+
+// closestTests returns the best match score for 'function' and the tests with that score, up to maxSuggestTests
+func closestTests(function Function, tests []string) (int, []string) {
+	bestScore := testMatchNone
+	var bestTests []string
+	for _, test := range tests {
+		score := testMatchScore(function, test)
+		switch {
+		case score == testMatchNone || score < bestScore:
+		case score > bestScore:
+			bestScore = score
+			bestTests = []string{test}
+		case len(bestTests) < maxSuggestTests:
+			bestTests = append(bestTests, test)
+		}
+	}
+	return bestScore, bestTests
+}
+
+// testMatchScore rates how closely a test's name matches a function.
+// For example, method (*Server).Handle fully matches TestServerHandle or TestServer_Handle, matches the name of TestHandle, and partially matches TestHandleError.
+func testMatchScore(function Function, test string) int {
+	testName := normalizeTestName(strings.TrimPrefix(test, "Test"))
+	name := normalizeTestName(function.Name)
+	receiver := normalizeTestName(receiverTypeName(function.Receiver))
+	switch {
+	case testName == "":
+		return testMatchNone
+	case receiver != "" && testName == receiver+name:
+		return testMatchFull
+	case testName == name:
+		return testMatchName
+	case strings.Contains(testName, name):
+		return testMatchPartial
+	default:
+		return testMatchNone
+	}
+}
+
+func normalizeTestName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// receiverTypeName returns the base type name of a method receiver, like "Server" for "*Server[T]"
+func receiverTypeName(receiver string) string {
+	receiver = strings.TrimPrefix(receiver, "*")
+	if i := strings.IndexRune(receiver, '['); i != -1 {
+		receiver = receiver[:i]
+	}
+	return receiver
+}
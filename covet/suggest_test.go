@@ -0,0 +1,140 @@
+package covet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/johnstarich/go/covet/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestTest(t *testing.T) {
+	t.Parallel()
+	handle := Function{File: "api/server.go", Name: "Handle", Receiver: "*Server"}
+	for _, tc := range []struct {
+		description string
+		function    Function
+		testsByFile map[string][]string
+		expect      TestSuggestion
+	}{
+		{
+			description: "no test files",
+			function:    handle,
+			expect:      TestSuggestion{Function: handle, TestFile: "api/server_test.go", NewTestFile: true},
+		},
+		{
+			description: "matching test file without related tests",
+			function:    handle,
+			testsByFile: map[string][]string{
+				"api/server_test.go": {"TestNew"},
+			},
+			expect: TestSuggestion{Function: handle, TestFile: "api/server_test.go"},
+		},
+		{
+			description: "receiver and name match",
+			function:    handle,
+			testsByFile: map[string][]string{
+				"api/server_test.go": {"TestHandleError", "TestServer_Handle", "TestHandle"},
+			},
+			expect: TestSuggestion{Function: handle, TestFile: "api/server_test.go", Tests: []string{"TestServer_Handle"}},
+		},
+		{
+			description: "partial matches",
+			function:    handle,
+			testsByFile: map[string][]string{
+				"api/server_test.go": {"TestHandleError", "TestNew", "TestHandleTimeout"},
+			},
+			expect: TestSuggestion{Function: handle, TestFile: "api/server_test.go", Tests: []string{"TestHandleError", "TestHandleTimeout"}},
+		},
+		{
+			description: "closer match in another file",
+			function:    Function{File: "api/server.go", Name: "parseArgs"},
+			testsByFile: map[string][]string{
+				"api/args_test.go":   {"TestParseArgs"},
+				"api/server_test.go": {"TestParseArgsError"},
+			},
+			expect: TestSuggestion{Function: Function{File: "api/server.go", Name: "parseArgs"}, TestFile: "api/args_test.go", Tests: []string{"TestParseArgs"}},
+		},
+		{
+			description: "ties prefer matching test file",
+			function:    Function{File: "api/server.go", Name: "parseArgs"},
+			testsByFile: map[string][]string{
+				"api/args_test.go":   {"TestParseArgs"},
+				"api/server_test.go": {"TestParseArgs"},
+			},
+			expect: TestSuggestion{Function: Function{File: "api/server.go", Name: "parseArgs"}, TestFile: "api/server_test.go", Tests: []string{"TestParseArgs"}},
+		},
+	} {
+		tc := tc // enable parallel sub-tests
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expect, suggestTest(tc.function, tc.testsByFile))
+		})
+	}
+}
+
+func TestReportSummaryTestSuggestions(t *testing.T) {
+	t.Parallel()
+	fs := testhelpers.FSWithFiles(t, map[string]string{
+		"go.mod": `module github.com/org/repo`,
+		"server.go": `package repo
+
+func (s *Server) Handle() {
+	println(1)
+}
+
+func run() {
+	println(2)
+}
+`,
+		"server_test.go": `package repo
+
+func TestMain(m *testing.M) {}
+
+func TestHandle(t *testing.T) {}
+
+func TestHandleError(t *testing.T) {}
+`,
+		"cover.out": `
+mode: set
+github.com/org/repo/server.go:4.1,4.11 1 0
+github.com/org/repo/server.go:8.1,8.11 1 0
+`,
+	})
+	diff := `
+diff --git a/server.go b/server.go
+index 0000000..1111111 100644
+--- a/server.go
++++ b/server.go
+@@ -0,0 +1,9 @@
++package repo
++
++func (s *Server) Handle() {
++	println(1)
++}
++
++func run() {
++	println(2)
++}
+`
+	cov, err := Parse(Options{
+		FS:             fs,
+		Diff:           strings.NewReader(strings.TrimSpace(diff)),
+		DiffBaseDir:    ".",
+		GoCoveragePath: "cover.out",
+	})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, cov.ReportSummaryColorTerminal(&buf, ReportSummaryOptions{
+		Target:       90,
+		SuggestTests: true,
+	}))
+	assert.Contains(t, buf.String(), `
+Suggested tests:
+- (*Server).Handle in server.go: add a case to TestHandle in server_test.go
+- run in server.go: add a test to server_test.go
+`)
+}
@@ -10,6 +10,21 @@ import (
 // ReportSummaryOptions contains summary report options
 type ReportSummaryOptions struct {
 	Target uint
+	// Functions includes a table of Go functions with uncovered lines in the prioritized files
+	Functions bool
+	// SuggestTests includes where to add tests for each Go function with uncovered lines in the prioritized files. See Covet.SuggestTests.
+	SuggestTests bool
+	// GroupByPackage includes a table of diff coverage for each package in the diff
+	GroupByPackage bool
+	// GroupByOwner includes a table of diff coverage for each code owner in the diff. See Covet.DiffCoverageByOwner.
+	GroupByOwner bool
+	// MinHits lists files with covered lines which ran fewer than MinHits times, using hit counts from 'count' or 'atomic' mode Go coverage profiles.
+	// Zero disables the weakly covered lines table.
+	MinHits uint
+	// Mutants adds mutation testing results, including a table of survived mutants. See Covet.Mutants.
+	Mutants []MutantResult
+	// Baseline compares total and per-package coverage against a previously recorded Snapshot, like one from the base branch
+	Baseline *Snapshot
 }
 
 // ReportSummaryMarkdown writes a markdown report to 'w'.
@@ -26,10 +41,184 @@ func (c *Covet) ReportSummaryColorTerminal(w io.Writer, options ReportSummaryOpt
 func (c *Covet) reportSummary(w io.Writer, options ReportSummaryOptions, format summary.Format) error {
 	uncoveredFiles := c.PriorityUncoveredFiles(options.Target)
 	report := summary.New(uncoveredFiles, options.Target, format)
+	if (options.Functions || options.SuggestTests) && len(uncoveredFiles) > 0 {
+		functions, err := c.priorityUncoveredFunctions(uncoveredFiles)
+		if err != nil {
+			return err
+		}
+		if options.Functions {
+			if functionsReport := summary.NewFunctions(functions, format); functionsReport != "" {
+				report += "\n" + functionsReport
+			}
+		}
+		if options.SuggestTests {
+			suggestionsReport, err := c.testSuggestionsSummary(functions, format)
+			if err != nil {
+				return err
+			}
+			report += suggestionsReport
+		}
+	}
+	report += c.packageSummary(format)
+	if options.GroupByPackage {
+		report += c.groupSummary("Diff coverage by package:", "Package", c.DiffCoverageByPackage(), format)
+	}
+	if options.GroupByOwner {
+		groups, err := c.DiffCoverageByOwner()
+		if err != nil {
+			return err
+		}
+		report += c.groupSummary("Diff coverage by owner:", "Owner", groups, format)
+	}
+	report += c.labelSummary(format)
+	if options.MinHits > 0 {
+		report += c.weaklyCoveredSummary(options.MinHits, format)
+	}
+	if options.Mutants != nil {
+		report += mutantsSummary(options.Mutants, format)
+	}
+	if options.Baseline != nil {
+		report += "\n" + c.baselineSummary(*options.Baseline, format)
+	}
 	_, err := io.WriteString(w, report)
 	return err
 }
 
+func (c *Covet) testSuggestionsSummary(functions []Function, format summary.Format) (string, error) {
+	suggestions, err := c.SuggestTests(functions)
+	if err != nil {
+		return "", err
+	}
+	summarySuggestions := make([]summary.TestSuggestion, 0, len(suggestions))
+	for _, s := range suggestions {
+		summarySuggestions = append(summarySuggestions, summary.TestSuggestion{
+			Function:    s.Function,
+			TestFile:    s.TestFile,
+			NewTestFile: s.NewTestFile,
+			Tests:       s.Tests,
+		})
+	}
+	if suggestionsReport := summary.NewTestSuggestions(summarySuggestions, format); suggestionsReport != "" {
+		return "\n" + suggestionsReport, nil
+	}
+	return "", nil
+}
+
+func (c *Covet) groupSummary(title, column string, groups []Group, format summary.Format) string {
+	summaryGroups := make([]summary.Group, 0, len(groups))
+	for _, g := range groups {
+		name := g.Name
+		if name == "" {
+			name = "(no owner)"
+		}
+		summaryGroups = append(summaryGroups, summary.Group{
+			Name:      name,
+			Covered:   g.Covered,
+			Uncovered: g.Uncovered,
+		})
+	}
+	if groupsReport := summary.NewGroups(title, column, summaryGroups, format); groupsReport != "" {
+		return "\n" + groupsReport
+	}
+	return ""
+}
+
+// packageSummary returns a table of diff coverage and total coverage for each package in the diff, or an empty string if there are none
+func (c *Covet) packageSummary(format summary.Format) string {
+	totals := make(map[string]float64)
+	for _, pkg := range c.PackageCoverage() {
+		totals[pkg.Name] = pkg.Coverage()
+	}
+	var packages []summary.Package
+	for _, g := range c.DiffCoverageByPackage() {
+		pkg := summary.Package{
+			Name:      g.Name,
+			Covered:   g.Covered,
+			Uncovered: g.Uncovered,
+		}
+		if total, ok := totals[g.Name]; ok {
+			pkg.Total = &total
+		}
+		packages = append(packages, pkg)
+	}
+	if packagesReport := summary.NewPackages(packages, format); packagesReport != "" {
+		return "\n" + packagesReport
+	}
+	return ""
+}
+
+// labelSummary returns a table of diff coverage by label, or an empty string if no coverage profiles are labelled
+func (c *Covet) labelSummary(format summary.Format) string {
+	var labels []summary.Label
+	for _, l := range c.DiffCoverageByLabel() {
+		labels = append(labels, summary.Label{
+			Name:        l.Label,
+			Covered:     l.Covered,
+			Uncovered:   l.Uncovered,
+			OnlyCovered: l.OnlyCovered,
+		})
+	}
+	if labelsReport := summary.NewLabels(labels, format); labelsReport != "" {
+		return "\n" + labelsReport
+	}
+	return ""
+}
+
+func (c *Covet) baselineSummary(baseline Snapshot, format summary.Format) string {
+	current := c.Snapshot("")
+	total := summary.PackageChange{
+		Coverage: current.Coverage(),
+		Baseline: baseline.Coverage(),
+	}
+	var packages []summary.PackageChange
+	for _, pkg := range current.Packages {
+		baselinePkg, exists := baseline.Package(pkg.Name)
+		if exists && pkg.Coverage() < baselinePkg.Coverage() {
+			packages = append(packages, summary.PackageChange{
+				Name:     pkg.Name,
+				Coverage: pkg.Coverage(),
+				Baseline: baselinePkg.Coverage(),
+			})
+		}
+	}
+	sort.SliceStable(packages, func(a, b int) bool {
+		return packages[a].Coverage-packages[a].Baseline < packages[b].Coverage-packages[b].Baseline
+	})
+	return summary.NewBaseline(shortCommit(baseline.Commit), total, packages, format)
+}
+
+// shortCommit abbreviates full git commit hashes, like "0a1b2c3"
+func shortCommit(commit string) string {
+	const (
+		fullHashLength  = 40
+		shortHashLength = 7
+	)
+	if len(commit) == fullHashLength {
+		return commit[:shortHashLength]
+	}
+	return commit
+}
+
+// priorityUncoveredFunctions returns functions with uncovered lines in 'files', sorted by most uncovered lines
+func (c *Covet) priorityUncoveredFunctions(files []File) ([]Function, error) {
+	var functions []Function
+	for _, f := range files {
+		fileFunctions, err := c.fileFunctions(f)
+		if err != nil {
+			return nil, err
+		}
+		for _, function := range fileFunctions {
+			if function.Uncovered > 0 {
+				functions = append(functions, function)
+			}
+		}
+	}
+	sort.SliceStable(functions, func(a, b int) bool {
+		return functions[a].Uncovered > functions[b].Uncovered
+	})
+	return functions, nil
+}
+
 // PriorityUncoveredFiles returns a list of Files prioritized by the largest uncovered sections of the diff.
 // The list contains just enough Files worth of uncovered lines necessary to meet the provided 'target' coverage.
 func (c *Covet) PriorityUncoveredFiles(target uint) []File {
@@ -40,10 +229,10 @@ func (c *Covet) PriorityUncoveredFiles(target uint) []File {
 	return findReportableUncoveredFiles(coveredFiles, targetPercent, current)
 }
 
-func findReportableUncoveredFiles(coveredFiles []File, target, current float64) []File {
-	// sort by highest uncovered line count
-	sort.Slice(coveredFiles, func(aIndex, bIndex int) bool {
-		a, b := coveredFiles[aIndex], coveredFiles[bIndex]
+// sortByPriority sorts files by highest uncovered line count, then by name
+func sortByPriority(files []File) {
+	sort.Slice(files, func(aIndex, bIndex int) bool {
+		a, b := files[aIndex], files[bIndex]
 		switch {
 		case a.Uncovered != b.Uncovered:
 			return a.Uncovered > b.Uncovered
@@ -51,6 +240,10 @@ func findReportableUncoveredFiles(coveredFiles []File, target, current float64)
 			return a.Name < b.Name
 		}
 	})
+}
+
+func findReportableUncoveredFiles(coveredFiles []File, target, current float64) []File {
+	sortByPriority(coveredFiles)
 
 	var uncoveredFiles []File
 	// find minimum number of covered lines required to hit target
@@ -43,6 +43,11 @@ Diff coverage is below target. Add tests for these files:
 |  | Lines | Coverage | File |
 | --- |:---:| --- | --- |
 | 🟠 | ~~1/2~~ | ~~ 50.0% ██▌  ~~ | covet.go |
+
+Total coverage of packages in the diff:
+|  | Diff | Total | Package |
+| --- |:---:|:---:| --- |
+| 🟠 | ~~ 50.0%~~ | ~~ 50.0%~~ | . |
 `,
 			expectTerminal: `
 Diff coverage is below target. Add tests for these files:
@@ -51,6 +56,13 @@ Diff coverage is below target. Add tests for these files:
 ├───────┼──────────────┼──────────┤
 │  1/2  │  50.0% ██▌   │ covet.go │
 └───────┴──────────────┴──────────┘
+
+Total coverage of packages in the diff:
+┌────────┬────────┬─────────┐
+│ DIFF   │ TOTAL  │ PACKAGE │
+├────────┼────────┼─────────┤
+│  50.0% │  50.0% │ .       │
+└────────┴────────┴─────────┘
 `,
 		},
 		{
@@ -74,6 +86,11 @@ Diff coverage is below target. Add tests for these files:
 |  | Lines | Coverage | File |
 | --- |:---:| --- | --- |
 | 🔴 | ~~0/2~~ | ~~  0.0% ▏    ~~ | covet.go |
+
+Total coverage of packages in the diff:
+|  | Diff | Total | Package |
+| --- |:---:|:---:| --- |
+| 🔴 | ~~  0.0%~~ | ~~  0.0%~~ | . |
 `,
 			expectTerminal: `
 Diff coverage is below target. Add tests for these files:
@@ -82,6 +99,13 @@ Diff coverage is below target. Add tests for these files:
 ├───────┼──────────────┼──────────┤
 │  0/2  │   0.0% ▏     │ covet.go │
 └───────┴──────────────┴──────────┘
+
+Total coverage of packages in the diff:
+┌────────┬────────┬─────────┐
+│ DIFF   │ TOTAL  │ PACKAGE │
+├────────┼────────┼─────────┤
+│  0.0%  │  0.0%  │ .       │
+└────────┴────────┴─────────┘
 `,
 		},
 	} {
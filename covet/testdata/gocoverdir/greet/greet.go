@@ -0,0 +1,14 @@
+package greet
+
+// Hello returns a greeting
+func Hello(name string) string {
+	if name == "" {
+		return "Hello, world!"
+	}
+	return "Hello, " + name + "!"
+}
+
+// Goodbye returns a farewell
+func Goodbye(name string) string {
+	return "Goodbye, " + name + "!"
+}
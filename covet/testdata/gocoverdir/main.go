@@ -0,0 +1,14 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"example.com/app/greet"
+)
+
+func main() {
+	for i := 0; i < 3; i++ {
+		fmt.Println(greet.Hello(os.Getenv("NAME")))
+	}
+}
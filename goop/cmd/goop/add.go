@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"path"
+	"strings"
 
 	"github.com/hack-pad/hackpadfs"
 	"github.com/johnstarich/go/pipe"
@@ -60,11 +61,23 @@ var addPipe = pipe.New(pipe.Options{}).
 		}
 		// Script shebang should run as follows:
 		// goop exec --name foo --encoded-package abc123== -- ~/.config/goop/bin/foo arg1 arg2 ...
-		script := fmt.Sprintf("goop exec --encoded-name %s --encoded-package %s --\n",
-			// shebangs do not support spaces or quotes, so encode all variables
-			encode(args.Name),
-			encode(args.Package.Path),
-		)
+		// shebangs do not support spaces or quotes, so encode all variables
+		scriptArgs := []string{
+			"goop", "exec",
+			"--encoded-name", encode(args.Name),
+			"--encoded-package", encode(args.Package.Path),
+		}
+		buildOptions := args.Package.BuildOptions
+		if buildOptions.Tags != "" {
+			scriptArgs = append(scriptArgs, "--encoded-tags", encode(buildOptions.Tags))
+		}
+		if buildOptions.LDFlags != "" {
+			scriptArgs = append(scriptArgs, "--encoded-ldflags", encode(buildOptions.LDFlags))
+		}
+		for _, keyValue := range buildOptions.Env {
+			scriptArgs = append(scriptArgs, "--encoded-env", encode(keyValue))
+		}
+		script := strings.Join(scriptArgs, " ") + " --\n"
 		err := hackpadfs.WriteFullFile(args.App.fs, scriptPath, []byte(makeShebang(script)), binPermission)
 		return scriptPath, err
 	})
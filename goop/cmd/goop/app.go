@@ -3,10 +3,12 @@ package main
 
 import (
 	"context"
+	"debug/buildinfo"
 	"io"
 	"os"
 	"os/exec"
 	"path"
+	"runtime/debug"
 	"strings"
 
 	"github.com/hack-pad/hackpadfs"
@@ -31,6 +33,7 @@ type App struct {
 	getEnv          func(string) string
 	lookPath        func(string) (string, error)
 	outWriter       io.Writer
+	readBuildInfo   func(string) (*debug.BuildInfo, error)
 	runCmd          func(*exec.Cmd) error
 	staticBinDir    string
 	staticCacheDir  string
@@ -71,6 +74,7 @@ func newApp(outWriter, errWriter io.Writer) (App, error) {
 		getEnv:          os.Getenv,
 		lookPath:        exec.LookPath,
 		outWriter:       outWriter,
+		readBuildInfo:   buildinfo.ReadFile,
 		runCmd:          runCmd,
 		staticBinDir:    path.Join(configDir, appName, configBin),
 		staticCacheDir:  path.Join(cacheDir, appName),
@@ -80,7 +84,9 @@ func newApp(outWriter, errWriter io.Writer) (App, error) {
 
 func runCmd(cmd *exec.Cmd) error {
 	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
+	if cmd.Stdout == nil {
+		cmd.Stdout = os.Stdout
+	}
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
@@ -120,9 +126,15 @@ Includes:
 	infoCommand := &cobra.Command{
 		Use:   "info",
 		Short: "Shows general information for currently installed modules.",
-		RunE:  a.info,
+		Long: `Shows general information for currently installed modules.
+
+Lists each command's package, whether it is a local or remote module, its version pinned in the lock file, when it was last built, its binary size, and whether it is out of date. Local modules are out of date when their source changed since the last build. Remote modules are out of date when their binary does not match the lock file, which 'goop sync' fixes.
+
+Checking local modules runs 'go list' for each one with changed files since its last build, so this may take a moment. If a check fails, a warning is printed and the status is unknown.`,
+		RunE: a.info,
 	}
 	rootCommand.AddCommand(infoCommand)
+	infoCommand.Flags().Bool("json", false, "Print information as JSON.")
 
 	installCommand := &cobra.Command{
 		Use:   "install",
@@ -131,15 +143,54 @@ Includes:
 
 For example, run 'goop install -p github.com/johnstarich/go/covet/cmd/covet' to build and install the covet tool, then run 'covet --help' to execute the covet command.
 
-To run an installed module, use its name on the command-line. For local modules, Goop automatically triggers a rebuild when the command is out of date. This means local scripts can be updated and used immediately.
+To run an installed module, use its name on the command-line. For local modules, Goop automatically triggers a rebuild when the command is out of date. A build is out of date when the contents of its Go files, embedded files, go.mod, or go.sum change, including those of go.work members and local 'replace' targets. This means local scripts can be updated and used immediately.
+
+Set the GOOP_BIN environment variable to select a custom command location. This is helpful when sharing commands across multiple machines with a tool like OneDrive, iCloud Drive, or Google Drive.
 
-Set the GOOP_BIN environment variable to select a custom command location. This is helpful when sharing commands across multiple machines with a tool like OneDrive, iCloud Drive, or Google Drive.`,
+Each install records the command's resolved module version and checksum in the bin directory's goop.lock file. Run 'goop sync' on another machine to install the same versions.
+
+Build options like --tags, --ldflags, and --env are saved with the command and the lock file, then applied on every rebuild. For example, 'goop install -p github.com/org/repo/cmd/tool --env CGO_ENABLED=0 --env GOPRIVATE=github.com/org' installs a private tool without cgo.`,
 		RunE: a.install,
 	}
 	rootCommand.AddCommand(installCommand)
 	installCommand.Flags().StringP("package", "p", "", "The package pattern to install. Can be a local or remote module. Remote modules may use a '@version' like '-p github.com/johnstarich/go/covet/cmd/covet@latest'. Local modules must use absolute paths without a '@version' like '-p /path/to/my/module'.")
 	panicIfErr(installCommand.MarkFlagRequired("package"))
 	installCommand.Flags().String("name", "", "An optional name for the command when installed. For example, 'goop install -p github.com/johnstarich/go/covet/cmd/covet -name foo' and then run 'foo' as the command. Defaults to the package base name.")
+	installCommand.Flags().String("tags", "", "A comma-separated list of build tags, like '--tags netgo,osusergo'. Applied on every rebuild.")
+	installCommand.Flags().String("ldflags", "", "Linker flags, like '--ldflags \"-X main.version=v1.2.3\"'. Applied on every rebuild.")
+	installCommand.Flags().StringArray("env", nil, "An environment variable for builds formatted as KEY=VALUE, like '--env CGO_ENABLED=0'. Repeat to set more than one. Applied on every rebuild.")
+
+	syncCommand := &cobra.Command{
+		Use:   "sync",
+		Short: "Installs every command in the lock file at its locked version.",
+		Long: `Installs every command in the bin directory's goop.lock file at its locked version.
+
+Run 'goop sync' on a new machine with a shared GOOP_BIN to install the exact module versions used everywhere else. Commands already installed at the locked version are skipped. Remote modules are verified against the locked go.sum checksum, and local modules rebuild if they are out of date.`,
+		RunE: a.sync,
+	}
+	rootCommand.AddCommand(syncCommand)
+
+	outdatedCommand := &cobra.Command{
+		Use:   "outdated",
+		Short: "Lists installed remote modules with newer versions available.",
+		Long: `Lists installed remote modules with newer versions available.
+
+Queries the module proxy set in the GOPROXY environment variable for each command in the lock file. Defaults to https://proxy.golang.org. Local modules are skipped, since they rebuild automatically.`,
+		RunE: a.outdated,
+	}
+	rootCommand.AddCommand(outdatedCommand)
+
+	upgradeCommand := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrades installed remote modules to their newest versions.",
+		Long: `Upgrades installed remote modules to their newest versions, then records the new versions in the lock file.
+
+For example, run 'goop upgrade --name covet --constraint ^v1.2' to upgrade covet to the newest v1 version, at least v1.2.0.`,
+		RunE: a.upgrade,
+	}
+	rootCommand.AddCommand(upgradeCommand)
+	upgradeCommand.Flags().String("name", "", "The name of the command to upgrade. Defaults to all remote module commands.")
+	upgradeCommand.Flags().String("constraint", "", "An optional version constraint for the new version. Separate multiple constraints with commas to match all of them. Examples: 'v1' or 'v1.2' to match a version prefix, '>=v1.2.3,<v2', '^v1.2.3' for compatible versions, and '~v1.2.3' for patch versions.")
 
 	removeCommand := &cobra.Command{
 		Use:   "rm",
@@ -164,6 +215,9 @@ Set the GOOP_BIN environment variable to select a custom command location. This
 	panicIfErr(execCommand.MarkFlagRequired("encoded-name"))
 	execCommand.Flags().String("encoded-package", "", "")
 	panicIfErr(execCommand.MarkFlagRequired("encoded-package"))
+	execCommand.Flags().String("encoded-tags", "", "")
+	execCommand.Flags().String("encoded-ldflags", "", "")
+	execCommand.Flags().StringArray("encoded-env", nil, "")
 
 	rootCommand.SetArgs(args)
 	err := rootCommand.ExecuteContext(context.Background())
@@ -5,6 +5,7 @@ import (
 	"os/exec"
 	"path"
 	"runtime"
+	"runtime/debug"
 	"strings"
 	"testing"
 
@@ -16,7 +17,8 @@ import (
 )
 
 type testAppOptions struct {
-	runCmd func(*TestApp, *exec.Cmd) error
+	runCmd        func(*TestApp, *exec.Cmd) error
+	readBuildInfo func(string) (*debug.BuildInfo, error)
 }
 
 type TestApp struct {
@@ -43,6 +45,7 @@ func newTestApp(t *testing.T, options testAppOptions) *TestApp {
 			return path.Join("bin", name), nil
 		},
 		outWriter:       newTestWriter(t),
+		readBuildInfo:   testApp.readBuildInfo,
 		runCmd:          testApp.runCmd,
 		staticBinDir:    "bin",
 		staticCacheDir:  "cache",
@@ -65,11 +68,20 @@ func (t *TestApp) runCmd(cmd *exec.Cmd) error {
 		t.testingT.Fatal("No runCmd provided")
 	}
 	cmd.Stdin = nil
-	cmd.Stdout = t.outWriter
+	if cmd.Stdout == nil {
+		cmd.Stdout = t.outWriter
+	}
 	cmd.Stderr = t.errWriter
 	return t.options.runCmd(t, cmd)
 }
 
+func (t *TestApp) readBuildInfo(path string) (*debug.BuildInfo, error) {
+	if t.options.readBuildInfo == nil {
+		return &debug.BuildInfo{Main: debug.Module{Path: "github.com/johnstarich/go/goop", Version: "v1.0.0", Sum: "h1:abc="}}, nil
+	}
+	return t.options.readBuildInfo(path)
+}
+
 type testWriter struct {
 	testingT *testing.T
 	out      *bytes.Buffer
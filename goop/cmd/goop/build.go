@@ -3,12 +3,10 @@ package main
 import (
 	"context"
 	"fmt"
-	"os"
 	"os/exec"
 	"path"
 	"runtime"
 	"strings"
-	"time"
 
 	"github.com/hack-pad/hackpadfs"
 	"github.com/johnstarich/go/pipe"
@@ -20,23 +18,45 @@ func (a App) build(ctx context.Context, name string, pkg Package, alwaysBuild bo
 }
 
 func (a App) buildOS(ctx context.Context, name string, pkg Package, alwaysBuild bool, goos string) (string, error) {
-	desiredPath := path.Join(a.packageInstallDir(name), name) + systemExt(goos)
-	info, err := hackpadfs.Stat(a.fs, desiredPath)
-	if err != nil && !errors.Is(err, hackpadfs.ErrNotExist) {
-		return "", err
+	desiredPath := a.installedBinaryPath(name, goos)
+	info, statErr := hackpadfs.Stat(a.fs, desiredPath)
+	if statErr != nil && !errors.Is(statErr, hackpadfs.ErrNotExist) {
+		return "", statErr
 	}
-	if err == nil && info.Mode().IsRegular() && !alwaysBuild {
-		shouldRebuild, err := a.shouldRebuild(info, pkg)
+	var fingerprint *fingerprintRecord
+	if statErr == nil && info.Mode().IsRegular() && !alwaysBuild {
+		shouldRebuild, newFingerprint, err := a.checkFingerprint(ctx, name, pkg)
 		if err != nil {
 			return "", err
 		}
 		if !shouldRebuild {
+			if newFingerprint != nil {
+				// files were touched without changing the build, record them so the next check skips 'go list'
+				return desiredPath, a.writeFingerprint(name, *newFingerprint)
+			}
 			return desiredPath, nil
 		}
+		fingerprint = newFingerprint
 	}
 
 	fmt.Fprintf(a.errWriter, "Building %q...\n", pkg.Path)
-	return desiredPath, a.buildAtPath(ctx, name, pkg, desiredPath)
+	if err := a.buildAtPath(ctx, name, pkg, desiredPath); err != nil {
+		return desiredPath, err
+	}
+	if fingerprint == nil {
+		// new and forced builds skip the check above, so fingerprint only after a successful build
+		newFingerprint, err := a.buildFingerprint(ctx, pkg)
+		if err != nil {
+			return desiredPath, err
+		}
+		fingerprint = &newFingerprint
+	}
+	return desiredPath, a.writeFingerprint(name, *fingerprint)
+}
+
+// installedBinaryPath returns the path of command 'name's binary, built for 'goos'
+func (a App) installedBinaryPath(name, goos string) string {
+	return path.Join(a.packageInstallDir(name), name) + systemExt(goos)
 }
 
 func systemExt(goos string) string {
@@ -69,11 +89,12 @@ var buildAtPathPipe = pipe.New(pipe.Options{}).
 	}).
 	Append(func(args buildAtPathArgs, gobin string) (buildAtPathArgs, error) {
 		workingDir, installPattern := args.App.packageInstallPaths(args.Package)
-		cmdArgs := []string{"install", installPattern}
-		fmt.Fprintf(args.App.errWriter, "Env: PWD=%q GOBIN=%q\nRunning 'go %s'...\n", workingDir, gobin, strings.Join(cmdArgs, " "))
+		buildOptions := args.Package.BuildOptions
+		cmdArgs := append(append([]string{"install"}, buildOptions.goFlags()...), installPattern)
+		fmt.Fprintf(args.App.errWriter, "Env: PWD=%q GOBIN=%q%s\nRunning 'go %s'...\n", workingDir, gobin, formatEnv(buildOptions.Env), strings.Join(cmdArgs, " "))
 		cmd := exec.CommandContext(args.Context, "go", cmdArgs...)
 		cmd.Dir = workingDir
-		cmd.Env = append(os.Environ(), toEnv(map[string]string{
+		cmd.Env = append(buildOptions.environ(), toEnv(map[string]string{
 			"GOARCH": "",
 			"GOBIN":  gobin,
 			"GOOS":   "",
@@ -110,77 +131,26 @@ func (a App) buildAtPath(ctx context.Context, name string, pkg Package, desiredP
 	return errors.Unwrap(err)
 }
 
-// findBinary returns the first regular file in the directory listing
+// formatEnv returns 'env' with quoted values for display, like ' CGO_ENABLED="0"'
+func formatEnv(env []string) string {
+	var sb strings.Builder
+	for _, keyValue := range env {
+		key, value, _ := strings.Cut(keyValue, "=")
+		fmt.Fprintf(&sb, " %s=%q", key, value)
+	}
+	return sb.String()
+}
+
+// findBinary returns the first regular file in the directory listing, excluding the build fingerprint
 func findBinary(fs hackpadfs.FS, installDir string) (string, bool, error) {
 	dirEntries, err := hackpadfs.ReadDir(fs, installDir)
 	if err != nil && !errors.Is(err, hackpadfs.ErrNotExist) {
 		return "", false, err
 	}
 	for _, entry := range dirEntries {
-		if entry.Type().IsRegular() {
+		if entry.Type().IsRegular() && entry.Name() != fingerprintFileName {
 			return path.Join(installDir, entry.Name()), true, nil
 		}
 	}
 	return "", false, nil
 }
-
-func (a App) shouldRebuild(binaryInfo hackpadfs.FileInfo, pkg Package) (bool, error) {
-	filePath, isFilePath := a.packageFilePath(pkg)
-	if !isFilePath {
-		// remote module, assume up-to-date
-		return false, nil
-	}
-
-	// local module, find out if the build is stale
-	binaryModTime := binaryInfo.ModTime()
-	fsPath, err := a.fromOSPath(filePath)
-	if err != nil {
-		return false, err
-	}
-	moduleRoot, err := moduleRoot(a.fs, fsPath)
-	if err != nil {
-		return false, err
-	}
-	return hasNewerModTime(a.fs, moduleRoot, binaryModTime)
-}
-
-func moduleRoot(fs hackpadfs.FS, p string) (string, error) {
-	const goMod = "go.mod"
-	_, err := hackpadfs.Stat(fs, path.Join(p, goMod))
-	if err == nil {
-		return p, nil
-	}
-	parentPath := path.Dir(p)
-	if parentPath == p {
-		return "", errors.Errorf("go.mod not found for package: %q", p)
-	}
-	root, parentErr := moduleRoot(fs, parentPath)
-	if parentErr != nil {
-		// if parent also failed to find module, return original error
-		return "", err
-	}
-	return root, nil
-}
-
-func hasNewerModTime(fs hackpadfs.FS, root string, baseModTime time.Time) (bool, error) {
-	hasNewerModTime := false
-	err := hackpadfs.WalkDir(fs, root, func(path string, d hackpadfs.DirEntry, err error) error {
-		if hasNewerModTime {
-			return hackpadfs.SkipDir
-		}
-		if err != nil || path == root {
-			return err
-		}
-		info, err := d.Info()
-		if err != nil {
-			return err
-		}
-		modTime := info.ModTime()
-		if modTime.After(baseModTime) {
-			hasNewerModTime = true
-			return hackpadfs.SkipDir
-		}
-		return nil
-	})
-	return hasNewerModTime, err
-}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/hack-pad/hackpadfs"
 	"github.com/hack-pad/hackpadfs/mem"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -186,6 +187,45 @@ func TestBuild(t *testing.T) {
 	})
 }
 
+func TestBuildLocalFingerprint(t *testing.T) {
+	t.Parallel()
+	const name = "foo"
+	var commands [][]string
+	installErr := errors.New("some build error")
+	app := newTestApp(t, testAppOptions{
+		runCmd: func(app *TestApp, cmd *exec.Cmd) error {
+			commands = append(commands, cmd.Args)
+			if cmd.Args[1] == "list" {
+				writeGoList(t, cmd.Stdout, goListPackage{
+					Dir:     "some/module",
+					GoFiles: []string{"main.go"},
+					Module:  &goListModule{Main: true, GoMod: "some/module/go.mod"},
+				})
+				return nil
+			}
+			return installErr
+		},
+	})
+	require.NoError(t, hackpadfs.MkdirAll(app.fs, "some/module", 0o700))
+	require.NoError(t, hackpadfs.WriteFullFile(app.fs, "some/module/main.go", []byte("package main"), 0o600))
+	require.NoError(t, hackpadfs.MkdirAll(app.fs, app.packageInstallDir(name), 0o700))
+	require.NoError(t, hackpadfs.WriteFullFile(app.fs, app.installedBinaryPath(name, runtime.GOOS), nil, 0o700))
+	pkg := Package{Path: "/some/module"}
+
+	_, err := app.build(context.Background(), name, pkg, true)
+	assert.ErrorIs(t, err, installErr)
+	assert.Equal(t, [][]string{{"go", "install", "."}}, commands, "Failed forced builds should not run 'go list'")
+
+	commands = nil
+	installErr = nil
+	_, err = app.build(context.Background(), name, pkg, true)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"go", "install", "."}, goListArgs}, commands, "Forced builds should fingerprint after building")
+	fingerprint, err := app.readFingerprint(name)
+	assert.NoError(t, err)
+	assert.NotNil(t, fingerprint)
+}
+
 func TestFindBinary(t *testing.T) {
 	t.Parallel()
 	t.Run("directory missing", func(t *testing.T) {
@@ -224,4 +264,22 @@ func TestFindBinary(t *testing.T) {
 		assert.True(t, ok)
 		assert.NoError(t, err)
 	})
+
+	t.Run("skips fingerprint", func(t *testing.T) {
+		t.Parallel()
+		fs, err := mem.NewFS()
+		require.NoError(t, err)
+		require.NoError(t, hackpadfs.Mkdir(fs, "foo", 0700))
+		require.NoError(t, hackpadfs.WriteFullFile(fs, "foo/"+fingerprintFileName, nil, 0700))
+
+		_, ok, err := findBinary(fs, "foo")
+		assert.False(t, ok)
+		assert.NoError(t, err)
+
+		require.NoError(t, hackpadfs.WriteFullFile(fs, "foo/bar", nil, 0700))
+		filePath, ok, err := findBinary(fs, "foo")
+		assert.Equal(t, "foo/bar", filePath)
+		assert.True(t, ok)
+		assert.NoError(t, err)
+	})
 }
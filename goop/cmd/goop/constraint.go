@@ -0,0 +1,141 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/mod/semver"
+)
+
+// versionConstraint limits the versions 'goop upgrade' may choose.
+// The zero value allows any version.
+type versionConstraint struct {
+	comparisons []versionComparison
+}
+
+type versionComparison struct {
+	op      string
+	version string
+}
+
+// parseVersionConstraint parses comma-separated comparisons, where every comparison must match. Examples:
+//   - 'v1' or 'v1.2' matches versions with that prefix, like 'v1.2.3'. 'v1.2.3' matches exactly.
+//   - '>=v1.2.3', '>v1.2.3', '<=v1.2.3', '<v2' compare versions.
+//   - '^v1.2.3' is like '>=v1.2.3,<v2'. Before v1, '^v0.2.3' is like '>=v0.2.3,<v0.3'.
+//   - '~v1.2.3' is like '>=v1.2.3,<v1.3'.
+//
+// The 'v' prefix is optional.
+func parseVersionConstraint(constraint string) (versionConstraint, error) {
+	var c versionConstraint
+	if strings.TrimSpace(constraint) == "" {
+		return c, nil
+	}
+	for _, comparison := range strings.Split(constraint, ",") {
+		comparison = strings.TrimSpace(comparison)
+		op := ""
+		for _, prefix := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+			if strings.HasPrefix(comparison, prefix) {
+				op = prefix
+				break
+			}
+		}
+		version := strings.TrimSpace(strings.TrimPrefix(comparison, op))
+		if !strings.HasPrefix(version, "v") {
+			version = "v" + version
+		}
+		if !semver.IsValid(version) || semver.Build(version) != "" {
+			return versionConstraint{}, errors.Errorf("invalid version %q in constraint %q", version, constraint)
+		}
+		switch op {
+		case "^":
+			upper := semver.Major(version)
+			if upper == "v0" {
+				upper = semver.MajorMinor(version)
+			}
+			c.comparisons = append(c.comparisons,
+				versionComparison{op: ">=", version: version},
+				versionComparison{op: "<", version: nextVersion(upper)},
+			)
+		case "~":
+			c.comparisons = append(c.comparisons,
+				versionComparison{op: ">=", version: version},
+				versionComparison{op: "<", version: nextVersion(semver.MajorMinor(version))},
+			)
+		default:
+			c.comparisons = append(c.comparisons, versionComparison{op: op, version: version})
+		}
+	}
+	return c, nil
+}
+
+// nextVersion increments the last component of a major or major.minor version, like 'v1.2' to 'v1.3'
+func nextVersion(version string) string {
+	i := strings.LastIndexAny(version, "v.")
+	n, _ := strconv.Atoi(version[i+1:]) // always valid, since version is from semver.Major or semver.MajorMinor
+	return version[:i+1] + strconv.Itoa(n+1)
+}
+
+// Allows returns true if 'version' matches every comparison in the constraint
+func (c versionConstraint) Allows(version string) bool {
+	for _, comparison := range c.comparisons {
+		if !comparison.allows(version) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c versionComparison) allows(version string) bool {
+	cmp := semver.Compare(version, c.version)
+	switch c.op {
+	case "":
+		return semver.Compare(truncateVersion(version, c.version), c.version) == 0
+	case "=":
+		return cmp == 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	default:
+		return false
+	}
+}
+
+// truncateVersion returns 'version' with as many components as 'like', so 'v1.2.3' is like 'v1' or 'v1.2'
+func truncateVersion(version, like string) string {
+	switch strings.Count(like, ".") {
+	case 0:
+		return semver.Major(version)
+	case 1:
+		return semver.MajorMinor(version)
+	default:
+		return version
+	}
+}
+
+// newestVersion returns the newest version in 'versions' allowed by 'constraint'.
+// Prereleases are only chosen if no release is allowed, like 'go get'.
+func newestVersion(versions []string, constraint versionConstraint) (string, bool) {
+	var newest, newestPrerelease string
+	for _, version := range versions {
+		if !constraint.Allows(version) {
+			continue
+		}
+		if semver.Prerelease(version) != "" {
+			if semver.Compare(version, newestPrerelease) > 0 {
+				newestPrerelease = version
+			}
+		} else if semver.Compare(version, newest) > 0 {
+			newest = version
+		}
+	}
+	if newest == "" {
+		newest = newestPrerelease
+	}
+	return newest, newest != ""
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionConstraint(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		constraint string
+		allows     []string
+		disallows  []string
+		expectErr  string
+	}{
+		{
+			constraint: "",
+			allows:     []string{"v0.0.1", "v1.2.3", "v2.0.0-rc.1"},
+		},
+		{
+			constraint: "v1",
+			allows:     []string{"v1.0.0", "v1.9.9"},
+			disallows:  []string{"v0.9.0", "v2.0.0"},
+		},
+		{
+			constraint: "1.2",
+			allows:     []string{"v1.2.0", "v1.2.9"},
+			disallows:  []string{"v1.1.0", "v1.3.0"},
+		},
+		{
+			constraint: "=v1.2.3",
+			allows:     []string{"v1.2.3"},
+			disallows:  []string{"v1.2.4"},
+		},
+		{
+			constraint: ">=v1.2.3, <v2",
+			allows:     []string{"v1.2.3", "v1.9.0"},
+			disallows:  []string{"v1.2.2", "v2.0.0"},
+		},
+		{
+			constraint: ">v1.2.3,<=v1.3.0",
+			allows:     []string{"v1.2.4", "v1.3.0"},
+			disallows:  []string{"v1.2.3", "v1.3.1"},
+		},
+		{
+			constraint: "^v1.2.3",
+			allows:     []string{"v1.2.3", "v1.10.0"},
+			disallows:  []string{"v1.2.2", "v2.0.0"},
+		},
+		{
+			constraint: "^v0.2.3",
+			allows:     []string{"v0.2.3", "v0.2.9"},
+			disallows:  []string{"v0.3.0"},
+		},
+		{
+			constraint: "~1.2.3",
+			allows:     []string{"v1.2.3", "v1.2.10"},
+			disallows:  []string{"v1.3.0"},
+		},
+		{
+			constraint: ">=latest",
+			expectErr:  `invalid version "vlatest" in constraint ">=latest"`,
+		},
+	} {
+		tc := tc // enable parallel sub-tests
+		t.Run(tc.constraint, func(t *testing.T) {
+			t.Parallel()
+			constraint, err := parseVersionConstraint(tc.constraint)
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			for _, version := range tc.allows {
+				assert.True(t, constraint.Allows(version), "Should allow %s", version)
+			}
+			for _, version := range tc.disallows {
+				assert.False(t, constraint.Allows(version), "Should not allow %s", version)
+			}
+		})
+	}
+}
+
+func TestNewestVersion(t *testing.T) {
+	t.Parallel()
+	versions := []string{"v1.0.0", "v1.1.0", "v1.2.0-rc.1", "v2.0.0", "v3.0.0-beta.1"}
+
+	newest, ok := newestVersion(versions, versionConstraint{})
+	assert.True(t, ok)
+	assert.Equal(t, "v2.0.0", newest)
+
+	constraint, err := parseVersionConstraint("v1")
+	require.NoError(t, err)
+	newest, ok = newestVersion(versions, constraint)
+	assert.True(t, ok)
+	assert.Equal(t, "v1.1.0", newest)
+
+	constraint, err = parseVersionConstraint("v3")
+	require.NoError(t, err)
+	newest, ok = newestVersion(versions, constraint)
+	assert.True(t, ok)
+	assert.Equal(t, "v3.0.0-beta.1", newest)
+
+	constraint, err = parseVersionConstraint("v4")
+	require.NoError(t, err)
+	_, ok = newestVersion(versions, constraint)
+	assert.False(t, ok)
+}
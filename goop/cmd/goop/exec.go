@@ -47,6 +47,16 @@ var execPipe = pipe.New(pipe.Options{}).
 		args.Package, err = args.App.parsePackagePattern(packagePattern)
 		return args, err
 	}).
+	Append(func(args execPipeArgs) (execPipeArgs, error) {
+		var err error
+		args.Package.BuildOptions, err = decodeBuildOptionFlags(args.Cmd)
+		return args, err
+	}).
+	Append(func(args execPipeArgs) (execPipeArgs, error) {
+		var err error
+		args.Package, err = args.App.lockedPackage(args.Name, args.Package)
+		return args, err
+	}).
 	Append(func(args execPipeArgs) (execPipeArgs, string, error) {
 		binaryPath, err := args.App.build(args.Cmd.Context(), args.Name, args.Package, false)
 		return args, binaryPath, err
@@ -94,3 +104,41 @@ func base64DecodeString(s string) (string, error) {
 	b, err := base64.StdEncoding.DecodeString(s)
 	return string(b), err
 }
+
+// decodeBuildOptionFlags returns the build options encoded in exec's flags
+func decodeBuildOptionFlags(cmd *cobra.Command) (BuildOptions, error) {
+	encodedTags, err := cmd.Flags().GetString("encoded-tags")
+	if err != nil {
+		return BuildOptions{}, err
+	}
+	encodedLDFlags, err := cmd.Flags().GetString("encoded-ldflags")
+	if err != nil {
+		return BuildOptions{}, err
+	}
+	encodedEnv, err := cmd.Flags().GetStringArray("encoded-env")
+	if err != nil {
+		return BuildOptions{}, err
+	}
+	return decodeBuildOptions(encodedTags, encodedLDFlags, encodedEnv)
+}
+
+func decodeBuildOptions(encodedTags, encodedLDFlags string, encodedEnv []string) (BuildOptions, error) {
+	var options BuildOptions
+	var err error
+	options.Tags, err = base64DecodeString(encodedTags)
+	if err != nil {
+		return BuildOptions{}, err
+	}
+	options.LDFlags, err = base64DecodeString(encodedLDFlags)
+	if err != nil {
+		return BuildOptions{}, err
+	}
+	for _, encoded := range encodedEnv {
+		keyValue, err := base64DecodeString(encoded)
+		if err != nil {
+			return BuildOptions{}, err
+		}
+		options.Env = append(options.Env, keyValue)
+	}
+	return options, nil
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hack-pad/hackpadfs"
+	"github.com/pkg/errors"
+)
+
+const (
+	// fingerprintFileName is the name of the build fingerprint file inside a command's install directory
+	fingerprintFileName = ".goop-fingerprint"
+	// goWorkEnv is the environment variable which selects the go command's go.work file
+	goWorkEnv = "GOWORK"
+)
+
+// goListPackage is the subset of 'go list -json' output that affects a package's build
+type goListPackage struct {
+	Dir          string
+	Standard     bool
+	Module       *goListModule
+	GoFiles      []string
+	CgoFiles     []string
+	CFiles       []string
+	CXXFiles     []string
+	MFiles       []string
+	HFiles       []string
+	FFiles       []string
+	SFiles       []string
+	SwigFiles    []string
+	SwigCXXFiles []string
+	SysoFiles    []string
+	EmbedFiles   []string
+}
+
+type goListModule struct {
+	Main    bool
+	Version string
+	GoMod   string
+	Replace *goListModule
+}
+
+// goListFields are the JSON fields requested from 'go list', matching goListPackage
+const goListFields = "Dir,Standard,Module,GoFiles,CgoFiles,CFiles,CXXFiles,MFiles,HFiles,FFiles,SFiles,SwigFiles,SwigCXXFiles,SysoFiles,EmbedFiles"
+
+// isLocal returns true if this package's files are on the local file system and could change between builds.
+// Includes main modules, go.work members, and local 'replace' targets. Other module dependencies are pinned by go.sum.
+func (p goListPackage) isLocal() bool {
+	switch {
+	case p.Standard:
+		return false
+	case p.Module == nil:
+		return true
+	case p.Module.Main:
+		return true
+	default:
+		return p.Module.Replace != nil && p.Module.Replace.Version == ""
+	}
+}
+
+// files returns the OS paths of this package's build inputs, including its module's go.mod and go.sum
+func (p goListPackage) files() []string {
+	var files []string
+	for _, names := range [][]string{
+		p.GoFiles, p.CgoFiles, p.CFiles, p.CXXFiles, p.MFiles, p.HFiles, p.FFiles,
+		p.SFiles, p.SwigFiles, p.SwigCXXFiles, p.SysoFiles, p.EmbedFiles,
+	} {
+		for _, name := range names {
+			files = append(files, filepath.Join(p.Dir, name))
+		}
+	}
+	if p.Module != nil && p.Module.GoMod != "" {
+		files = append(files, p.Module.GoMod, filepath.Join(filepath.Dir(p.Module.GoMod), "go.sum"))
+	}
+	return files
+}
+
+// fingerprintRecord is the fingerprint of a build, stored next to its binary.
+// Includes the size and modified time of each local input file, so later checks can skip 'go list' if none of them changed.
+type fingerprintRecord struct {
+	Fingerprint string            `json:"fingerprint"`
+	Package     string            `json:"package"`
+	Options     BuildOptions      `json:"options"`
+	Files       []fingerprintFile `json:"files"`
+}
+
+// fingerprintFile is the state of a build input file or directory when fingerprinted.
+// Directories containing build inputs or searched for go.work are included to notice new files.
+type fingerprintFile struct {
+	Path    string `json:"path"`
+	Size    int64  `json:"size,omitempty"`
+	ModTime int64  `json:"modTime,omitempty"`
+	Missing bool   `json:"missing,omitempty"`
+}
+
+// buildFingerprint returns a hash of pkg's build options and the local files 'go list -deps' reports for pkg.
+// Remote modules return an empty fingerprint, since go.sum already pins their contents.
+func (a App) buildFingerprint(ctx context.Context, pkg Package) (fingerprintRecord, error) {
+	workingDir, isLocal := a.packageFilePath(pkg)
+	if !isLocal {
+		return fingerprintRecord{}, nil
+	}
+
+	var stdout bytes.Buffer
+	buildOptions := pkg.BuildOptions
+	cmdArgs := append([]string{"list", "-deps", "-e", "-json=" + goListFields}, buildOptions.goFlags()...)
+	cmd := exec.CommandContext(ctx, "go", append(cmdArgs, ".")...)
+	cmd.Dir = workingDir
+	if len(buildOptions.Env) > 0 {
+		cmd.Env = buildOptions.environ()
+	}
+	cmd.Stdout = &stdout
+	if err := a.runCmd(cmd); err != nil {
+		return fingerprintRecord{}, errors.WithMessage(err, formatCmd(cmd))
+	}
+
+	fileSet := make(map[string]bool)
+	dirSet := make(map[string]bool)
+	var rootDir string
+	decoder := json.NewDecoder(&stdout)
+	for {
+		var listed goListPackage
+		err := decoder.Decode(&listed)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fingerprintRecord{}, errors.Wrapf(err, "invalid output from '%s'", formatCmd(cmd))
+		}
+		// 'go list -deps' prints pkg itself last
+		rootDir = listed.Dir
+		if listed.isLocal() {
+			dirSet[listed.Dir] = true
+			for _, file := range listed.files() {
+				fileSet[file] = true
+				dirSet[filepath.Dir(file)] = true
+			}
+		}
+	}
+	if rootDir != "" {
+		goWorkFiles, searchedDirs := a.findGoWork(rootDir, buildOptions)
+		for _, file := range goWorkFiles {
+			fileSet[file] = true
+		}
+		for _, dir := range searchedDirs {
+			dirSet[dir] = true
+		}
+	}
+
+	record := fingerprintRecord{
+		Package: pkg.Path,
+		Options: buildOptions,
+	}
+	for _, dir := range sortedKeys(dirSet) {
+		file, err := a.statFingerprintFile(dir)
+		if err != nil {
+			return fingerprintRecord{}, err
+		}
+		record.Files = append(record.Files, file)
+	}
+
+	fingerprint := sha256.New()
+	options, err := json.Marshal(buildOptions)
+	if err != nil {
+		return fingerprintRecord{}, err
+	}
+	fmt.Fprintf(fingerprint, "options %s\n", options)
+	for _, filePath := range sortedKeys(fileSet) {
+		// stat before reading, so an edit in between is noticed by the next check
+		file, err := a.statFingerprintFile(filePath)
+		if err != nil {
+			return fingerprintRecord{}, err
+		}
+		record.Files = append(record.Files, file)
+		if file.Missing {
+			// go.sum and go.work.sum are optional. Other missing files are reported by 'go install'.
+			continue
+		}
+		fsPath, err := a.fromOSPath(filePath)
+		if err != nil {
+			return fingerprintRecord{}, err
+		}
+		contents, err := hackpadfs.ReadFile(a.fs, fsPath)
+		if err != nil {
+			return fingerprintRecord{}, err
+		}
+		fmt.Fprintf(fingerprint, "%s %x\n", filePath, sha256.Sum256(contents))
+	}
+	record.Fingerprint = hex.EncodeToString(fingerprint.Sum(nil))
+	return record, nil
+}
+
+// findGoWork returns the OS paths of the go.work and go.work.sum files used to build in 'dir', like the go command does.
+// GOWORK selects the file if set, or disables workspaces if set to 'off'.
+// Otherwise, also returns the directories searched for go.work, so a new go.work in one of them is noticed.
+func (a App) findGoWork(dir string, buildOptions BuildOptions) (files, searchedDirs []string) {
+	goWork := a.getEnv(goWorkEnv)
+	for _, keyValue := range buildOptions.Env {
+		if key, value, _ := strings.Cut(keyValue, "="); key == goWorkEnv {
+			goWork = value
+		}
+	}
+	switch goWork {
+	case "off":
+		return nil, nil
+	case "":
+	default:
+		return []string{goWork, goWork + ".sum"}, nil
+	}
+
+	for {
+		goWork := filepath.Join(dir, "go.work")
+		// like the go command, skip directories which can't be read
+		if file, err := a.statFingerprintFile(goWork); err == nil {
+			searchedDirs = append(searchedDirs, dir)
+			if !file.Missing {
+				return []string{goWork, goWork + ".sum"}, searchedDirs
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, searchedDirs
+		}
+		dir = parent
+	}
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// statFingerprintFile returns the current state of the file or directory at OS path 'filePath'
+func (a App) statFingerprintFile(filePath string) (fingerprintFile, error) {
+	fsPath, err := a.fromOSPath(filePath)
+	if err != nil {
+		return fingerprintFile{}, err
+	}
+	info, err := hackpadfs.Stat(a.fs, fsPath)
+	if errors.Is(err, hackpadfs.ErrNotExist) {
+		return fingerprintFile{Path: filePath, Missing: true}, nil
+	}
+	if err != nil {
+		return fingerprintFile{}, err
+	}
+	return fingerprintFile{
+		Path:    filePath,
+		Size:    info.Size(),
+		ModTime: info.ModTime().UnixNano(),
+	}, nil
+}
+
+func (a App) fingerprintPath(name string) string {
+	return path.Join(a.packageInstallDir(name), fingerprintFileName)
+}
+
+// checkFingerprint returns true if the installed build of 'name' is out of date with pkg's local files or build options.
+// Remote modules are assumed up-to-date, since go.sum pins their contents.
+// Skips 'go list' and returns a nil fingerprint if none of the files recorded with the installed build changed.
+// Otherwise, returns the new fingerprint.
+func (a App) checkFingerprint(ctx context.Context, name string, pkg Package) (bool, *fingerprintRecord, error) {
+	if _, isLocal := a.packageFilePath(pkg); !isLocal {
+		return false, nil, nil
+	}
+	installed, err := a.readFingerprint(name)
+	if err != nil {
+		return false, nil, err
+	}
+	if installed != nil {
+		unchanged, err := a.filesUnchanged(*installed, pkg)
+		if err != nil || unchanged {
+			return false, nil, err
+		}
+	}
+	fingerprint, err := a.buildFingerprint(ctx, pkg)
+	if err != nil {
+		return false, nil, err
+	}
+	return installed == nil || installed.Fingerprint != fingerprint.Fingerprint, &fingerprint, nil
+}
+
+// filesUnchanged returns true if 'record' is for the same package and build options, and none of its files changed since it was recorded
+func (a App) filesUnchanged(record fingerprintRecord, pkg Package) (bool, error) {
+	if record.Package != pkg.Path || len(record.Files) == 0 {
+		return false, nil
+	}
+	recordOptions, err := json.Marshal(record.Options)
+	if err != nil {
+		return false, err
+	}
+	options, err := json.Marshal(pkg.BuildOptions)
+	if err != nil || !bytes.Equal(recordOptions, options) {
+		return false, err
+	}
+	for _, recorded := range record.Files {
+		current, err := a.statFingerprintFile(recorded.Path)
+		if err != nil || current != recorded {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// readFingerprint returns the fingerprint recorded with the installed build of 'name', or nil if there isn't a valid one
+func (a App) readFingerprint(name string) (*fingerprintRecord, error) {
+	contents, err := hackpadfs.ReadFile(a.fs, a.fingerprintPath(name))
+	if errors.Is(err, hackpadfs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var record fingerprintRecord
+	if err := json.Unmarshal(contents, &record); err != nil || record.Fingerprint == "" {
+		return nil, nil //nolint:nilerr // Invalid fingerprints are rebuilt and overwritten
+	}
+	return &record, nil
+}
+
+// writeFingerprint records the fingerprint of a successful build next to its binary
+func (a App) writeFingerprint(name string, record fingerprintRecord) error {
+	fingerprintPath := a.fingerprintPath(name)
+	if record.Fingerprint == "" {
+		err := hackpadfs.Remove(a.fs, fingerprintPath)
+		if errors.Is(err, hackpadfs.ErrNotExist) {
+			err = nil
+		}
+		return err
+	}
+	contents, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return hackpadfs.WriteFullFile(a.fs, fingerprintPath, contents, 0o600)
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hack-pad/hackpadfs"
+	osfs "github.com/hack-pad/hackpadfs/os"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:gochecknoglobals // Constant for tests. Slices can't be constants.
+var goListArgs = []string{"go", "list", "-deps", "-e", "-json=" + goListFields, "."}
+
+func writeGoList(t *testing.T, w io.Writer, pkgs ...goListPackage) {
+	t.Helper()
+	encoder := json.NewEncoder(w)
+	for _, pkg := range pkgs {
+		require.NoError(t, encoder.Encode(pkg))
+	}
+}
+
+func TestBuildFingerprint(t *testing.T) {
+	t.Parallel()
+
+	t.Run("remote module", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t, testAppOptions{})
+		fingerprint, err := app.buildFingerprint(context.Background(), Package{Path: thisPackage})
+		assert.NoError(t, err)
+		assert.Empty(t, fingerprint)
+	})
+
+	t.Run("skips standard library and remote dependencies", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t, testAppOptions{
+			runCmd: func(_ *TestApp, cmd *exec.Cmd) error {
+				assert.Equal(t, goListArgs, cmd.Args)
+				writeGoList(t, cmd.Stdout,
+					goListPackage{Dir: "/goroot/src/fmt", Standard: true, GoFiles: []string{"print.go"}},
+					goListPackage{
+						Dir:     "/gopath/pkg/mod/example.com/dep@v1.0.0",
+						GoFiles: []string{"dep.go"},
+						Module:  &goListModule{Version: "v1.0.0", GoMod: "/gopath/pkg/mod/cache/download/example.com/dep/@v/v1.0.0.mod"},
+					},
+				)
+				return nil
+			},
+		})
+		fingerprint, err := app.buildFingerprint(context.Background(), Package{Path: "/some/module"})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, fingerprint)
+	})
+
+	t.Run("build options", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t, testAppOptions{
+			runCmd: func(_ *TestApp, cmd *exec.Cmd) error {
+				if len(cmd.Args) > len(goListArgs) {
+					assert.Equal(t, append(goListArgs[:len(goListArgs)-1:len(goListArgs)-1], "-tags", "netgo", "."), cmd.Args)
+					assert.Equal(t, "0", fromEnv(cmd.Env)["CGO_ENABLED"])
+				}
+				return nil
+			},
+		})
+		pkg := Package{Path: "/some/module"}
+		fingerprint, err := app.buildFingerprint(context.Background(), pkg)
+		require.NoError(t, err)
+
+		pkg.BuildOptions = BuildOptions{Tags: "netgo", Env: []string{"CGO_ENABLED=0"}}
+		optionsFingerprint, err := app.buildFingerprint(context.Background(), pkg)
+		require.NoError(t, err)
+		assert.NotEqual(t, fingerprint.Fingerprint, optionsFingerprint.Fingerprint)
+	})
+
+	t.Run("invalid go list output", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t, testAppOptions{
+			runCmd: func(_ *TestApp, cmd *exec.Cmd) error {
+				_, err := cmd.Stdout.Write([]byte("{"))
+				return err
+			},
+		})
+		_, err := app.buildFingerprint(context.Background(), Package{Path: "/some/module"})
+		assert.EqualError(t, err, "invalid output from 'go list -deps -e -json="+goListFields+" .': unexpected EOF")
+	})
+
+	t.Run("go list", func(t *testing.T) {
+		t.Parallel()
+		if testing.Short() {
+			t.Skip("Skipping 'go list' in short mode")
+		}
+		dir := t.TempDir()
+		writeFile := func(name, contents string) {
+			t.Helper()
+			filePath := filepath.Join(dir, filepath.FromSlash(name))
+			require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0o700))
+			require.NoError(t, os.WriteFile(filePath, []byte(contents), 0o600))
+		}
+		writeFile("main/go.mod", `
+module example.com/main
+
+go 1.23
+
+require example.com/dep v0.0.0
+
+replace example.com/dep => ../dep
+`)
+		writeFile("main/main.go", `
+package main
+
+import (
+	"fmt"
+
+	"example.com/dep"
+)
+
+func main() { fmt.Println(dep.Hello) }
+`)
+		writeFile("dep/go.mod", "module example.com/dep\n\ngo 1.23\n")
+		writeFile("dep/dep.go", "package dep\n\nconst Hello = \"hello\"\n")
+
+		app := newTestApp(t, testAppOptions{
+			runCmd: func(_ *TestApp, cmd *exec.Cmd) error {
+				return cmd.Run()
+			},
+		})
+		app.fs = osfs.NewFS()
+		pkg := Package{
+			Path: filepath.Join(dir, "main"),
+			// go.work rejects some GOFLAGS, like -mod=mod
+			BuildOptions: BuildOptions{Env: []string{"GOFLAGS="}},
+		}
+		fingerprint := func() string {
+			t.Helper()
+			fingerprint, err := app.buildFingerprint(context.Background(), pkg)
+			require.NoError(t, err)
+			return fingerprint.Fingerprint
+		}
+
+		original := fingerprint()
+		assert.NotEmpty(t, original)
+
+		writeFile("main/notes.txt", "not part of the build")
+		writeFile("dep/dep_test.go", "package dep\n")
+		assert.Equal(t, original, fingerprint(), "Files outside the build should not change the fingerprint")
+
+		writeFile("dep/dep.go", "package dep\n\nconst Hello = \"hi\"\n")
+		changed := fingerprint()
+		assert.NotEqual(t, original, changed, "Local replace targets should change the fingerprint")
+
+		writeFile("main/go.sum", "")
+		withGoSum := fingerprint()
+		assert.NotEqual(t, changed, withGoSum, "go.sum should change the fingerprint")
+
+		writeFile("go.work", "go 1.23\n\nuse (\n\t./main\n\t./dep\n)\n")
+		withGoWork := fingerprint()
+		assert.NotEqual(t, withGoSum, withGoWork, "go.work should change the fingerprint")
+
+		writeFile("go.work.sum", "")
+		assert.NotEqual(t, withGoWork, fingerprint(), "go.work.sum should change the fingerprint")
+	})
+}
+
+func TestFindGoWork(t *testing.T) {
+	t.Parallel()
+	app := newTestApp(t, testAppOptions{})
+	require.NoError(t, hackpadfs.MkdirAll(app.fs, "work/module/cmd", 0o700))
+
+	files, searchedDirs := app.findGoWork("work/module/cmd", BuildOptions{})
+	assert.Empty(t, files)
+	assert.Equal(t, []string{"work/module/cmd", "work/module", "work", "."}, searchedDirs)
+
+	require.NoError(t, hackpadfs.WriteFullFile(app.fs, "work/go.work", []byte("go 1.23"), 0o600))
+	files, searchedDirs = app.findGoWork("work/module/cmd", BuildOptions{})
+	assert.Equal(t, []string{"work/go.work", "work/go.work.sum"}, files)
+	assert.Equal(t, []string{"work/module/cmd", "work/module", "work"}, searchedDirs)
+
+	files, searchedDirs = app.findGoWork("work/module/cmd", BuildOptions{Env: []string{"GOWORK=/other/go.work"}})
+	assert.Equal(t, []string{"/other/go.work", "/other/go.work.sum"}, files)
+	assert.Empty(t, searchedDirs)
+
+	files, searchedDirs = app.findGoWork("work/module/cmd", BuildOptions{Env: []string{"GOWORK=off"}})
+	assert.Empty(t, files)
+	assert.Empty(t, searchedDirs)
+}
+
+func TestCheckFingerprint(t *testing.T) {
+	t.Parallel()
+	const name = "foo"
+	var goListRuns int
+	app := newTestApp(t, testAppOptions{
+		runCmd: func(app *TestApp, cmd *exec.Cmd) error {
+			goListRuns++
+			writeGoList(t, cmd.Stdout, goListPackage{
+				Dir:     "some/module",
+				GoFiles: []string{"main.go"},
+				Module:  &goListModule{Main: true, GoMod: "some/module/go.mod"},
+			})
+			return nil
+		},
+	})
+	require.NoError(t, hackpadfs.MkdirAll(app.fs, "some/module", 0o700))
+	require.NoError(t, hackpadfs.WriteFullFile(app.fs, "some/module/go.mod", []byte("module example.com/module"), 0o600))
+	require.NoError(t, hackpadfs.WriteFullFile(app.fs, "some/module/main.go", []byte("package main"), 0o600))
+	require.NoError(t, hackpadfs.MkdirAll(app.fs, app.packageInstallDir(name), 0o700))
+	pkg := Package{Path: "/some/module"}
+	check := func() (bool, *fingerprintRecord) {
+		t.Helper()
+		shouldRebuild, fingerprint, err := app.checkFingerprint(context.Background(), name, pkg)
+		require.NoError(t, err)
+		return shouldRebuild, fingerprint
+	}
+
+	shouldRebuild, _, err := app.checkFingerprint(context.Background(), name, Package{Path: thisPackage})
+	assert.NoError(t, err)
+	assert.False(t, shouldRebuild, "Remote modules should not rebuild")
+	assert.Zero(t, goListRuns)
+
+	shouldRebuild, fingerprint := check()
+	assert.True(t, shouldRebuild, "Missing fingerprints should rebuild")
+	require.NotNil(t, fingerprint)
+	assert.Equal(t, 1, goListRuns)
+	require.NoError(t, app.writeFingerprint(name, *fingerprint))
+
+	shouldRebuild, fingerprint = check()
+	assert.False(t, shouldRebuild, "Unchanged files should not rebuild")
+	assert.Nil(t, fingerprint)
+	assert.Equal(t, 1, goListRuns, "Unchanged files should skip 'go list'")
+
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, hackpadfs.Chtimes(app.fs, "some/module/main.go", later, later))
+	shouldRebuild, fingerprint = check()
+	assert.False(t, shouldRebuild, "Touched files with the same contents should not rebuild")
+	require.NotNil(t, fingerprint)
+	assert.Equal(t, 2, goListRuns)
+	require.NoError(t, app.writeFingerprint(name, *fingerprint))
+
+	require.NoError(t, hackpadfs.WriteFullFile(app.fs, "some/module/main.go", []byte("package main\n\nfunc main() {}"), 0o600))
+	shouldRebuild, fingerprint = check()
+	assert.True(t, shouldRebuild, "Changed files should rebuild")
+	require.NotNil(t, fingerprint)
+	assert.Equal(t, 3, goListRuns)
+	require.NoError(t, app.writeFingerprint(name, *fingerprint))
+
+	require.NoError(t, hackpadfs.WriteFullFile(app.fs, "some/module/other.go", []byte("package main"), 0o600))
+	// mem FS doesn't update directory modified times, unlike OS file systems
+	require.NoError(t, hackpadfs.Chtimes(app.fs, "some/module", later, later))
+	shouldRebuild, fingerprint = check()
+	assert.False(t, shouldRebuild, "New files outside the build should not rebuild")
+	require.NotNil(t, fingerprint)
+	assert.Equal(t, 4, goListRuns, "New files should run 'go list'")
+	require.NoError(t, app.writeFingerprint(name, *fingerprint))
+
+	require.NoError(t, hackpadfs.WriteFullFile(app.fs, "some/go.work", []byte("go 1.23"), 0o600))
+	require.NoError(t, hackpadfs.Chtimes(app.fs, "some", later, later))
+	shouldRebuild, fingerprint = check()
+	assert.True(t, shouldRebuild, "New go.work files should rebuild")
+	require.NotNil(t, fingerprint)
+	assert.Equal(t, 5, goListRuns)
+
+	pkg.BuildOptions = BuildOptions{Tags: "netgo"}
+	require.NoError(t, app.writeFingerprint(name, fingerprintRecord{Fingerprint: "abc", Package: pkg.Path, Files: fingerprint.Files}))
+	shouldRebuild, _ = check()
+	assert.True(t, shouldRebuild, "Changed build options should rebuild")
+	assert.Equal(t, 6, goListRuns)
+
+	require.NoError(t, app.writeFingerprint(name, fingerprintRecord{}))
+	shouldRebuild, _ = check()
+	assert.True(t, shouldRebuild, "Removed fingerprints should rebuild")
+}
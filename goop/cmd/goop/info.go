@@ -1,15 +1,59 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"path"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/hack-pad/hackpadfs"
 	"github.com/spf13/cobra"
 )
 
+const (
+	kindLocal  = "local"
+	kindRemote = "remote"
+)
+
+var errInvalidScript = errors.New("invalid goop script, missing encoded name or package")
+
+// commandInfo describes an installed command for 'goop info'
+type commandInfo struct {
+	// Name is the command's name on the command-line
+	Name string `json:"name"`
+	// Package is the installed package path, like 'github.com/johnstarich/go/covet/cmd/covet' or '~/path/to/module'
+	Package string `json:"package"`
+	// Kind is either 'local' or 'remote'
+	Kind string `json:"kind"`
+	// Version is the pinned module version from the lock file. Empty for local modules.
+	Version string `json:"version,omitempty"`
+	// BuildOptions are the command's build tags, linker flags, and environment
+	BuildOptions
+	// BuildTime is when the binary was last built. Nil if not built yet.
+	BuildTime *time.Time `json:"buildTime,omitempty"`
+	// Size is the binary's size in bytes. Zero if not built yet.
+	Size int64 `json:"size,omitempty"`
+	// Stale is true if the next run will rebuild, or if the binary does not match the lock file. Nil if it could not be checked.
+	Stale *bool `json:"stale"`
+}
+
+// infoOutput is the JSON output of 'goop info --json'
+type infoOutput struct {
+	BinDir   string        `json:"binDir"`
+	Commands []commandInfo `json:"commands"`
+}
+
 func (a App) info(cmd *cobra.Command, _ []string) error {
+	printJSON, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return err
+	}
 	binDir, err := a.userBinDir()
 	if err != nil {
 		return err
@@ -18,20 +62,200 @@ func (a App) info(cmd *cobra.Command, _ []string) error {
 	if err != nil && !errors.Is(err, hackpadfs.ErrNotExist) {
 		return err
 	}
+	lock, err := a.readLockFile()
+	if err != nil {
+		return err
+	}
 
-	cmd.Printf("Installed: (%s)\n", binDir)
+	output := infoOutput{
+		BinDir:   binDir,
+		Commands: []commandInfo{},
+	}
 	for _, entry := range dirEntries {
-		isInstalled, err := isAppExecutable(a.fs, path.Join(binDir, entry.Name()))
+		scriptPath := path.Join(binDir, entry.Name())
+		isInstalled, err := isAppExecutable(a.fs, scriptPath)
 		if err != nil && !errors.Is(err, hackpadfs.ErrNotExist) {
 			return err
 		}
-		if isInstalled {
-			cmd.Println("-", entry.Name())
+		if !isInstalled {
+			continue
+		}
+		command, err := a.commandInfo(cmd, scriptPath, lock)
+		if errors.Is(err, errInvalidScript) {
+			fmt.Fprintf(a.errWriter, "WARNING: Skipping %q: %v\n", scriptPath, err)
+			continue
+		}
+		if err != nil {
+			return err
 		}
+		output.Commands = append(output.Commands, command)
+	}
+
+	if printJSON {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(output)
+	}
+	cmd.Printf("Installed: (%s)\n", binDir)
+	for _, command := range output.Commands {
+		cmd.Println("-", command.Name)
+		printCommandInfo(cmd.OutOrStdout(), command)
 	}
 	return nil
 }
 
+// commandInfo returns the details of the installed script at 'scriptPath'
+func (a App) commandInfo(cmd *cobra.Command, scriptPath string, lock lockFile) (commandInfo, error) {
+	contents, err := hackpadfs.ReadFile(a.fs, scriptPath)
+	if err != nil {
+		return commandInfo{}, err
+	}
+	name, pkg, err := a.decodeScript(contents)
+	if err != nil {
+		return commandInfo{}, err
+	}
+	info := commandInfo{
+		Name:         name,
+		Package:      pkg.Path,
+		Kind:         kindRemote,
+		BuildOptions: pkg.BuildOptions,
+	}
+	_, isLocal := a.packageFilePath(pkg)
+	if isLocal {
+		info.Kind = kindLocal
+	}
+	locked, isLocked := lock.Commands[name]
+	isLocked = isLocked && locked.Package == pkg.Path
+	if isLocked {
+		info.Version = locked.Version
+	}
+
+	binaryPath := a.installedBinaryPath(name, runtime.GOOS)
+	binaryInfo, err := hackpadfs.Stat(a.fs, binaryPath)
+	if errors.Is(err, hackpadfs.ErrNotExist) {
+		stale := true
+		info.Stale = &stale
+		return info, nil
+	}
+	if err != nil {
+		return commandInfo{}, err
+	}
+	buildTime := binaryInfo.ModTime()
+	info.BuildTime = &buildTime
+	info.Size = binaryInfo.Size()
+
+	stale, err := a.isStale(cmd.Context(), name, pkg, binaryPath, locked, isLocked)
+	if err != nil {
+		// staleness is unknown, but the rest of the info is still useful
+		fmt.Fprintf(a.errWriter, "WARNING: Failed to check if %q is up to date: %v\n", name, err)
+		return info, nil
+	}
+	info.Stale = &stale
+	return info, nil
+}
+
+// isStale returns true if the built command 'name' rebuilds on its next run, or if it does not match the lock file
+func (a App) isStale(ctx context.Context, name string, pkg Package, binaryPath string, locked lockedCommand, isLocked bool) (bool, error) {
+	if _, isLocal := a.packageFilePath(pkg); isLocal {
+		stale, _, err := a.checkFingerprint(ctx, name, pkg)
+		return stale, err
+	}
+	if !isLocked {
+		return false, nil
+	}
+	built, err := a.lockedBuild(pkg, binaryPath)
+	if err != nil {
+		return false, err
+	}
+	return locked.verify(name, built) != nil, nil
+}
+
+// decodeScript returns the command name and package, with build options, encoded in a goop script's shebang
+func (a App) decodeScript(contents []byte) (string, Package, error) {
+	shebang := string(contents)
+	if i := strings.IndexRune(shebang, '\n'); i != -1 {
+		shebang = shebang[:i]
+	}
+	args := strings.Fields(strings.TrimPrefix(shebang, makeShebang("goop exec")))
+	var name, packagePattern, encodedTags, encodedLDFlags string
+	var encodedEnv []string
+	for i := 0; i+1 < len(args); i += 2 {
+		value := args[i+1]
+		switch args[i] {
+		case "--encoded-name":
+			name, _ = base64DecodeString(value)
+		case "--encoded-package":
+			packagePattern, _ = base64DecodeString(value)
+		case "--encoded-tags":
+			encodedTags = value
+		case "--encoded-ldflags":
+			encodedLDFlags = value
+		case "--encoded-env":
+			encodedEnv = append(encodedEnv, value)
+		}
+	}
+	if name == "" || packagePattern == "" {
+		return "", Package{}, errInvalidScript
+	}
+	pkg, err := a.parsePackagePattern(packagePattern)
+	if err != nil {
+		return "", Package{}, err
+	}
+	pkg.BuildOptions, err = decodeBuildOptions(encodedTags, encodedLDFlags, encodedEnv)
+	return name, pkg, err
+}
+
+func printCommandInfo(w io.Writer, command commandInfo) {
+	table := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	row := func(key, value string) {
+		fmt.Fprintf(table, "    %s:\t%s\n", key, value)
+	}
+	row("Package", command.Package)
+	row("Kind", command.Kind)
+	if command.Version != "" {
+		row("Version", command.Version)
+	}
+	if command.Tags != "" {
+		row("Tags", command.Tags)
+	}
+	if command.LDFlags != "" {
+		row("LDFlags", command.LDFlags)
+	}
+	if len(command.Env) > 0 {
+		row("Env", strings.TrimSpace(formatEnv(command.Env)))
+	}
+	status := "up to date"
+	switch {
+	case command.BuildTime == nil:
+		status = "not built"
+	case command.Stale == nil:
+		status = "unknown"
+	case *command.Stale:
+		status = "out of date"
+	}
+	if command.BuildTime != nil {
+		row("Built", command.BuildTime.Format(time.RFC3339))
+		row("Size", formatSize(command.Size))
+	}
+	row("Status", status)
+	_ = table.Flush()
+}
+
+// formatSize returns a human-readable size for 'bytes', like '1.5 MiB'
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	size := float64(bytes) / unit
+	prefixes := "KMGTPE"
+	i := 0
+	for ; size >= unit && i < len(prefixes)-1; i++ {
+		size /= unit
+	}
+	return fmt.Sprintf("%.1f %ciB", size, prefixes[i])
+}
+
 func isAppExecutable(fs hackpadfs.FS, filePath string) (bool, error) {
 	f, err := fs.Open(filePath)
 	if err != nil {
@@ -1,10 +1,18 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/hack-pad/hackpadfs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestInfo(t *testing.T) {
@@ -40,9 +48,162 @@ func TestInfo(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, `Installed: (bin)
 - bar
+    Package: baz
+    Kind:    remote
+    Status:  not built
 `, app.Stdout())
 	})
 
+	t.Run("invalid script", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t, testAppOptions{})
+		assert.NoError(t, hackpadfs.Mkdir(app.fs, "bin", 0700))
+		assert.NoError(t, hackpadfs.WriteFullFile(app.fs, "bin/foo", []byte(makeShebang("goop exec --")), 0700))
+		err := app.Run([]string{"info"})
+		assert.NoError(t, err)
+		assert.Equal(t, `Installed: (bin)
+`, app.Stdout())
+		assert.Equal(t, `WARNING: Skipping "bin/foo": invalid goop script, missing encoded name or package
+`, app.Stderr())
+	})
+
+	t.Run("remote and local commands", func(t *testing.T) {
+		t.Parallel()
+		thisDir, err := os.Getwd()
+		require.NoError(t, err)
+		app := newTestApp(t, testAppOptions{
+			runCmd: func(_ *TestApp, cmd *exec.Cmd) error {
+				assert.Equal(t, goListArgs, cmd.Args)
+				assert.Equal(t, thisDir, cmd.Dir)
+				return nil
+			},
+			readBuildInfo: buildInfoVersions("v1.2.3"),
+		})
+		require.NoError(t, app.add("foo", Package{Path: thisPackage}))
+		require.NoError(t, app.add("bar", Package{Path: thisDir}))
+		require.NoError(t, app.add("baz", Package{Path: "example.com/baz"}))
+		writeTestLockFile(t, app, `{"commands": {"foo": {"package": "`+thisPackage+`", "module": "github.com/johnstarich/go/goop", "version": "v1.2.3", "sum": "h1:v1.2.3="}}}`)
+
+		buildTime := time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)
+		for name, size := range map[string]int{"foo": 1536, "bar": 10} {
+			binaryPath := app.installedBinaryPath(name, runtime.GOOS)
+			require.NoError(t, hackpadfs.MkdirAll(app.fs, path.Dir(binaryPath), 0o700))
+			require.NoError(t, hackpadfs.WriteFullFile(app.fs, binaryPath, make([]byte, size), 0o700))
+			require.NoError(t, hackpadfs.Chtimes(app.fs, binaryPath, buildTime, buildTime))
+		}
+		require.NoError(t, app.writeFingerprint("bar", fingerprintRecord{Fingerprint: "outdated"}))
+
+		require.NoError(t, app.Run([]string{"info"}))
+		assert.Equal(t, fmt.Sprintf(`Installed: (bin)
+- bar
+    Package: %s
+    Kind:    local
+    Built:   2024-01-02T03:04:05Z
+    Size:    10 B
+    Status:  out of date
+- baz
+    Package: example.com/baz
+    Kind:    remote
+    Status:  not built
+- foo
+    Package: github.com/johnstarich/go/goop/cmd/goop
+    Kind:    remote
+    Version: v1.2.3
+    Built:   2024-01-02T03:04:05Z
+    Size:    1.5 KiB
+    Status:  up to date
+`, thisDir), app.Stdout())
+	})
+
+	t.Run("failed staleness check", func(t *testing.T) {
+		t.Parallel()
+		thisDir, err := os.Getwd()
+		require.NoError(t, err)
+		app := newTestApp(t, testAppOptions{
+			runCmd: func(*TestApp, *exec.Cmd) error {
+				return errors.New("go list failed")
+			},
+		})
+		require.NoError(t, app.add("foo", Package{Path: thisDir}))
+		buildTime := time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)
+		binaryPath := app.installedBinaryPath("foo", runtime.GOOS)
+		require.NoError(t, hackpadfs.MkdirAll(app.fs, path.Dir(binaryPath), 0o700))
+		require.NoError(t, hackpadfs.WriteFullFile(app.fs, binaryPath, make([]byte, 10), 0o700))
+		require.NoError(t, hackpadfs.Chtimes(app.fs, binaryPath, buildTime, buildTime))
+
+		require.NoError(t, app.Run([]string{"info"}))
+		assert.Equal(t, fmt.Sprintf(`Installed: (bin)
+- foo
+    Package: %s
+    Kind:    local
+    Built:   2024-01-02T03:04:05Z
+    Size:    10 B
+    Status:  unknown
+`, thisDir), app.Stdout())
+		assert.Equal(t, `WARNING: Failed to check if "foo" is up to date: go list -deps -e -json=`+goListFields+` .: go list failed
+`, app.Stderr())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t, testAppOptions{
+			readBuildInfo: buildInfoVersions("v1.0.0"),
+		})
+		require.NoError(t, app.add("foo", Package{Path: thisPackage}))
+		writeTestLockFile(t, app, `{"commands": {"foo": {"package": "`+thisPackage+`", "module": "github.com/johnstarich/go/goop", "version": "v1.2.3", "sum": "h1:v1.2.3="}}}`)
+		buildTime := time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)
+		binaryPath := app.installedBinaryPath("foo", runtime.GOOS)
+		require.NoError(t, hackpadfs.MkdirAll(app.fs, path.Dir(binaryPath), 0o700))
+		require.NoError(t, hackpadfs.WriteFullFile(app.fs, binaryPath, make([]byte, 100), 0o700))
+		require.NoError(t, hackpadfs.Chtimes(app.fs, binaryPath, buildTime, buildTime))
+
+		require.NoError(t, app.Run([]string{"info", "--json"}))
+		assert.JSONEq(t, `{
+  "binDir": "bin",
+  "commands": [
+    {
+      "name": "foo",
+      "package": "github.com/johnstarich/go/goop/cmd/goop",
+      "kind": "remote",
+      "version": "v1.2.3",
+      "buildTime": "2024-01-02T03:04:05Z",
+      "size": 100,
+      "stale": true
+    }
+  ]
+}`, app.Stdout())
+	})
+
+	t.Run("build options", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t, testAppOptions{})
+		require.NoError(t, app.add("foo", Package{
+			Path: "example.com/foo",
+			BuildOptions: BuildOptions{
+				Tags:    "netgo",
+				LDFlags: "-s -w",
+				Env:     []string{"CGO_ENABLED=0", "GOPRIVATE=example.com"},
+			},
+		}))
+		require.NoError(t, app.Run([]string{"info"}))
+		assert.Equal(t, `Installed: (bin)
+- foo
+    Package: example.com/foo
+    Kind:    remote
+    Tags:    netgo
+    LDFlags: -s -w
+    Env:     CGO_ENABLED="0" GOPRIVATE="example.com"
+    Status:  not built
+`, app.Stdout())
+	})
+
+	t.Run("json none installed", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t, testAppOptions{})
+		require.NoError(t, app.Run([]string{"info", "--json"}))
+		assert.JSONEq(t, `{"binDir": "bin", "commands": []}`, app.Stdout())
+	})
+
 	t.Run("unrecognized dir", func(t *testing.T) {
 		t.Parallel()
 		app := newTestApp(t, testAppOptions{})
@@ -54,3 +215,24 @@ func TestInfo(t *testing.T) {
 `, app.Stdout())
 	})
 }
+
+func TestFormatSize(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		bytes  int64
+		expect string
+	}{
+		{bytes: 0, expect: "0 B"},
+		{bytes: 1023, expect: "1023 B"},
+		{bytes: 1024, expect: "1.0 KiB"},
+		{bytes: 1536, expect: "1.5 KiB"},
+		{bytes: 5 << 20, expect: "5.0 MiB"},
+		{bytes: 3 << 30, expect: "3.0 GiB"},
+	} {
+		tc := tc // enable parallel sub-tests
+		t.Run(tc.expect, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expect, formatSize(tc.bytes))
+		})
+	}
+}
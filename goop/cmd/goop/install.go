@@ -15,11 +15,31 @@ func (a App) install(cmd *cobra.Command, _ []string) error {
 	if err != nil {
 		return err
 	}
+	tags, err := cmd.Flags().GetString("tags")
+	if err != nil {
+		return err
+	}
+	ldflags, err := cmd.Flags().GetString("ldflags")
+	if err != nil {
+		return err
+	}
+	env, err := cmd.Flags().GetStringArray("env")
+	if err != nil {
+		return err
+	}
+	pkg.BuildOptions, err = parseBuildOptions(tags, ldflags, env)
+	if err != nil {
+		return err
+	}
 	if name == "" {
 		name = pkg.Name
 	}
-	if _, err := a.build(cmd.Context(), name, pkg, true); err != nil {
+	binaryPath, err := a.build(cmd.Context(), name, pkg, true)
+	if err != nil {
+		return err
+	}
+	if err := a.add(name, pkg); err != nil {
 		return err
 	}
-	return a.add(name, pkg)
+	return a.lock(name, pkg, binaryPath)
 }
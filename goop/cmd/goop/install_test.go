@@ -148,6 +148,86 @@ Build successful.
 		assert.Equal(t, "#!/usr/bin/env -S goop exec --encoded-name Z29vcA== --encoded-package Z2l0aHViLmNvbS9qb2huc3RhcmljaC9nby9nb29wL2NtZC9nb29w --\n", string(binFile))
 	})
 
+	t.Run("install with build options", func(t *testing.T) {
+		t.Parallel()
+		const name = "foo"
+		expectGoInstall := []string{
+			"go", "install",
+			"-tags", "netgo,osusergo",
+			"-ldflags", "-X main.version=v1.2.3 -s",
+			thisPackage + "@latest",
+		}
+		var commandsToRun [][]string
+		app := newTestApp(t, testAppOptions{
+			runCmd: func(app *TestApp, cmd *exec.Cmd) error {
+				commandsToRun = append(commandsToRun, cmd.Args)
+				switch cmd.Args[0] {
+				case "go":
+					env := fromEnv(cmd.Env)
+					assert.Equal(t, "0", env["CGO_ENABLED"])
+					assert.Equal(t, "github.com/org", env["GOPRIVATE"])
+					f, err := hackpadfs.Create(app.fs, path.Join(env["GOBIN"], name+systemExt(runtime.GOOS)))
+					require.NoError(t, err)
+					require.NoError(t, f.Close())
+				case name:
+				default:
+					t.Errorf("Unexpected command: %q", cmd.Args[0])
+				}
+				return nil
+			},
+		})
+		err := app.Run([]string{
+			"install", "--name", name, "-p", thisPackage,
+			"--tags", "netgo,osusergo",
+			"--ldflags", "-X main.version=v1.2.3 -s",
+			"--env", "CGO_ENABLED=0",
+			"--env", "GOPRIVATE=github.com/org",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, strings.TrimSpace(`
+Building "github.com/johnstarich/go/goop/cmd/goop"...
+Env: PWD="" GOBIN="cache/install/foo" CGO_ENABLED="0" GOPRIVATE="github.com/org"
+Running 'go install -tags netgo,osusergo -ldflags -X main.version=v1.2.3 -s github.com/johnstarich/go/goop/cmd/goop@latest'...
+Build successful.
+`), strings.TrimSpace(app.Stderr()))
+		assert.Equal(t, [][]string{expectGoInstall}, commandsToRun)
+
+		binFile, err := hackpadfs.ReadFile(app.fs, "bin/foo")
+		require.NoError(t, err)
+		script := string(binFile)
+		assert.Equal(t, "#!/usr/bin/env -S goop exec --encoded-name Zm9v --encoded-package Z2l0aHViLmNvbS9qb2huc3RhcmljaC9nby9nb29wL2NtZC9nb29w"+
+			" --encoded-tags bmV0Z28sb3N1c2VyZ28= --encoded-ldflags LVggbWFpbi52ZXJzaW9uPXYxLjIuMyAtcw=="+
+			" --encoded-env Q0dPX0VOQUJMRUQ9MA== --encoded-env R09QUklWQVRFPWdpdGh1Yi5jb20vb3Jn --\n", script)
+		lock, err := hackpadfs.ReadFile(app.fs, "bin/goop.lock")
+		require.NoError(t, err)
+		assert.Contains(t, string(lock), `"tags": "netgo,osusergo",
+      "ldflags": "-X main.version=v1.2.3 -s",
+      "env": [
+        "CGO_ENABLED=0",
+        "GOPRIVATE=github.com/org"
+      ]`)
+
+		// rebuild from the script's shebang args, at the locked version
+		commandsToRun = nil
+		require.NoError(t, hackpadfs.RemoveAll(app.fs, app.packageInstallDir(name)))
+		execArgs := strings.Fields(strings.TrimPrefix(script, makeShebang("goop")))
+		require.NoError(t, app.Run(append(execArgs, name)))
+		expectGoInstall[len(expectGoInstall)-1] = thisPackage + "@v1.0.0"
+		assert.Equal(t, [][]string{
+			expectGoInstall,
+			{name},
+		}, commandsToRun)
+	})
+
+	t.Run("install with invalid env", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t, testAppOptions{})
+		err := app.Run([]string{"install", "-p", thisPackage, "--env", "CGO_ENABLED"})
+		assert.EqualError(t, err, `invalid environment variable "CGO_ENABLED", must be formatted as KEY=VALUE`)
+		err = app.Run([]string{"install", "-p", thisPackage, "--env", "GOBIN=/tmp"})
+		assert.EqualError(t, err, "environment variable GOBIN is set by goop and cannot be changed")
+	})
+
 	t.Run("install also reinstalls", func(t *testing.T) {
 		t.Parallel()
 		var commandsToRun [][]string
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/hack-pad/hackpadfs"
+	"github.com/pkg/errors"
+)
+
+// lockFileName is the name of the lock file inside the bin directory
+const lockFileName = "goop.lock"
+
+// lockFile records the resolved module version and checksum of each installed command.
+// Lives in the bin directory, so sharing GOOP_BIN also shares the exact versions for 'goop sync' to install.
+type lockFile struct {
+	// Commands are keyed by command name
+	Commands map[string]lockedCommand `json:"commands"`
+}
+
+// lockedCommand is a command's package and its module's resolved version
+type lockedCommand struct {
+	// Package is the installed package path, like 'github.com/johnstarich/go/covet/cmd/covet' or '~/path/to/module'
+	Package string `json:"package"`
+	// Module is the package's module path. Empty for local modules.
+	Module string `json:"module,omitempty"`
+	// Version is the resolved module version, like 'v1.2.3'. Empty for local modules.
+	Version string `json:"version,omitempty"`
+	// Sum is the module's go.sum hash, like 'h1:abc123='. Empty for local modules.
+	Sum string `json:"sum,omitempty"`
+	// BuildOptions mirror the build tags, linker flags, and environment encoded in the command's script, so 'goop sync' can reinstall it
+	BuildOptions
+}
+
+// packagePattern returns the package pattern to install this exact version, like 'github.com/org/repo/cmd/foo@v1.2.3'
+func (c lockedCommand) packagePattern() string {
+	if c.Version == "" {
+		return c.Package
+	}
+	return c.Package + "@" + c.Version
+}
+
+func (a App) lockFilePath() (string, error) {
+	binDir, err := a.userBinDir()
+	return path.Join(binDir, lockFileName), err
+}
+
+// readLockFile returns the current lock file, or an empty one if it does not exist yet
+func (a App) readLockFile() (lockFile, error) {
+	lock := lockFile{Commands: make(map[string]lockedCommand)}
+	lockPath, err := a.lockFilePath()
+	if err != nil {
+		return lockFile{}, err
+	}
+	contents, err := hackpadfs.ReadFile(a.fs, lockPath)
+	if errors.Is(err, hackpadfs.ErrNotExist) {
+		return lock, nil
+	}
+	if err != nil {
+		return lockFile{}, err
+	}
+	if err := json.Unmarshal(contents, &lock); err != nil {
+		return lockFile{}, errors.Wrapf(err, "invalid lock file %q", lockPath)
+	}
+	if lock.Commands == nil {
+		lock.Commands = make(map[string]lockedCommand)
+	}
+	return lock, nil
+}
+
+// writeLockFile writes 'lock' to the bin directory. Removes the lock file if no commands are locked.
+func (a App) writeLockFile(lock lockFile) error {
+	lockPath, err := a.lockFilePath()
+	if err != nil {
+		return err
+	}
+	if len(lock.Commands) == 0 {
+		err := hackpadfs.Remove(a.fs, lockPath)
+		if errors.Is(err, hackpadfs.ErrNotExist) {
+			err = nil
+		}
+		return err
+	}
+	contents, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := hackpadfs.MkdirAll(a.fs, path.Dir(lockPath), binPermission); err != nil {
+		return err
+	}
+	const lockPermission = 0o600
+	return hackpadfs.WriteFullFile(a.fs, lockPath, append(contents, '\n'), lockPermission)
+}
+
+// lockedBuild returns the locked command for 'pkg', read from the built binary at 'binaryPath'.
+// Remote modules include the module's resolved version and checksum.
+func (a App) lockedBuild(pkg Package, binaryPath string) (lockedCommand, error) {
+	locked := lockedCommand{Package: pkg.Path, BuildOptions: pkg.BuildOptions}
+	if _, isLocal := a.packageFilePath(pkg); isLocal {
+		return locked, nil
+	}
+	binaryOSPath, err := a.toOSPath(binaryPath)
+	if err != nil {
+		return lockedCommand{}, err
+	}
+	info, err := a.readBuildInfo(binaryOSPath)
+	if err != nil {
+		return lockedCommand{}, errors.Wrapf(err, "failed to read module version from %q", binaryOSPath)
+	}
+	locked.Module = info.Main.Path
+	locked.Version = info.Main.Version
+	locked.Sum = info.Main.Sum
+	return locked, nil
+}
+
+// lock records the installed version of command 'name' in the lock file
+func (a App) lock(name string, pkg Package, binaryPath string) error {
+	locked, err := a.lockedBuild(pkg, binaryPath)
+	if err != nil {
+		return err
+	}
+	lock, err := a.readLockFile()
+	if err != nil {
+		return err
+	}
+	lock.Commands[name] = locked
+	return a.writeLockFile(lock)
+}
+
+// unlock removes command 'name' from the lock file
+func (a App) unlock(name string) error {
+	lock, err := a.readLockFile()
+	if err != nil {
+		return err
+	}
+	if _, exists := lock.Commands[name]; !exists {
+		return nil
+	}
+	delete(lock.Commands, name)
+	return a.writeLockFile(lock)
+}
+
+// lockedPackage returns 'pkg' pinned to the version in the lock file, if 'pkg' is a remote module without a version
+func (a App) lockedPackage(name string, pkg Package) (Package, error) {
+	if pkg.ModuleVersion != "" {
+		return pkg, nil
+	}
+	lock, err := a.readLockFile()
+	if err != nil {
+		return Package{}, err
+	}
+	if locked, ok := lock.Commands[name]; ok && locked.Package == pkg.Path {
+		pkg.ModuleVersion = locked.Version
+	}
+	return pkg, nil
+}
+
+// sortedNames returns the lock file's command names in sorted order
+func (l lockFile) sortedNames() []string {
+	names := make([]string, 0, len(l.Commands))
+	for name := range l.Commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// verify returns an error if 'built' does not match this locked command's version and checksum
+func (c lockedCommand) verify(name string, built lockedCommand) error {
+	if c.Version != built.Version || c.Sum != built.Sum {
+		return fmt.Errorf("installed %q does not match the lock file: expected %s %s, got %s %s", name, c.Version, c.Sum, built.Version, built.Sum)
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"os/exec"
+	"path"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/hack-pad/hackpadfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// installTestBinary returns a runCmd which fakes 'go install' by creating the binary in GOBIN
+func installTestBinary(t *testing.T, name string, commandsToRun *[][]string) func(*TestApp, *exec.Cmd) error {
+	t.Helper()
+	return func(app *TestApp, cmd *exec.Cmd) error {
+		*commandsToRun = append(*commandsToRun, cmd.Args)
+		f, err := hackpadfs.Create(app.fs, path.Join(fromEnv(cmd.Env)["GOBIN"], name+systemExt(runtime.GOOS)))
+		require.NoError(t, err)
+		return f.Close()
+	}
+}
+
+func buildInfoVersions(versions ...string) func(string) (*debug.BuildInfo, error) {
+	return func(string) (*debug.BuildInfo, error) {
+		version := versions[0]
+		if len(versions) > 1 {
+			versions = versions[1:]
+		}
+		return &debug.BuildInfo{Main: debug.Module{
+			Path:    "github.com/johnstarich/go/goop",
+			Version: version,
+			Sum:     "h1:" + version + "=",
+		}}, nil
+	}
+}
+
+func writeTestLockFile(t *testing.T, app *TestApp, contents string) {
+	t.Helper()
+	require.NoError(t, hackpadfs.MkdirAll(app.fs, "bin", 0o700))
+	require.NoError(t, hackpadfs.WriteFullFile(app.fs, path.Join("bin", lockFileName), []byte(strings.TrimSpace(contents)), 0o600))
+}
+
+func TestInstallLock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("remote module", func(t *testing.T) {
+		t.Parallel()
+		var commandsToRun [][]string
+		app := newTestApp(t, testAppOptions{
+			runCmd:        installTestBinary(t, "foo", &commandsToRun),
+			readBuildInfo: buildInfoVersions("v1.2.3"),
+		})
+		writeTestLockFile(t, app, `{"commands": {"bar": {"package": "example.com/bar"}}}`)
+		require.NoError(t, app.Run([]string{"install", "--name", "foo", "-p", thisPackage}))
+
+		lock, err := hackpadfs.ReadFile(app.fs, "bin/goop.lock")
+		require.NoError(t, err)
+		assert.Equal(t, `{
+  "commands": {
+    "bar": {
+      "package": "example.com/bar"
+    },
+    "foo": {
+      "package": "github.com/johnstarich/go/goop/cmd/goop",
+      "module": "github.com/johnstarich/go/goop",
+      "version": "v1.2.3",
+      "sum": "h1:v1.2.3="
+    }
+  }
+}
+`, string(lock))
+
+		require.NoError(t, app.Run([]string{"rm", "--name", "foo"}))
+		lock, err = hackpadfs.ReadFile(app.fs, "bin/goop.lock")
+		require.NoError(t, err)
+		assert.NotContains(t, string(lock), `"foo"`)
+	})
+
+	t.Run("local module", func(t *testing.T) {
+		t.Parallel()
+		var commandsToRun [][]string
+		app := newTestApp(t, testAppOptions{
+			runCmd: installTestBinary(t, "foo", &commandsToRun),
+		})
+		require.NoError(t, app.Run([]string{"install", "--name", "foo", "-p", "/path/to/foo"}))
+
+		lock, err := hackpadfs.ReadFile(app.fs, "bin/goop.lock")
+		require.NoError(t, err)
+		assert.Equal(t, `{
+  "commands": {
+    "foo": {
+      "package": "/path/to/foo"
+    }
+  }
+}
+`, string(lock))
+	})
+
+	t.Run("invalid lock file", func(t *testing.T) {
+		t.Parallel()
+		var commandsToRun [][]string
+		app := newTestApp(t, testAppOptions{
+			runCmd: installTestBinary(t, "foo", &commandsToRun),
+		})
+		writeTestLockFile(t, app, `not json`)
+		err := app.Run([]string{"install", "--name", "foo", "-p", thisPackage})
+		assert.EqualError(t, err, `invalid lock file "bin/goop.lock": invalid character 'o' in literal null (expecting 'u')`)
+	})
+}
+
+func TestSync(t *testing.T) {
+	t.Parallel()
+	const lockContents = `
+{
+  "commands": {
+    "foo": {
+      "package": "github.com/johnstarich/go/goop/cmd/goop",
+      "module": "github.com/johnstarich/go/goop",
+      "version": "v1.2.3",
+      "sum": "h1:v1.2.3="
+    }
+  }
+}
+`
+
+	t.Run("install locked version", func(t *testing.T) {
+		t.Parallel()
+		var commandsToRun [][]string
+		app := newTestApp(t, testAppOptions{
+			runCmd:        installTestBinary(t, "foo", &commandsToRun),
+			readBuildInfo: buildInfoVersions("v1.2.3"),
+		})
+		writeTestLockFile(t, app, lockContents)
+		require.NoError(t, app.Run([]string{"sync"}))
+
+		assert.Equal(t, [][]string{
+			{"go", "install", thisPackage + "@v1.2.3"},
+		}, commandsToRun)
+		assert.Equal(t, "Synced 1 commands from bin/goop.lock\n", app.Stdout())
+		binFile, err := hackpadfs.ReadFile(app.fs, "bin/foo")
+		assert.NoError(t, err)
+		assert.Equal(t, "#!/usr/bin/env -S goop exec --encoded-name Zm9v --encoded-package Z2l0aHViLmNvbS9qb2huc3RhcmljaC9nby9nb29wL2NtZC9nb29w --\n", string(binFile))
+	})
+
+	t.Run("skip installed locked version", func(t *testing.T) {
+		t.Parallel()
+		var commandsToRun [][]string
+		app := newTestApp(t, testAppOptions{
+			runCmd:        installTestBinary(t, "foo", &commandsToRun),
+			readBuildInfo: buildInfoVersions("v1.2.3"),
+		})
+		writeTestLockFile(t, app, lockContents)
+		require.NoError(t, hackpadfs.MkdirAll(app.fs, app.packageInstallDir("foo"), 0o700))
+		require.NoError(t, hackpadfs.WriteFullFile(app.fs, path.Join(app.packageInstallDir("foo"), "foo"+systemExt(runtime.GOOS)), nil, 0o700))
+		require.NoError(t, app.Run([]string{"sync"}))
+
+		assert.Empty(t, commandsToRun)
+		assert.Empty(t, app.Stderr())
+	})
+
+	t.Run("rebuild different version", func(t *testing.T) {
+		t.Parallel()
+		var commandsToRun [][]string
+		app := newTestApp(t, testAppOptions{
+			runCmd:        installTestBinary(t, "foo", &commandsToRun),
+			readBuildInfo: buildInfoVersions("v1.0.0", "v1.2.3"),
+		})
+		writeTestLockFile(t, app, lockContents)
+		require.NoError(t, hackpadfs.MkdirAll(app.fs, app.packageInstallDir("foo"), 0o700))
+		require.NoError(t, hackpadfs.WriteFullFile(app.fs, path.Join(app.packageInstallDir("foo"), "foo"+systemExt(runtime.GOOS)), nil, 0o700))
+		require.NoError(t, app.Run([]string{"sync"}))
+
+		assert.Equal(t, [][]string{
+			{"go", "install", thisPackage + "@v1.2.3"},
+		}, commandsToRun)
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		t.Parallel()
+		var commandsToRun [][]string
+		app := newTestApp(t, testAppOptions{
+			runCmd: installTestBinary(t, "foo", &commandsToRun),
+			readBuildInfo: func(string) (*debug.BuildInfo, error) {
+				return &debug.BuildInfo{Main: debug.Module{Version: "v1.2.3", Sum: "h1:other="}}, nil
+			},
+		})
+		writeTestLockFile(t, app, lockContents)
+		err := app.Run([]string{"sync"})
+		assert.EqualError(t, err, `failed to sync "foo": installed "foo" does not match the lock file: expected v1.2.3 h1:v1.2.3=, got v1.2.3 h1:other=`)
+	})
+
+	t.Run("install with locked build options", func(t *testing.T) {
+		t.Parallel()
+		var commandsToRun [][]string
+		app := newTestApp(t, testAppOptions{
+			runCmd:        installTestBinary(t, "foo", &commandsToRun),
+			readBuildInfo: buildInfoVersions("v1.2.3"),
+		})
+		writeTestLockFile(t, app, `{"commands": {"foo": {
+			"package": "`+thisPackage+`", "module": "github.com/johnstarich/go/goop", "version": "v1.2.3", "sum": "h1:v1.2.3=",
+			"tags": "netgo", "env": ["CGO_ENABLED=0"]
+		}}}`)
+		require.NoError(t, app.Run([]string{"sync"}))
+
+		assert.Equal(t, [][]string{
+			{"go", "install", "-tags", "netgo", thisPackage + "@v1.2.3"},
+		}, commandsToRun)
+		binFile, err := hackpadfs.ReadFile(app.fs, "bin/foo")
+		assert.NoError(t, err)
+		assert.Equal(t, "#!/usr/bin/env -S goop exec --encoded-name Zm9v --encoded-package Z2l0aHViLmNvbS9qb2huc3RhcmljaC9nby9nb29wL2NtZC9nb29w --encoded-tags bmV0Z28= --encoded-env Q0dPX0VOQUJMRUQ9MA== --\n", string(binFile))
+	})
+
+	t.Run("no lock file", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t, testAppOptions{})
+		require.NoError(t, app.Run([]string{"sync"}))
+		assert.Equal(t, "Synced 0 commands from bin/goop.lock\n", app.Stdout())
+	})
+}
+
+func TestExecLockedVersion(t *testing.T) {
+	t.Parallel()
+	var commandsToRun [][]string
+	app := newTestApp(t, testAppOptions{
+		runCmd: func(app *TestApp, cmd *exec.Cmd) error {
+			if cmd.Args[0] == "go" {
+				return installTestBinary(t, "foo", &commandsToRun)(app, cmd)
+			}
+			commandsToRun = append(commandsToRun, cmd.Args)
+			return nil
+		},
+	})
+	writeTestLockFile(t, app, `{"commands": {"foo": {"package": "`+thisPackage+`", "version": "v1.2.3"}}}`)
+	err := app.Run([]string{"exec", "--encoded-name", base64EncodeString("foo"), "--encoded-package", base64EncodeString(thisPackage)})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"go", "install", thisPackage + "@v1.2.3"},
+		{"foo" + systemExt(runtime.GOOS)},
+	}, commandsToRun)
+}
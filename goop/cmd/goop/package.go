@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -12,6 +13,17 @@ type Package struct {
 	Path          string
 	Name          string
 	ModuleVersion string
+	BuildOptions  BuildOptions
+}
+
+// BuildOptions are a command's extra 'go install' options, applied on every rebuild
+type BuildOptions struct {
+	// Tags is a comma-separated list of build tags, like 'netgo,osusergo'
+	Tags string `json:"tags,omitempty"`
+	// LDFlags are the linker flags, like '-X main.version=v1.2.3'
+	LDFlags string `json:"ldflags,omitempty"`
+	// Env are extra environment variables formatted as KEY=VALUE, like 'CGO_ENABLED=0'
+	Env []string `json:"env,omitempty"`
 }
 
 const (
@@ -69,3 +81,39 @@ func (a App) packageInstallPaths(pkg Package) (workingDir, installPattern string
 	}
 	return
 }
+
+// parseBuildOptions validates and returns build options from user input
+func parseBuildOptions(tags, ldflags string, env []string) (BuildOptions, error) {
+	for _, keyValue := range env {
+		key, _, hasValue := strings.Cut(keyValue, "=")
+		if !hasValue || key == "" {
+			return BuildOptions{}, fmt.Errorf("invalid environment variable %q, must be formatted as KEY=VALUE", keyValue)
+		}
+		switch key {
+		case "GOARCH", "GOBIN", "GOOS":
+			return BuildOptions{}, fmt.Errorf("environment variable %s is set by goop and cannot be changed", key)
+		}
+	}
+	return BuildOptions{
+		Tags:    tags,
+		LDFlags: ldflags,
+		Env:     env,
+	}, nil
+}
+
+// goFlags returns the build flags for 'go' commands, like '-tags netgo'
+func (o BuildOptions) goFlags() []string {
+	var flags []string
+	if o.Tags != "" {
+		flags = append(flags, "-tags", o.Tags)
+	}
+	if o.LDFlags != "" {
+		flags = append(flags, "-ldflags", o.LDFlags)
+	}
+	return flags
+}
+
+// environ returns the current environment with these options' environment variables added
+func (o BuildOptions) environ() []string {
+	return append(os.Environ(), o.Env...)
+}
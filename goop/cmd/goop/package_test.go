@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func rootFilePath() string {
@@ -125,3 +126,65 @@ func TestPackageFilePath(t *testing.T) {
 		})
 	}
 }
+
+func TestParseBuildOptions(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		description   string
+		tags          string
+		ldflags       string
+		env           []string
+		expectOptions BuildOptions
+		expectFlags   []string
+		expectErr     string
+	}{
+		{
+			description: "no options",
+		},
+		{
+			description:   "tags",
+			tags:          "netgo,osusergo",
+			expectOptions: BuildOptions{Tags: "netgo,osusergo"},
+			expectFlags:   []string{"-tags", "netgo,osusergo"},
+		},
+		{
+			description:   "ldflags",
+			ldflags:       "-s -w",
+			expectOptions: BuildOptions{LDFlags: "-s -w"},
+			expectFlags:   []string{"-ldflags", "-s -w"},
+		},
+		{
+			description:   "env",
+			env:           []string{"CGO_ENABLED=0", "GOFLAGS=-mod=mod"},
+			expectOptions: BuildOptions{Env: []string{"CGO_ENABLED=0", "GOFLAGS=-mod=mod"}},
+		},
+		{
+			description: "env missing value",
+			env:         []string{"CGO_ENABLED"},
+			expectErr:   `invalid environment variable "CGO_ENABLED", must be formatted as KEY=VALUE`,
+		},
+		{
+			description: "env missing key",
+			env:         []string{"=0"},
+			expectErr:   `invalid environment variable "=0", must be formatted as KEY=VALUE`,
+		},
+		{
+			description: "env reserved key",
+			env:         []string{"GOOS=plan9"},
+			expectErr:   "environment variable GOOS is set by goop and cannot be changed",
+		},
+	} {
+		tc := tc // enable parallel sub-tests
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			options, err := parseBuildOptions(tc.tags, tc.ldflags, tc.env)
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectOptions, options)
+			assert.Equal(t, tc.expectFlags, options.goFlags())
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+const defaultGoProxy = "https://proxy.golang.org,direct"
+
+// errProxyNotFound is returned when a proxy does not have a module, so the next proxy in GOPROXY should be tried
+var errProxyNotFound = errors.New("module not found")
+
+// moduleProxy queries module versions using the GOPROXY protocol. See 'go help goproxy'.
+type moduleProxy struct {
+	// urls are the proxy URLs from GOPROXY, in order
+	urls []string
+	// fallbackOnError is true for each proxy which falls back to the next on any error, from a '|' separator
+	fallbackOnError []bool
+	client          *http.Client
+}
+
+// newModuleProxy returns a moduleProxy for the GOPROXY environment variable.
+// Supports http(s):// and file:// URLs. Entries like 'direct' and 'off' are skipped, since they do not list versions.
+func (a App) newModuleProxy() (moduleProxy, error) {
+	goProxy := a.getEnv("GOPROXY")
+	if goProxy == "" {
+		goProxy = defaultGoProxy
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.RegisterProtocol("file", http.NewFileTransport(http.Dir("/")))
+	proxy := moduleProxy{
+		client: &http.Client{Transport: transport},
+	}
+	for goProxy != "" {
+		entry := goProxy
+		fallbackOnError := false
+		if i := strings.IndexAny(goProxy, ",|"); i != -1 {
+			fallbackOnError = goProxy[i] == '|'
+			entry, goProxy = goProxy[:i], goProxy[i+1:]
+		} else {
+			goProxy = ""
+		}
+		if entry == "direct" || entry == "off" || entry == "" {
+			continue
+		}
+		proxy.urls = append(proxy.urls, strings.TrimSuffix(entry, "/"))
+		proxy.fallbackOnError = append(proxy.fallbackOnError, fallbackOnError)
+	}
+	if len(proxy.urls) == 0 {
+		return moduleProxy{}, errors.Errorf("a module proxy is required to find new versions, but GOPROXY has none: %q", a.getEnv("GOPROXY"))
+	}
+	return proxy, nil
+}
+
+// Versions returns the module's released versions, sorted from oldest to newest.
+// If the module has no tagged versions, returns its latest pseudo-version.
+func (p moduleProxy) Versions(ctx context.Context, modulePath string) ([]string, error) {
+	escapedPath, err := module.EscapePath(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	var lastErr error
+	for i, url := range p.urls {
+		versions, err := p.versions(ctx, url, escapedPath)
+		if err == nil {
+			return versions, nil
+		}
+		lastErr = err
+		if !errors.Is(err, errProxyNotFound) && !p.fallbackOnError[i] {
+			break
+		}
+	}
+	return nil, errors.WithMessagef(lastErr, "failed to find versions of module %q", modulePath)
+}
+
+func (p moduleProxy) versions(ctx context.Context, proxyURL, escapedPath string) ([]string, error) {
+	body, err := p.get(ctx, proxyURL+"/"+escapedPath+"/@v/list")
+	if err != nil {
+		return nil, err
+	}
+	var versions []string
+	scanner := bufio.NewScanner(strings.NewReader(string(body)))
+	for scanner.Scan() {
+		if version := strings.TrimSpace(scanner.Text()); semver.IsValid(version) {
+			versions = append(versions, version)
+		}
+	}
+	if len(versions) > 0 {
+		semver.Sort(versions)
+		return versions, nil
+	}
+
+	body, err = p.get(ctx, proxyURL+"/"+escapedPath+"/@latest")
+	if err != nil {
+		return nil, err
+	}
+	var latest struct {
+		Version string
+	}
+	if err := json.Unmarshal(body, &latest); err != nil {
+		return nil, err
+	}
+	return []string{latest.Version}, nil
+}
+
+func (p moduleProxy) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, errProxyNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("GET %s: %s: %s", url, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestProxy returns a GOPROXY server with files at the given paths, like '/example.com/foo/@v/list'
+func newTestProxy(t *testing.T, files map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contents, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(contents))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestModuleProxy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("list versions", func(t *testing.T) {
+		t.Parallel()
+		server := newTestProxy(t, map[string]string{
+			"/github.com/!burnt!sushi/toml/@v/list": "v1.2.0\nv1.10.0\nv1.3.0\n",
+		})
+		app := newTestApp(t, testAppOptions{})
+		app.getEnv = func(string) string { return server.URL }
+		proxy, err := app.newModuleProxy()
+		require.NoError(t, err)
+		versions, err := proxy.Versions(context.Background(), "github.com/BurntSushi/toml")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"v1.2.0", "v1.3.0", "v1.10.0"}, versions)
+	})
+
+	t.Run("latest pseudo-version", func(t *testing.T) {
+		t.Parallel()
+		server := newTestProxy(t, map[string]string{
+			"/example.com/foo/@v/list": "",
+			"/example.com/foo/@latest": `{"Version": "v0.0.0-20240102030405-abcdefabcdef"}`,
+		})
+		app := newTestApp(t, testAppOptions{})
+		app.getEnv = func(string) string { return server.URL }
+		proxy, err := app.newModuleProxy()
+		require.NoError(t, err)
+		versions, err := proxy.Versions(context.Background(), "example.com/foo")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"v0.0.0-20240102030405-abcdefabcdef"}, versions)
+	})
+
+	t.Run("fall back to next proxy", func(t *testing.T) {
+		t.Parallel()
+		emptyServer := newTestProxy(t, nil)
+		server := newTestProxy(t, map[string]string{
+			"/example.com/foo/@v/list": "v1.0.0\n",
+		})
+		app := newTestApp(t, testAppOptions{})
+		app.getEnv = func(string) string { return emptyServer.URL + ",direct," + server.URL + "/" }
+		proxy, err := app.newModuleProxy()
+		require.NoError(t, err)
+		versions, err := proxy.Versions(context.Background(), "example.com/foo")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"v1.0.0"}, versions)
+
+		_, err = proxy.Versions(context.Background(), "example.com/bar")
+		assert.EqualError(t, err, `failed to find versions of module "example.com/bar": module not found`)
+	})
+
+	t.Run("file proxy", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		listPath := filepath.Join(dir, "example.com", "foo", "@v", "list")
+		require.NoError(t, os.MkdirAll(filepath.Dir(listPath), 0o700))
+		require.NoError(t, os.WriteFile(listPath, []byte("v1.0.0\nv1.1.0\n"), 0o600))
+		app := newTestApp(t, testAppOptions{})
+		app.getEnv = func(string) string { return "file://" + filepath.ToSlash(dir) }
+		proxy, err := app.newModuleProxy()
+		require.NoError(t, err)
+		versions, err := proxy.Versions(context.Background(), "example.com/foo")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"v1.0.0", "v1.1.0"}, versions)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		t.Parallel()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "uh oh", http.StatusInternalServerError)
+		}))
+		t.Cleanup(server.Close)
+		app := newTestApp(t, testAppOptions{})
+		app.getEnv = func(string) string { return server.URL }
+		proxy, err := app.newModuleProxy()
+		require.NoError(t, err)
+		_, err = proxy.Versions(context.Background(), "example.com/foo")
+		require.Error(t, err)
+		assert.True(t, strings.HasSuffix(err.Error(), "/example.com/foo/@v/list: 500 Internal Server Error: uh oh"), err.Error())
+	})
+
+	t.Run("no proxy", func(t *testing.T) {
+		t.Parallel()
+		app := newTestApp(t, testAppOptions{})
+		app.getEnv = func(string) string { return "direct" }
+		_, err := app.newModuleProxy()
+		assert.EqualError(t, err, `a module proxy is required to find new versions, but GOPROXY has none: "direct"`)
+	})
+}
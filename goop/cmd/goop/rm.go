@@ -29,5 +29,5 @@ func (a App) rm(cmd *cobra.Command, _ []string) error {
 	if err := hackpadfs.RemoveAll(a.fs, installDir); err != nil {
 		return err
 	}
-	return nil
+	return a.unlock(name)
 }
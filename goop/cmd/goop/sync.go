@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func (a App) sync(cmd *cobra.Command, _ []string) error {
+	lock, err := a.readLockFile()
+	if err != nil {
+		return err
+	}
+	names := lock.sortedNames()
+	for _, name := range names {
+		if err := a.syncCommand(cmd.Context(), name, lock.Commands[name]); err != nil {
+			return errors.WithMessagef(err, "failed to sync %q", name)
+		}
+	}
+	lockPath, err := a.lockFilePath()
+	if err != nil {
+		return err
+	}
+	cmd.Printf("Synced %d commands from %s\n", len(names), lockPath)
+	return nil
+}
+
+// syncCommand installs command 'name' at its locked version, if not already installed.
+// Remote modules are rebuilt when the installed version or checksum differs from the lock file.
+func (a App) syncCommand(ctx context.Context, name string, locked lockedCommand) error {
+	pkg, err := a.parsePackagePattern(locked.packagePattern())
+	if err != nil {
+		return err
+	}
+	pkg.BuildOptions = locked.BuildOptions
+	binaryPath, err := a.build(ctx, name, pkg, false)
+	if err != nil {
+		return err
+	}
+	if _, isLocal := a.packageFilePath(pkg); !isLocal {
+		built, err := a.lockedBuild(pkg, binaryPath)
+		if err != nil {
+			return err
+		}
+		if locked.verify(name, built) != nil {
+			binaryPath, err = a.build(ctx, name, pkg, true)
+			if err != nil {
+				return err
+			}
+			built, err = a.lockedBuild(pkg, binaryPath)
+			if err != nil {
+				return err
+			}
+			if err := locked.verify(name, built); err != nil {
+				return err
+			}
+		}
+	}
+	return a.add(name, pkg)
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/semver"
+)
+
+// remoteCommand is an installed remote module command and its newest available version
+type remoteCommand struct {
+	Name     string
+	Locked   lockedCommand
+	Newest   string
+	Outdated bool
+}
+
+// remoteCommands returns the locked remote commands named 'names', or all of them if 'names' is empty.
+// Local modules are skipped since they rebuild automatically. Naming a local module returns an error.
+func (a App) remoteCommands(names []string) ([]remoteCommand, error) {
+	lock, err := a.readLockFile()
+	if err != nil {
+		return nil, err
+	}
+	explicitNames := len(names) > 0
+	if !explicitNames {
+		names = lock.sortedNames()
+	}
+	var commands []remoteCommand
+	for _, name := range names {
+		locked, ok := lock.Commands[name]
+		if !ok {
+			return nil, errors.Errorf("command %q is not in the lock file, install it with 'goop install' first", name)
+		}
+		_, isLocal := a.packageFilePath(Package{Path: locked.Package})
+		switch {
+		case isLocal && explicitNames:
+			return nil, errors.Errorf("command %q is a local module, which rebuilds automatically when run", name)
+		case isLocal:
+			continue
+		case locked.Module == "" && explicitNames:
+			return nil, errors.Errorf("module for command %q is unknown, reinstall it with 'goop install' to record it", name)
+		case locked.Module == "":
+			fmt.Fprintf(a.errWriter, "WARNING: Skipping %q, its module is unknown. Reinstall it with 'goop install' to record it.\n", name)
+			continue
+		}
+		commands = append(commands, remoteCommand{Name: name, Locked: locked})
+	}
+	return commands, nil
+}
+
+// findNewestVersions sets each command's newest version allowed by 'constraint'
+func (a App) findNewestVersions(ctx context.Context, commands []remoteCommand, constraint versionConstraint) error {
+	proxy, err := a.newModuleProxy()
+	if err != nil {
+		return err
+	}
+	for i := range commands {
+		versions, err := proxy.Versions(ctx, commands[i].Locked.Module)
+		if err != nil {
+			return err
+		}
+		newest, ok := newestVersion(versions, constraint)
+		commands[i].Newest = newest
+		commands[i].Outdated = ok && semver.Compare(newest, commands[i].Locked.Version) > 0
+	}
+	return nil
+}
+
+func (a App) outdated(cmd *cobra.Command, _ []string) error {
+	commands, err := a.remoteCommands(nil)
+	if err != nil {
+		return err
+	}
+	if err := a.findNewestVersions(cmd.Context(), commands, versionConstraint{}); err != nil {
+		return err
+	}
+	const tabPadding = 2
+	table := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, tabPadding, ' ', 0)
+	outdatedCount := 0
+	for _, command := range commands {
+		if !command.Outdated {
+			continue
+		}
+		if outdatedCount == 0 {
+			fmt.Fprintln(table, "NAME\tMODULE\tCURRENT\tLATEST")
+		}
+		outdatedCount++
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", command.Name, command.Locked.Module, command.Locked.Version, command.Newest)
+	}
+	if outdatedCount == 0 {
+		cmd.Println("All remote commands are up to date.")
+		return nil
+	}
+	return table.Flush()
+}
+
+func (a App) upgrade(cmd *cobra.Command, _ []string) error {
+	name, err := cmd.Flags().GetString("name")
+	if err != nil {
+		return err
+	}
+	constraintStr, err := cmd.Flags().GetString("constraint")
+	if err != nil {
+		return err
+	}
+	constraint, err := parseVersionConstraint(constraintStr)
+	if err != nil {
+		return err
+	}
+	var names []string
+	if name != "" {
+		names = []string{name}
+	}
+	commands, err := a.remoteCommands(names)
+	if err != nil {
+		return err
+	}
+	if err := a.findNewestVersions(cmd.Context(), commands, constraint); err != nil {
+		return err
+	}
+	for _, command := range commands {
+		if !command.Outdated {
+			cmd.Printf("%s is up to date at %s\n", command.Name, command.Locked.Version)
+			continue
+		}
+		pkg, err := a.parsePackagePattern(command.Locked.Package + "@" + command.Newest)
+		if err != nil {
+			return err
+		}
+		pkg.BuildOptions = command.Locked.BuildOptions
+		binaryPath, err := a.build(cmd.Context(), command.Name, pkg, true)
+		if err != nil {
+			return err
+		}
+		if err := a.add(command.Name, pkg); err != nil {
+			return err
+		}
+		if err := a.lock(command.Name, pkg, binaryPath); err != nil {
+			return err
+		}
+		cmd.Printf("Upgraded %s from %s to %s\n", command.Name, command.Locked.Version, command.Newest)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hack-pad/hackpadfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const upgradeLockContents = `
+{
+  "commands": {
+    "foo": {
+      "package": "github.com/johnstarich/go/goop/cmd/goop",
+      "module": "github.com/johnstarich/go/goop",
+      "version": "v1.2.3",
+      "sum": "h1:v1.2.3="
+    },
+    "local": {
+      "package": "/path/to/local"
+    }
+  }
+}
+`
+
+func newUpgradeTestApp(t *testing.T, commandsToRun *[][]string, builtVersion string) *TestApp {
+	t.Helper()
+	server := newTestProxy(t, map[string]string{
+		"/github.com/johnstarich/go/goop/@v/list": "v1.2.3\nv1.3.0\nv2.0.0-rc.1\nv1.2.4\n",
+	})
+	app := newTestApp(t, testAppOptions{
+		runCmd:        installTestBinary(t, "foo", commandsToRun),
+		readBuildInfo: buildInfoVersions(builtVersion),
+	})
+	app.getEnv = func(key string) string {
+		if key == "GOPROXY" {
+			return server.URL
+		}
+		return ""
+	}
+	writeTestLockFile(t, app, upgradeLockContents)
+	return app
+}
+
+func TestOutdated(t *testing.T) {
+	t.Parallel()
+
+	t.Run("outdated", func(t *testing.T) {
+		t.Parallel()
+		var commandsToRun [][]string
+		app := newUpgradeTestApp(t, &commandsToRun, "")
+		require.NoError(t, app.Run([]string{"outdated"}))
+		assert.Equal(t, `NAME  MODULE                          CURRENT  LATEST
+foo   github.com/johnstarich/go/goop  v1.2.3   v1.3.0
+`, app.Stdout())
+		assert.Empty(t, commandsToRun)
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		t.Parallel()
+		var commandsToRun [][]string
+		app := newUpgradeTestApp(t, &commandsToRun, "")
+		writeTestLockFile(t, app, `{"commands": {"foo": {"package": "`+thisPackage+`", "module": "github.com/johnstarich/go/goop", "version": "v1.3.0"}}}`)
+		require.NoError(t, app.Run([]string{"outdated"}))
+		assert.Equal(t, "All remote commands are up to date.\n", app.Stdout())
+	})
+}
+
+func TestUpgrade(t *testing.T) {
+	t.Parallel()
+
+	t.Run("upgrade all", func(t *testing.T) {
+		t.Parallel()
+		var commandsToRun [][]string
+		app := newUpgradeTestApp(t, &commandsToRun, "v1.3.0")
+		require.NoError(t, app.Run([]string{"upgrade"}))
+		assert.Equal(t, "Upgraded foo from v1.2.3 to v1.3.0\n", app.Stdout())
+		assert.Equal(t, [][]string{
+			{"go", "install", thisPackage + "@v1.3.0"},
+		}, commandsToRun)
+
+		lock, err := hackpadfs.ReadFile(app.fs, "bin/goop.lock")
+		require.NoError(t, err)
+		assert.Contains(t, string(lock), `"version": "v1.3.0"`)
+	})
+
+	t.Run("upgrade with constraint", func(t *testing.T) {
+		t.Parallel()
+		var commandsToRun [][]string
+		app := newUpgradeTestApp(t, &commandsToRun, "v1.2.4")
+		require.NoError(t, app.Run([]string{"upgrade", "--name", "foo", "--constraint", "~v1.2"}))
+		assert.Equal(t, "Upgraded foo from v1.2.3 to v1.2.4\n", app.Stdout())
+		assert.Equal(t, [][]string{
+			{"go", "install", thisPackage + "@v1.2.4"},
+		}, commandsToRun)
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		t.Parallel()
+		var commandsToRun [][]string
+		app := newUpgradeTestApp(t, &commandsToRun, "")
+		require.NoError(t, app.Run([]string{"upgrade", "--constraint", "=v1.2.3"}))
+		assert.Equal(t, "foo is up to date at v1.2.3\n", app.Stdout())
+		assert.Empty(t, commandsToRun)
+	})
+
+	t.Run("invalid constraint", func(t *testing.T) {
+		t.Parallel()
+		var commandsToRun [][]string
+		app := newUpgradeTestApp(t, &commandsToRun, "")
+		err := app.Run([]string{"upgrade", "--constraint", "^latest"})
+		assert.EqualError(t, err, `invalid version "vlatest" in constraint "^latest"`)
+	})
+
+	t.Run("local module", func(t *testing.T) {
+		t.Parallel()
+		var commandsToRun [][]string
+		app := newUpgradeTestApp(t, &commandsToRun, "")
+		err := app.Run([]string{"upgrade", "--name", "local"})
+		assert.EqualError(t, err, `command "local" is a local module, which rebuilds automatically when run`)
+	})
+
+	t.Run("not installed", func(t *testing.T) {
+		t.Parallel()
+		var commandsToRun [][]string
+		app := newUpgradeTestApp(t, &commandsToRun, "")
+		err := app.Run([]string{"upgrade", "--name", "bar"})
+		assert.EqualError(t, err, `command "bar" is not in the lock file, install it with 'goop install' first`)
+	})
+}